@@ -0,0 +1,848 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+)
+
+const header = "// Code generated by gsrpc-gen. DO NOT EDIT.\n\n"
+
+// imports lists the packages generated code may refer to, by their name
+var imports = map[string]string{
+	"big":   "math/big",
+	"fmt":   "fmt",
+	"scale": "github.com/Phala-Network/go-substrate-rpc-client/v3/scale",
+	"state": "github.com/Phala-Network/go-substrate-rpc-client/v3/rpc/state",
+	"types": "github.com/Phala-Network/go-substrate-rpc-client/v3/types",
+}
+
+// knownTypes maps the paths of registry types to the types package types they are encoded like
+var knownTypes = map[string]string{
+	"sp_core::crypto::AccountId32":           "types.AccountID",
+	"primitive_types::H256":                  "types.H256",
+	"sp_runtime::multiaddress::MultiAddress": "types.MultiAddress",
+}
+
+var primitiveTypes = map[types.Si0TypeDefPrimitive]string{
+	types.IsBool: "types.Bool", types.IsChar: "types.U32", types.IsStr: "types.Text",
+	types.IsU8: "types.U8", types.IsU16: "types.U16", types.IsU32: "types.U32", types.IsU64: "types.U64",
+	types.IsU128: "types.U128", types.IsU256: "types.U256",
+	types.IsI8: "types.I8", types.IsI16: "types.I16", types.IsI32: "types.I32", types.IsI64: "types.I64",
+	types.IsI128: "types.I128", types.IsI256: "types.I256",
+}
+
+var primitiveSizes = map[types.Si0TypeDefPrimitive]int{
+	types.IsU8: 1, types.IsU16: 2, types.IsU32: 4, types.IsU64: 8,
+}
+
+// generator generates Go bindings for the pallets of V14 or V15 metadata: a struct for every event, a constructor
+// for every call, key builders and getters for every storage item and a getter for every constant. Composite and
+// variant types of the registry used by the pallets are generated as Go types.
+type generator struct {
+	pkg     string
+	lookup  map[int64]*types.Si1Type
+	pallets []types.PalletMetadataV14
+	// callType is the registry type of the outer call, generated as types.Call
+	callType    int64
+	hasCallType bool
+
+	names  map[int64]string
+	needed map[int64]bool
+	queue  []int64
+	// bitVecs maps the size of the store type of bit sequences to the name of their generated type
+	bitVecs map[int]string
+}
+
+func newGenerator(meta *types.Metadata, pkg string) (*generator, error) {
+	g := &generator{
+		pkg:     pkg,
+		needed:  map[int64]bool{},
+		bitVecs: map[int]string{},
+	}
+
+	switch meta.Version {
+	case 14:
+		m := meta.AsMetadataV14
+		g.lookup = registryLookup(m.Lookup)
+		g.pallets = m.Pallets
+		if typ, ok := g.lookup[m.Extrinsic.Type.Int64()]; ok {
+			for _, param := range typ.Params {
+				if param.Name == "Call" && param.HasType {
+					g.callType, g.hasCallType = param.Type.Int64(), true
+				}
+			}
+		}
+	case 15:
+		m := meta.AsMetadataV15
+		g.lookup = registryLookup(m.Lookup)
+		for _, p := range m.Pallets {
+			g.pallets = append(g.pallets, types.PalletMetadataV14{
+				Name:       p.Name,
+				HasStorage: p.HasStorage,
+				Storage:    p.Storage,
+				HasCalls:   p.HasCalls,
+				Calls:      p.Calls,
+				HasEvents:  p.HasEvents,
+				Events:     p.Events,
+				Constants:  p.Constants,
+				HasErrors:  p.HasErrors,
+				Errors:     p.Errors,
+				Index:      p.Index,
+			})
+		}
+		g.callType, g.hasCallType = m.Extrinsic.CallType.Int64(), true
+	default:
+		return nil, fmt.Errorf("generating code for metadata version %v is not supported", meta.Version)
+	}
+
+	g.names = g.typeNames()
+	return g, nil
+}
+
+func registryLookup(registry types.PortableRegistryV14) map[int64]*types.Si1Type {
+	lookup := make(map[int64]*types.Si1Type, len(registry.Types))
+	for _, t := range registry.Types {
+		typ := t.Type
+		lookup[t.ID.Int64()] = &typ
+	}
+	return lookup
+}
+
+// generate returns the generated Go files by their names
+func generate(meta *types.Metadata, pkg string) (map[string][]byte, error) {
+	g, err := newGenerator(meta, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{}
+	// palletFiles are the names of the pallets by the names of their files
+	palletFiles := map[string]types.Text{}
+	var events bytes.Buffer
+	fmt.Fprintf(&events, "// EventRecords holds the events of a block, to be passed to "+
+		"types.EventRecordsRaw.DecodeEventRecords\ntype EventRecords struct {\n")
+
+	for _, p := range g.pallets {
+		var buf bytes.Buffer
+		g.writeEvents(&buf, &events, p)
+		g.writeCalls(&buf, p)
+		g.writeStorage(&buf, p)
+		g.writeConstants(&buf, p)
+		if buf.Len() == 0 {
+			continue
+		}
+
+		src, err := g.format(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("pallet %v: %v", p.Name, err)
+		}
+		name := palletFileName(p.Name)
+		if other, ok := palletFiles[name]; ok {
+			return nil, fmt.Errorf("pallets %v and %v are both generated into %v", other, p.Name, name)
+		}
+		palletFiles[name] = p.Name
+		files[name] = src
+	}
+
+	events.WriteString("}\n")
+	src, err := g.format(events.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	files["events.go"] = src
+
+	var buf bytes.Buffer
+	g.writeTypes(&buf)
+	src, err = g.format(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	files["types.go"] = src
+
+	return files, nil
+}
+
+// format adds the package clause and the imports used by the declarations in body and formats the result
+func (g *generator) format(body []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", append([]byte("package "+g.pkg+"\n"), body...), 0)
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid code: %v", err)
+	}
+
+	used := map[string]bool{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && imports[id.Name] != "" {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+	var names []string
+	for name := range used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	fmt.Fprintf(&buf, "package %v\n\n", g.pkg)
+	if len(names) > 0 {
+		buf.WriteString("import (\n")
+		for _, name := range names {
+			fmt.Fprintf(&buf, "%q\n", imports[name])
+		}
+		buf.WriteString(")\n\n")
+	}
+	buf.Write(body)
+
+	return format.Source(buf.Bytes())
+}
+
+func (g *generator) writeEvents(buf, records *bytes.Buffer, p types.PalletMetadataV14) {
+	if !p.HasEvents {
+		return
+	}
+	for _, v := range g.variants(p.Events.Type) {
+		name := fmt.Sprintf("Event%v%v", exportedName(string(p.Name)), exportedName(string(v.Name)))
+		writeDocs(buf, fmt.Sprintf("%v is emitted by %v.%v", name, p.Name, v.Name), v.Docs)
+		fmt.Fprintf(buf, "type %v struct {\nPhase types.Phase\n", name)
+		for _, f := range g.fields(v.Fields, -1) {
+			if f.name == "Phase" || f.name == "Topics" {
+				f.name += "Field"
+			}
+			fmt.Fprintf(buf, "%v %v\n", f.name, f.typ)
+		}
+		buf.WriteString("Topics []types.Hash\n}\n\n")
+
+		fmt.Fprintf(records, "%v_%v []%v\n", p.Name, v.Name, name)
+	}
+}
+
+func (g *generator) writeCalls(buf *bytes.Buffer, p types.PalletMetadataV14) {
+	if !p.HasCalls {
+		return
+	}
+	for _, v := range g.variants(p.Calls.Type) {
+		name := fmt.Sprintf("New%v%vCall", exportedName(string(p.Name)), exportedName(string(v.Name)))
+		writeDocs(buf, fmt.Sprintf("%v creates a call to %v.%v", name, p.Name, v.Name), v.Docs)
+
+		var params, args []string
+		for _, f := range g.fields(v.Fields, -1) {
+			params = append(params, fmt.Sprintf("%v %v", f.param, f.typ))
+			args = append(args, f.param)
+		}
+		fmt.Fprintf(buf, "func %v(meta *types.Metadata%v) (types.Call, error) {\n", name, joinWithPrefix(params))
+		fmt.Fprintf(buf, "return types.NewCall(meta, %q%v)\n}\n\n", p.Name+"."+v.Name, joinWithPrefix(args))
+	}
+}
+
+func (g *generator) writeStorage(buf *bytes.Buffer, p types.PalletMetadataV14) {
+	if !p.HasStorage {
+		return
+	}
+	for _, s := range p.Storage.Items {
+		name := exportedName(string(p.Name)) + exportedName(string(s.Name))
+
+		var keys []string
+		var valueType string
+		if s.Type.IsPlainType {
+			valueType = g.goType(s.Type.AsPlainType.Int64())
+		} else {
+			valueType = g.goType(s.Type.AsMap.Value.Int64())
+			keys = g.storageKeyTypes(s.Type.AsMap)
+		}
+
+		var params, args []string
+		for i, k := range keys {
+			params = append(params, fmt.Sprintf("key%v %v", i, k))
+			args = append(args, fmt.Sprintf("key%v", i))
+		}
+
+		writeDocs(buf, fmt.Sprintf("%vKey returns the storage key of %v.%v", name, p.Storage.Prefix, s.Name), nil)
+		fmt.Fprintf(buf, "func %vKey(meta *types.Metadata%v) (types.StorageKey, error) {\n", name,
+			joinWithPrefix(params))
+		if len(keys) > 0 {
+			fmt.Fprintf(buf, "var args [][]byte\nfor _, key := range []interface{}{%v} {\n", strings.Join(args, ", "))
+			buf.WriteString("arg, err := types.EncodeToBytes(key)\nif err != nil {\nreturn nil, err\n}\n")
+			buf.WriteString("args = append(args, arg)\n}\n")
+			fmt.Fprintf(buf, "return types.CreateStorageKey(meta, %q, %q, args...)\n}\n\n", p.Storage.Prefix, s.Name)
+		} else {
+			fmt.Fprintf(buf, "return types.CreateStorageKey(meta, %q, %q)\n}\n\n", p.Storage.Prefix, s.Name)
+		}
+
+		for _, at := range []bool{false, true} {
+			getter, blockParam, blockArg := "Get"+name, "", ""
+			if at {
+				getter, blockParam, blockArg = getter+"At", ", blockHash types.Hash", ", blockHash"
+			}
+			writeDocs(buf, fmt.Sprintf("%v reads %v.%v", getter, p.Storage.Prefix, s.Name), s.Documentation)
+			fmt.Fprintf(buf, "func %v(s *state.State, meta *types.Metadata%v%v) (value %v, ok bool, err error) {\n",
+				getter, joinWithPrefix(params), blockParam, valueType)
+			fmt.Fprintf(buf, "key, err := %vKey(meta%v)\nif err != nil {\nreturn value, false, err\n}\n", name,
+				joinWithPrefix(args))
+			if at {
+				buf.WriteString("ok, err = s.GetStorage(key, &value" + blockArg + ")\nreturn value, ok, err\n}\n\n")
+			} else {
+				buf.WriteString("ok, err = s.GetStorageLatest(key, &value)\nreturn value, ok, err\n}\n\n")
+			}
+		}
+	}
+}
+
+// storageKeyTypes returns the Go types of the keys of a storage map, one per hasher
+func (g *generator) storageKeyTypes(m types.MapTypeV14) []string {
+	if len(m.Hashers) > 1 {
+		if typ, ok := g.lookup[m.Key.Int64()]; ok && typ.Def.IsTuple && len(typ.Def.Tuple) == len(m.Hashers) {
+			keys := make([]string, len(typ.Def.Tuple))
+			for i, id := range typ.Def.Tuple {
+				keys[i] = g.goType(id.Int64())
+			}
+			return keys
+		}
+	}
+	return []string{g.goType(m.Key.Int64())}
+}
+
+func (g *generator) writeConstants(buf *bytes.Buffer, p types.PalletMetadataV14) {
+	for _, c := range p.Constants {
+		name := fmt.Sprintf("Get%v%vConstant", exportedName(string(p.Name)), exportedName(string(c.Name)))
+		writeDocs(buf, fmt.Sprintf("%v decodes the constant %v.%v", name, p.Name, c.Name), c.Docs)
+		fmt.Fprintf(buf, "func %v(meta *types.Metadata) (value %v, err error) {\n", name, g.goType(c.Type.Int64()))
+		fmt.Fprintf(buf, "bz, err := meta.FindConstantValue(%q, %q)\nif err != nil {\nreturn value, err\n}\n",
+			p.Name, c.Name)
+		buf.WriteString("err = types.DecodeFromBytes(bz, &value)\nreturn value, err\n}\n\n")
+	}
+}
+
+// writeTypes writes the composite and variant types that are used by the generated pallet bindings, including the
+// types that are only used by the written types
+func (g *generator) writeTypes(buf *bytes.Buffer) {
+	var written []int64
+	var bodies = map[int64]*bytes.Buffer{}
+	for len(g.queue) > 0 {
+		id := g.queue[0]
+		g.queue = g.queue[1:]
+
+		var body bytes.Buffer
+		g.writeType(&body, id)
+		bodies[id] = &body
+		written = append(written, id)
+	}
+
+	sort.Slice(written, func(i, j int) bool { return g.names[written[i]] < g.names[written[j]] })
+	for _, id := range written {
+		buf.Write(bodies[id].Bytes())
+	}
+
+	var sizes []int
+	for size := range g.bitVecs {
+		sizes = append(sizes, size)
+	}
+	sort.Ints(sizes)
+	for _, size := range sizes {
+		writeBitVec(buf, g.bitVecs[size], size)
+	}
+}
+
+func (g *generator) writeType(buf *bytes.Buffer, id int64) {
+	typ := g.lookup[id]
+	name := g.names[id]
+	path := joinPath(typ.Path)
+
+	if typ.Def.IsComposite {
+		fields := g.fields(typ.Def.Composite.Fields, id)
+		writeDocs(buf, fmt.Sprintf("%v is generated from %v", name, path), typ.Docs)
+		fmt.Fprintf(buf, "type %v struct {\n", name)
+		custom := false
+		for _, f := range fields {
+			fmt.Fprintf(buf, "%v %v\n", f.name, f.typ)
+			custom = custom || f.pointer
+		}
+		buf.WriteString("}\n\n")
+
+		// fields referring back to the type are pointers, which the scale package does not decode
+		if custom {
+			fmt.Fprintf(buf, "func (m *%v) Decode(decoder scale.Decoder) error {\n", name)
+			writeFieldsDecode(buf, "m", fields, false)
+			buf.WriteString("return nil\n}\n\n")
+			fmt.Fprintf(buf, "func (m %v) Encode(encoder scale.Encoder) error {\n", name)
+			writeFieldsEncode(buf, "m", fields, false)
+			buf.WriteString("return nil\n}\n\n")
+		}
+		return
+	}
+
+	variants := typ.Def.Variant.Variants
+	writeDocs(buf, fmt.Sprintf("%v is generated from the enum %v", name, path), typ.Docs)
+	fmt.Fprintf(buf, "type %v struct {\n", name)
+	variantFields := make([][]field, len(variants))
+	for i, v := range variants {
+		vn := exportedName(string(v.Name))
+		fmt.Fprintf(buf, "Is%v bool\n", vn)
+		variantFields[i] = g.fields(v.Fields, id)
+		switch len(variantFields[i]) {
+		case 0:
+		case 1:
+			fmt.Fprintf(buf, "As%v %v\n", vn, variantFields[i][0].typ)
+		default:
+			fmt.Fprintf(buf, "As%v struct {\n", vn)
+			for _, f := range variantFields[i] {
+				fmt.Fprintf(buf, "%v %v\n", f.name, f.typ)
+			}
+			buf.WriteString("}\n")
+		}
+	}
+	buf.WriteString("}\n\n")
+
+	if len(variants) == 0 {
+		// enums without variants, such as Void, can't be instantiated
+		fmt.Fprintf(buf, "func (m *%v) Decode(decoder scale.Decoder) error {\n", name)
+		fmt.Fprintf(buf, "return fmt.Errorf(\"%v has no variants\")\n}\n\n", name)
+		fmt.Fprintf(buf, "func (m %v) Encode(encoder scale.Encoder) error {\n", name)
+		fmt.Fprintf(buf, "return fmt.Errorf(\"%v has no variants\")\n}\n\n", name)
+		return
+	}
+
+	fmt.Fprintf(buf, "func (m *%v) Decode(decoder scale.Decoder) error {\n", name)
+	buf.WriteString("b, err := decoder.ReadOneByte()\nif err != nil {\nreturn err\n}\nswitch b {\n")
+	for i, v := range variants {
+		vn := exportedName(string(v.Name))
+		fmt.Fprintf(buf, "case %v:\nm.Is%v = true\n", v.Index, vn)
+		writeFieldsDecode(buf, "m.As"+vn, variantFields[i], len(variantFields[i]) == 1)
+	}
+	fmt.Fprintf(buf, "default:\nreturn fmt.Errorf(\"%v does not support variant %%v\", b)\n}\nreturn nil\n}\n\n", name)
+
+	fmt.Fprintf(buf, "func (m %v) Encode(encoder scale.Encoder) error {\nswitch {\n", name)
+	for i, v := range variants {
+		vn := exportedName(string(v.Name))
+		fmt.Fprintf(buf, "case m.Is%v:\nerr := encoder.PushByte(%v)\nif err != nil {\nreturn err\n}\n", vn, v.Index)
+		writeFieldsEncode(buf, "m.As"+vn, variantFields[i], len(variantFields[i]) == 1)
+	}
+	fmt.Fprintf(buf, "default:\nreturn fmt.Errorf(\"%v has no variant set\")\n}\nreturn nil\n}\n\n", name)
+}
+
+// writeFieldsDecode decodes the fields of base one by one, allocating pointers. The single field of a variant is
+// decoded into base itself.
+func writeFieldsDecode(buf *bytes.Buffer, base string, fields []field, single bool) {
+	for _, f := range fields {
+		target := base + "." + f.name
+		if single {
+			target = base
+		}
+		if f.pointer {
+			fmt.Fprintf(buf, "%v = new(%v)\n", target, strings.TrimPrefix(f.typ, "*"))
+			fmt.Fprintf(buf, "if err := decoder.Decode(%v); err != nil {\nreturn err\n}\n", target)
+		} else {
+			fmt.Fprintf(buf, "if err := decoder.Decode(&%v); err != nil {\nreturn err\n}\n", target)
+		}
+	}
+}
+
+func writeFieldsEncode(buf *bytes.Buffer, base string, fields []field, single bool) {
+	for _, f := range fields {
+		target := base + "." + f.name
+		if single {
+			target = base
+		}
+		fmt.Fprintf(buf, "if err := encoder.Encode(%v); err != nil {\nreturn err\n}\n", target)
+	}
+}
+
+// writeBitVec writes a type for bit sequences with the given size of their store type
+func writeBitVec(buf *bytes.Buffer, name string, size int) {
+	fmt.Fprintf(buf, "// %v is a bit sequence stored in units of %v bytes\n", name, size)
+	fmt.Fprintf(buf, "type %v struct {\nLen types.UCompact\nData []byte\n}\n\n", name)
+	fmt.Fprintf(buf, "func (m *%v) Decode(decoder scale.Decoder) error {\n", name)
+	buf.WriteString("if err := decoder.Decode(&m.Len); err != nil {\nreturn err\n}\n")
+	fmt.Fprintf(buf, "bits := (*big.Int)(&m.Len).Int64()\nm.Data = make([]byte, (bits+%v)/%v*%v)\n", size*8-1, size*8, size)
+	buf.WriteString("return decoder.Read(m.Data)\n}\n\n")
+	fmt.Fprintf(buf, "func (m %v) Encode(encoder scale.Encoder) error {\n", name)
+	buf.WriteString("if err := encoder.Encode(m.Len); err != nil {\nreturn err\n}\n")
+	buf.WriteString("return encoder.Write(m.Data)\n}\n\n")
+}
+
+type field struct {
+	// name is the name of the field in a struct, param the name of the field as a function parameter
+	name    string
+	param   string
+	typ     string
+	pointer bool
+}
+
+// fields returns the Go fields of the registry fields. Fields that refer back to the type owner are pointers.
+func (g *generator) fields(fields []types.Si1Field, owner int64) []field {
+	res := make([]field, len(fields))
+	used := map[string]int{}
+	for i, f := range fields {
+		var name string
+		switch {
+		case f.HasName:
+			name = exportedName(string(f.Name))
+		case f.HasTypeName:
+			// unnamed fields, e.g. of events, are named by their declared type, e.g. T::AccountId
+			tn := string(f.TypeName)
+			if j := strings.Index(tn, "<"); j >= 0 {
+				tn = tn[:j]
+			}
+			if j := strings.LastIndex(tn, "::"); j >= 0 {
+				tn = tn[j+2:]
+			}
+			name = exportedName(tn)
+		}
+		if name == "" {
+			name = fmt.Sprintf("F%v", i)
+		}
+		used[name]++
+		res[i] = field{name: name, typ: g.goType(f.Type.Int64())}
+		if owner >= 0 && g.names[f.Type.Int64()] != "" && g.reachesByValue(f.Type.Int64(), owner, map[int64]bool{}) {
+			res[i].typ = "*" + res[i].typ
+			res[i].pointer = true
+		}
+	}
+
+	// number fields with clashing names
+	seen := map[string]int{}
+	for i := range res {
+		if used[res[i].name] > 1 {
+			seen[res[i].name]++
+			res[i].name = fmt.Sprintf("%v%v", res[i].name, seen[res[i].name]-1)
+		}
+		res[i].param = paramName(res[i].name)
+	}
+	return res
+}
+
+// reachesByValue returns true if the type target is contained by value, without indirection, in the type id
+func (g *generator) reachesByValue(id, target int64, visited map[int64]bool) bool {
+	if id == target {
+		return true
+	}
+	if visited[id] || g.isKnown(id) {
+		return false
+	}
+	visited[id] = true
+
+	typ, ok := g.lookup[id]
+	if !ok {
+		return false
+	}
+	var children []types.Si1LookupTypeID
+	switch {
+	case typ.Def.IsComposite:
+		for _, f := range typ.Def.Composite.Fields {
+			children = append(children, f.Type)
+		}
+	case typ.Def.IsVariant:
+		for _, v := range typ.Def.Variant.Variants {
+			for _, f := range v.Fields {
+				children = append(children, f.Type)
+			}
+		}
+	case typ.Def.IsTuple:
+		children = typ.Def.Tuple
+	case typ.Def.IsArray:
+		children = append(children, typ.Def.Array.Type)
+	}
+	for _, c := range children {
+		if g.reachesByValue(c.Int64(), target, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// isKnown returns true for types that are generated as types of the types package
+func (g *generator) isKnown(id int64) bool {
+	if g.hasCallType && id == g.callType {
+		return true
+	}
+	typ, ok := g.lookup[id]
+	return ok && knownTypes[joinPath(typ.Path)] != ""
+}
+
+// goType returns the Go type of the registry type id, marking composite and variant types to be generated
+func (g *generator) goType(id int64) string {
+	if g.hasCallType && id == g.callType {
+		return "types.Call"
+	}
+	typ, ok := g.lookup[id]
+	if !ok {
+		return "types.Bytes"
+	}
+	if known := knownTypes[joinPath(typ.Path)]; known != "" {
+		return known
+	}
+
+	def := typ.Def
+	switch {
+	case def.IsComposite, def.IsVariant:
+		if !g.needed[id] {
+			g.needed[id] = true
+			g.queue = append(g.queue, id)
+		}
+		return g.names[id]
+	case def.IsPrimitive:
+		return primitiveTypes[def.Primitive.Si0TypeDefPrimitive]
+	case def.IsSequence:
+		if g.isU8(def.Sequence.Type) {
+			return "types.Bytes"
+		}
+		return "[]" + g.goType(def.Sequence.Type.Int64())
+	case def.IsArray:
+		if g.isU8(def.Array.Type) {
+			return fmt.Sprintf("[%v]byte", def.Array.Len)
+		}
+		return fmt.Sprintf("[%v]%v", def.Array.Len, g.goType(def.Array.Type.Int64()))
+	case def.IsTuple:
+		if len(def.Tuple) == 0 {
+			return "struct{}"
+		}
+		elems := make([]string, len(def.Tuple))
+		for i, e := range def.Tuple {
+			elems[i] = fmt.Sprintf("F%v %v", i, g.goType(e.Int64()))
+		}
+		return "struct {" + strings.Join(elems, "; ") + "}"
+	case def.IsCompact:
+		return "types.UCompact"
+	case def.IsBitSequence:
+		size := 1
+		if store, ok := g.lookup[def.BitSequence.BitStoreType.Int64()]; ok && store.Def.IsPrimitive {
+			if s, ok := primitiveSizes[store.Def.Primitive.Si0TypeDefPrimitive]; ok {
+				size = s
+			}
+		}
+		if g.bitVecs[size] == "" {
+			g.bitVecs[size] = "BitVec"
+			if size > 1 {
+				g.bitVecs[size] = fmt.Sprintf("BitVecU%v", size*8)
+			}
+		}
+		return g.bitVecs[size]
+	default:
+		return "types.Bytes"
+	}
+}
+
+func (g *generator) isU8(id types.Si1LookupTypeID) bool {
+	typ, ok := g.lookup[id.Int64()]
+	return ok && typ.Def.IsPrimitive && typ.Def.Primitive.Si0TypeDefPrimitive == types.IsU8
+}
+
+// variants returns the variants of the enum type id, e.g. the calls or events of a pallet
+func (g *generator) variants(id types.Si1LookupTypeID) []types.Si1Variant {
+	typ, ok := g.lookup[id.Int64()]
+	if !ok || !typ.Def.IsVariant {
+		return nil
+	}
+	return typ.Def.Variant.Variants
+}
+
+// typeNames names all composite and variant types of the registry by the last segment of their path. Clashing names
+// are prefixed with the crate of the type, followed by the names of the type parameters and finally the type id.
+func (g *generator) typeNames() map[int64]string {
+	var ids []int64
+	for id, typ := range g.lookup {
+		if typ.Def.IsComposite || typ.Def.IsVariant {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	names := map[int64]string{}
+	for _, id := range ids {
+		typ := g.lookup[id]
+		if len(typ.Path) == 0 {
+			names[id] = fmt.Sprintf("Type%v", id)
+			continue
+		}
+		names[id] = exportedName(string(typ.Path[len(typ.Path)-1]))
+	}
+
+	disambiguations := []func(id int64) string{
+		func(id int64) string {
+			path := g.lookup[id].Path
+			if len(path) < 2 {
+				return names[id]
+			}
+			crate := string(path[0])
+			for _, prefix := range []string{"pallet_", "frame_", "sp_"} {
+				crate = strings.TrimPrefix(crate, prefix)
+			}
+			return exportedName(crate) + names[id]
+		},
+		func(id int64) string {
+			name := names[id]
+			for _, p := range g.lookup[id].Params {
+				if p.HasType {
+					name += g.identName(p.Type.Int64(), 2)
+				}
+			}
+			return name
+		},
+		func(id int64) string {
+			return fmt.Sprintf("%v%v", names[id], id)
+		},
+	}
+	for _, disambiguate := range disambiguations {
+		count := map[string]int{}
+		for _, id := range ids {
+			count[names[id]]++
+		}
+		// names of the types package that are used in generated code don't clash, but the generated BitVec might
+		count["BitVec"]++
+		count["EventRecords"]++
+		next := map[int64]string{}
+		for _, id := range ids {
+			next[id] = names[id]
+			if count[names[id]] > 1 {
+				next[id] = disambiguate(id)
+			}
+		}
+		names = next
+	}
+	return names
+}
+
+// identName returns a short identifier for the type id, used to name generic types after their parameters
+func (g *generator) identName(id int64, depth int) string {
+	typ, ok := g.lookup[id]
+	if !ok || depth < 0 {
+		return ""
+	}
+	def := typ.Def
+	switch {
+	case len(typ.Path) > 0:
+		return exportedName(string(typ.Path[len(typ.Path)-1]))
+	case def.IsPrimitive:
+		return exportedName(strings.TrimPrefix(primitiveTypes[def.Primitive.Si0TypeDefPrimitive], "types."))
+	case def.IsSequence:
+		return "Vec" + g.identName(def.Sequence.Type.Int64(), depth-1)
+	case def.IsArray:
+		return fmt.Sprintf("Array%v%v", def.Array.Len, g.identName(def.Array.Type.Int64(), depth-1))
+	case def.IsCompact:
+		return "Compact" + g.identName(def.Compact.Type.Int64(), depth-1)
+	case def.IsTuple:
+		name := "Tuple"
+		for _, e := range def.Tuple {
+			name += g.identName(e.Int64(), depth-1)
+		}
+		return name
+	default:
+		return ""
+	}
+}
+
+func joinPath(path types.Si1Path) string {
+	segments := make([]string, len(path))
+	for i, s := range path {
+		segments[i] = string(s)
+	}
+	return strings.Join(segments, "::")
+}
+
+func joinWithPrefix(s []string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	return ", " + strings.Join(s, ", ")
+}
+
+// writeDocs writes a doc comment with the summary, followed by the docs from the metadata
+func writeDocs(buf *bytes.Buffer, summary string, docs []types.Text) {
+	fmt.Fprintf(buf, "// %v\n", summary)
+	for i, d := range docs {
+		if i == 0 {
+			buf.WriteString("//\n")
+		}
+		for _, line := range strings.Split(strings.TrimRight(string(d), " \t\r\n"), "\n") {
+			fmt.Fprintf(buf, "//%v\n", strings.TrimRight(line, " \t\r"))
+		}
+	}
+}
+
+// exportedName converts snake case and other names to an exported Go identifier, e.g. transfer_keep_alive to
+// TransferKeepAlive
+func exportedName(s string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	name := sb.String()
+	if name != "" && unicode.IsDigit(rune(name[0])) {
+		name = "N" + name
+	}
+	return name
+}
+
+// paramName converts an exported field name to a function parameter name that does not clash with keywords or the
+// parameters of generated functions
+func paramName(name string) string {
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	param := string(runes)
+	if token.Lookup(param).IsKeyword() {
+		return param + "Arg"
+	}
+	switch param {
+	case "meta", "types", "state", "scale", "fmt", "big":
+		return param + "Arg"
+	}
+	return param
+}
+
+// palletFileName returns the name of the file of a pallet. The suffix keeps the files of pallets such as Types or
+// Events apart from types.go and events.go, and keeps the go tool from taking them for test files or files for other
+// platforms, as it would for pallets whose names end in Test or Windows.
+func palletFileName(pallet types.Text) string {
+	return snakeCase(string(pallet)) + "_pallet.go"
+}
+
+func snakeCase(s string) string {
+	var sb strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				sb.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,243 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	var meta types.Metadata
+	err := types.DecodeFromHexString(types.MetadataV14Data, &meta)
+	assert.NoError(t, err)
+
+	files, err := generate(&meta, "runtime")
+	assert.NoError(t, err)
+	for _, name := range []string{"types.go", "events.go", "system_pallet.go", "balances_pallet.go",
+		"utility_pallet.go"} {
+		assert.Contains(t, files, name)
+		assert.Contains(t, string(files[name]), "// Code generated by gsrpc-gen. DO NOT EDIT.\n\npackage runtime\n")
+	}
+
+	balances := string(files["balances_pallet.go"])
+	assert.Contains(t, balances, `type EventBalancesTransfer struct {
+	Phase      types.Phase
+	AccountId0 types.AccountID
+	AccountId1 types.AccountID
+	Balance    types.U128
+	Topics     []types.Hash
+}`)
+	assert.Contains(t, balances, `func NewBalancesTransferCall(meta *types.Metadata, dest types.MultiAddress, `+
+		`value types.UCompact) (types.Call, error) {
+	return types.NewCall(meta, "Balances.transfer", dest, value)
+}`)
+	assert.Contains(t, balances,
+		`func GetBalancesExistentialDepositConstant(meta *types.Metadata) (value types.U128, err error) {`)
+	assert.Contains(t, balances, `func GetBalancesAccountAt(s *state.State, meta *types.Metadata, key0 types.AccountID, `+
+		`blockHash types.Hash) (value AccountData, ok bool, err error) {`)
+
+	system := string(files["system_pallet.go"])
+	assert.Contains(t, system,
+		`func SystemAccountKey(meta *types.Metadata, key0 types.AccountID) (types.StorageKey, error) {`)
+	assert.Contains(t, system,
+		`func GetSystemNumber(s *state.State, meta *types.Metadata) (value types.U32, ok bool, err error) {`)
+	assert.Contains(t, system, `func NewSystemRemarkCall(meta *types.Metadata, remark types.Bytes) (types.Call, error) {`)
+
+	// storage maps with several hashers take one key per hasher
+	assert.Contains(t, string(files["staking_pallet.go"]),
+		`func StakingErasStakersKey(meta *types.Metadata, key0 types.U32, key1 types.AccountID) `+
+			`(types.StorageKey, error) {`)
+
+	// nested calls are types.Call
+	assert.Contains(t, string(files["utility_pallet.go"]),
+		`func NewUtilityBatchCall(meta *types.Metadata, calls []types.Call) (types.Call, error) {`)
+
+	assert.Regexp(t, `\tBalances_Transfer +\[\]EventBalancesTransfer\n`, string(files["events.go"]))
+
+	typs := string(files["types.go"])
+	assert.Contains(t, typs, `type AccountInfo struct {
+	Nonce       types.U32
+	Consumers   types.U32
+	Providers   types.U32
+	Sufficients types.U32
+	Data        AccountData
+}`)
+	assert.Contains(t, typs, "func (m *DispatchError) Decode(decoder scale.Decoder) error {")
+	// clashing names are prefixed with the crate of the type
+	assert.Contains(t, typs, "type BalancesReleases struct {")
+	assert.Contains(t, typs, "type StakingReleases struct {")
+}
+
+func TestGenerate_RecursiveType(t *testing.T) {
+	u8 := types.Si1Type{Def: types.Si1TypeDef{IsPrimitive: true,
+		Primitive: types.Si1TypeDefPrimitive{Si0TypeDefPrimitive: types.IsU8}}}
+	tree := types.Si1Type{Path: types.Si1Path{"tree", "Tree"}, Def: types.Si1TypeDef{IsVariant: true,
+		Variant: types.Si1TypeDefVariant{Variants: []types.Si1Variant{
+			{Name: "Leaf", Index: 0, Fields: []types.Si1Field{{Type: types.NewSi1LookupTypeIDFromUInt(0)}}},
+			{Name: "Node", Index: 1, Fields: []types.Si1Field{
+				{Type: types.NewSi1LookupTypeIDFromUInt(1)}, {Type: types.NewSi1LookupTypeIDFromUInt(1)},
+			}},
+		}}}}
+	event := types.Si1Type{Path: types.Si1Path{"pallet_tree", "Event"}, Def: types.Si1TypeDef{IsVariant: true,
+		Variant: types.Si1TypeDefVariant{Variants: []types.Si1Variant{
+			{Name: "Grown", Fields: []types.Si1Field{{Type: types.NewSi1LookupTypeIDFromUInt(1)}}},
+		}}}}
+
+	meta := types.NewMetadataV14()
+	meta.AsMetadataV14.Lookup.Types = []types.PortableTypeV14{
+		{ID: types.NewSi1LookupTypeIDFromUInt(0), Type: u8},
+		{ID: types.NewSi1LookupTypeIDFromUInt(1), Type: tree},
+		{ID: types.NewSi1LookupTypeIDFromUInt(2), Type: event},
+	}
+	meta.AsMetadataV14.Pallets = []types.PalletMetadataV14{
+		{Name: "Tree", HasEvents: true, Events: types.EventMetadataV14{Type: types.NewSi1LookupTypeIDFromUInt(2)}},
+	}
+
+	files, err := generate(meta, "tree")
+	assert.NoError(t, err)
+	assert.Contains(t, string(files["tree_pallet.go"]),
+		"type EventTreeGrown struct {\n\tPhase  types.Phase\n\tF0     Tree\n")
+	typs := string(files["types.go"])
+	assert.Contains(t, typs, `type Tree struct {
+	IsLeaf bool
+	AsLeaf types.U8
+	IsNode bool
+	AsNode struct {
+		F0 *Tree
+		F1 *Tree
+	}
+}`)
+	assert.Contains(t, typs, `		m.AsNode.F0 = new(Tree)
+		if err := decoder.Decode(m.AsNode.F0); err != nil {
+			return err
+		}`)
+
+	if !testing.Short() {
+		buildGenerated(t, "tree", files)
+	}
+}
+
+func TestGenerate_Compiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the build of the generated code in short mode")
+	}
+
+	var meta types.Metadata
+	err := types.DecodeFromHexString(types.MetadataV14Data, &meta)
+	assert.NoError(t, err)
+	files, err := generate(&meta, "runtime")
+	assert.NoError(t, err)
+	buildGenerated(t, "runtime", files)
+}
+
+// buildGenerated builds and vets the generated package pkg in a module that uses this repository
+func buildGenerated(t *testing.T, pkg string, files map[string][]byte) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not installed")
+	}
+
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	assert.NoError(t, err)
+	dir, err := ioutil.TempDir("", "gsrpc-gen")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	goMod := "module generated\n\ngo 1.16\n\n" +
+		"require github.com/Phala-Network/go-substrate-rpc-client/v3 v3.0.0\n\n" +
+		"replace github.com/Phala-Network/go-substrate-rpc-client/v3 => " + root + "\n"
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644))
+	goSum, err := ioutil.ReadFile(filepath.Join(root, "go.sum"))
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "go.sum"), goSum, 0644))
+
+	pkgDir := filepath.Join(dir, pkg)
+	assert.NoError(t, os.Mkdir(pkgDir, 0755))
+	for name, content := range files {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(pkgDir, name), content, 0644))
+	}
+
+	for _, args := range [][]string{{"build", "./..."}, {"vet", "./..."}} {
+		cmd := exec.Command(goBin, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+		out, err := cmd.CombinedOutput()
+		assert.NoError(t, err, "go %v: %s", args[0], out)
+	}
+}
+
+func TestGenerate_FileNames(t *testing.T) {
+	u8 := types.Si1Type{Def: types.Si1TypeDef{IsPrimitive: true,
+		Primitive: types.Si1TypeDefPrimitive{Si0TypeDefPrimitive: types.IsU8}}}
+	event := types.Si1Type{Path: types.Si1Path{"pallet", "Event"}, Def: types.Si1TypeDef{IsVariant: true,
+		Variant: types.Si1TypeDefVariant{Variants: []types.Si1Variant{
+			{Name: "Happened", Fields: []types.Si1Field{{Type: types.NewSi1LookupTypeIDFromUInt(0)}}},
+		}}}}
+
+	meta := types.NewMetadataV14()
+	meta.AsMetadataV14.Lookup.Types = []types.PortableTypeV14{
+		{ID: types.NewSi1LookupTypeIDFromUInt(0), Type: u8},
+		{ID: types.NewSi1LookupTypeIDFromUInt(1), Type: event},
+	}
+	for i, name := range []types.Text{"Types", "Events", "SystemTest", "Windows"} {
+		meta.AsMetadataV14.Pallets = append(meta.AsMetadataV14.Pallets, types.PalletMetadataV14{
+			Name: name, Index: types.NewU8(uint8(i)), HasEvents: true,
+			Events: types.EventMetadataV14{Type: types.NewSi1LookupTypeIDFromUInt(1)},
+		})
+	}
+
+	files, err := generate(meta, "names")
+	assert.NoError(t, err)
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{"types.go", "events.go", "types_pallet.go", "events_pallet.go",
+		"system_test_pallet.go", "windows_pallet.go"}, names)
+	if !testing.Short() {
+		buildGenerated(t, "names", files)
+	}
+
+	meta.AsMetadataV14.Pallets[1].Name = "System_test"
+	_, err = generate(meta, "names")
+	assert.EqualError(t, err, "pallets System_test and SystemTest are both generated into system_test_pallet.go")
+}
+
+func TestGenerate_Unsupported(t *testing.T) {
+	_, err := generate(types.ExamplaryMetadataV13, "runtime")
+	assert.EqualError(t, err, "generating code for metadata version 13 is not supported")
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, "TransferKeepAlive", exportedName("transfer_keep_alive"))
+	assert.Equal(t, "AccountId", exportedName("AccountId"))
+	assert.Equal(t, "Ratio", exportedName("_ratio"))
+	assert.Equal(t, "N0", exportedName("0"))
+
+	assert.Equal(t, "dest", paramName("Dest"))
+	assert.Equal(t, "typeArg", paramName("Type"))
+	assert.Equal(t, "metaArg", paramName("Meta"))
+
+	assert.Equal(t, "election_provider_multi_phase", snakeCase("ElectionProviderMultiPhase"))
+}
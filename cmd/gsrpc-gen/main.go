@@ -0,0 +1,114 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// gsrpc-gen generates a Go package with typed bindings for the events, calls, storage items and constants of a
+// runtime from its V14 or V15 metadata.
+//
+// Usage:
+//
+//	gsrpc-gen -metadata metadata.scale -out ./runtime
+//	gsrpc-gen -url ws://127.0.0.1:9944 -out ./runtime -package phala
+//
+// The metadata file may contain the SCALE encoded metadata or its hex encoding, as returned by state_getMetadata.
+// The bindings of each pallet are written to <pallet>_pallet.go, the event records to events.go and the types used by
+// the bindings to types.go.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gsrpc "github.com/Phala-Network/go-substrate-rpc-client/v3"
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+)
+
+func main() {
+	metadataFile := flag.String("metadata", "", "file containing the metadata, SCALE or hex encoded")
+	url := flag.String("url", "", "url of the node to fetch the metadata from, if no metadata file is given")
+	out := flag.String("out", ".", "directory to write the generated files to")
+	pkg := flag.String("package", "", "name of the generated package, defaults to the name of the out directory")
+	flag.Parse()
+
+	err := run(*metadataFile, *url, *out, *pkg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gsrpc-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(metadataFile, url, out, pkg string) error {
+	meta, err := loadMetadata(metadataFile, url)
+	if err != nil {
+		return err
+	}
+
+	if pkg == "" {
+		abs, err := filepath.Abs(out)
+		if err != nil {
+			return err
+		}
+		pkg = strings.ToLower(exportedName(filepath.Base(abs)))
+	}
+
+	files, err := generate(meta, pkg)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(out, 0755)
+	if err != nil {
+		return err
+	}
+	for name, src := range files {
+		err = ioutil.WriteFile(filepath.Join(out, name), src, 0644) //nolint:gosec
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadMetadata(metadataFile, url string) (*types.Metadata, error) {
+	switch {
+	case metadataFile != "":
+		bz, err := ioutil.ReadFile(metadataFile)
+		if err != nil {
+			return nil, err
+		}
+		var meta types.Metadata
+		if hex := strings.TrimSpace(string(bz)); strings.HasPrefix(hex, "0x") {
+			err = types.DecodeFromHexString(hex, &meta)
+		} else {
+			err = types.DecodeFromBytes(bz, &meta)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode metadata from %v: %v", metadataFile, err)
+		}
+		return &meta, nil
+	case url != "":
+		api, err := gsrpc.NewSubstrateAPI(url)
+		if err != nil {
+			return nil, err
+		}
+		return api.RPC.State.GetMetadataLatest()
+	default:
+		return nil, fmt.Errorf("either a metadata file or the url of a node is required")
+	}
+}
@@ -42,3 +42,22 @@ func NewSubstrateAPI(url string) (*SubstrateAPI, error) {
 		Client: cl,
 	}, nil
 }
+
+// NewSubstrateAPIWithMetadataCache connects to the node like NewSubstrateAPI, caching the metadata of all runtimes in
+// dir, see state.MetadataCache
+func NewSubstrateAPIWithMetadataCache(url, dir string) (*SubstrateAPI, error) {
+	cl, err := client.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+
+	newRPC, err := rpc.NewRPCWithMetadataCache(cl, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SubstrateAPI{
+		RPC:    newRPC,
+		Client: cl,
+	}, nil
+}
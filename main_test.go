@@ -265,6 +265,45 @@ func Example_makeASimpleTransfer() {
 	// Output: Balance transferred from Alice to Bob: 100000000000000
 }
 
+func Example_makeASimpleTransferWithTxBuilder() {
+	// This sample shows how to make a transfer with the TxBuilder, which fetches the genesis hash, runtime version,
+	// nonce and a recent finalized block for a mortal era from the node.
+
+	api, err := gsrpc.NewSubstrateAPI(config.Default().RPCURL)
+	if err != nil {
+		panic(err)
+	}
+
+	meta, err := api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		panic(err)
+	}
+
+	bob, err := types.NewMultiAddressFromHexAccountID("0x8eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f26a48")
+	if err != nil {
+		panic(err)
+	}
+
+	c, err := types.NewCall(meta, "Balances.transfer", bob, types.NewUCompactFromUInt(12345))
+	if err != nil {
+		panic(err)
+	}
+
+	alice := types.NewKeyringPairSigner(signature.TestKeyringPairAlice)
+	tx, err := api.NewTx(c, alice).WithTip(types.NewUCompactFromUInt(100)).SubmitAndWatch()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Status.Unsubscribe()
+
+	for status := range tx.Status.Chan() {
+		if status.IsInBlock {
+			fmt.Printf("Extrinsic %#x included at block %#x\n", tx.Hash, status.AsInBlock)
+			return
+		}
+	}
+}
+
 func Example_displaySystemEvents() {
 	// Query the system events and extract information from them. This example runs until exited via Ctrl-C
 
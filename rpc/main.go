@@ -32,7 +32,9 @@ type RPC struct {
 	Offchain *offchain.Offchain
 	State    *state.State
 	System   *system.System
-	client   client.Client
+	// MetadataCache is only set if the RPC has been created by NewRPCWithMetadataCache
+	MetadataCache *state.MetadataCache
+	client        client.Client
 }
 
 func NewRPC(cl client.Client) (*RPC, error) {
@@ -42,6 +44,30 @@ func NewRPC(cl client.Client) (*RPC, error) {
 		return nil, err
 	}
 
+	return newRPC(cl, st, meta), nil
+}
+
+// NewRPCWithMetadataCache creates the RPC like NewRPC, but loads the latest metadata from the metadata cache in dir,
+// fetching it only if the latest runtime is not cached yet. The cache is available as RPC.MetadataCache.
+func NewRPCWithMetadataCache(cl client.Client, dir string) (*RPC, error) {
+	genesisHash, err := chain.NewChain(cl).GetBlockHash(0)
+	if err != nil {
+		return nil, err
+	}
+
+	st := state.NewState(cl)
+	cache := state.NewMetadataCache(st, genesisHash, dir)
+	meta, err := cache.MetadataLatest()
+	if err != nil {
+		return nil, err
+	}
+
+	r := newRPC(cl, st, meta)
+	r.MetadataCache = cache
+	return r, nil
+}
+
+func newRPC(cl client.Client, st *state.State, meta *types.Metadata) *RPC {
 	opts := types.SerDeOptionsFromMetadata(meta)
 	types.SetSerDeOptions(opts)
 
@@ -52,5 +78,5 @@ func NewRPC(cl client.Client) (*RPC, error) {
 		State:    st,
 		System:   system.NewSystem(cl),
 		client:   cl,
-	}, nil
+	}
 }
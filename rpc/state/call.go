@@ -0,0 +1,44 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/client"
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+)
+
+// Call calls the runtime API method with the SCALE encoded data at the given block and returns the SCALE encoded
+// result. Methods are named <api>_<method>, e.g. Metadata_metadata_at_version.
+func (s *State) Call(method string, data []byte, blockHash types.Hash) (types.Bytes, error) {
+	return s.call(method, data, &blockHash)
+}
+
+// CallLatest calls the runtime API method with the SCALE encoded data at the latest block and returns the SCALE
+// encoded result
+func (s *State) CallLatest(method string, data []byte) (types.Bytes, error) {
+	return s.call(method, data, nil)
+}
+
+func (s *State) call(method string, data []byte, blockHash *types.Hash) (types.Bytes, error) {
+	var res string
+	err := client.CallWithBlockHash(s.client, &res, "state_call", blockHash, method, types.HexEncodeToString(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return types.HexDecodeString(res)
+}
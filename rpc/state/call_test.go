@@ -0,0 +1,41 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestState_CallLatest(t *testing.T) {
+	res, err := state.CallLatest("Metadata_metadata_at_version", []byte{16, 0, 0, 0})
+	assert.NoError(t, err)
+	assert.Equal(t, types.Bytes{0}, res)
+}
+
+func TestState_Call(t *testing.T) {
+	res, err := state.Call("Metadata_metadata_at_version", []byte{15, 0, 0, 0}, mockSrv.blockHashLatest)
+	assert.NoError(t, err)
+
+	var opaque types.OptionBytes
+	err = types.DecodeFromBytes(res, &opaque)
+	assert.NoError(t, err)
+	ok, _ := opaque.Unwrap()
+	assert.True(t, ok)
+}
@@ -17,6 +17,8 @@
 package state
 
 import (
+	"fmt"
+
 	"github.com/Phala-Network/go-substrate-rpc-client/v3/client"
 	"github.com/Phala-Network/go-substrate-rpc-client/v3/types"
 )
@@ -32,13 +34,61 @@ func (s *State) GetMetadataLatest() (*types.Metadata, error) {
 }
 
 func (s *State) getMetadata(blockHash *types.Hash) (*types.Metadata, error) {
+	bz, err := s.getMetadataRaw(blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata types.Metadata
+	err = types.DecodeFromBytes(bz, &metadata)
+	return &metadata, err
+}
+
+// getMetadataRaw returns the SCALE encoded metadata at the given block, or the latest if blockHash is nil
+func (s *State) getMetadataRaw(blockHash *types.Hash) ([]byte, error) {
 	var res string
 	err := client.CallWithBlockHash(s.client, &res, "state_getMetadata", blockHash)
 	if err != nil {
 		return nil, err
 	}
 
+	return types.HexDecodeString(res)
+}
+
+// GetMetadataAtVersion returns the metadata of the given version at the given block. It is fetched through the
+// Metadata_metadata_at_version runtime API, which is needed to get metadata of versions above 14.
+func (s *State) GetMetadataAtVersion(version uint32, blockHash types.Hash) (*types.Metadata, error) {
+	return s.getMetadataAtVersion(version, &blockHash)
+}
+
+// GetMetadataAtVersionLatest returns the latest metadata of the given version
+func (s *State) GetMetadataAtVersionLatest(version uint32) (*types.Metadata, error) {
+	return s.getMetadataAtVersion(version, nil)
+}
+
+func (s *State) getMetadataAtVersion(version uint32, blockHash *types.Hash) (*types.Metadata, error) {
+	arg, err := types.EncodeToBytes(version)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.call("Metadata_metadata_at_version", arg, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// the runtime API returns an Option<OpaqueMetadata>, with OpaqueMetadata being the encoded metadata
+	var opaque types.OptionBytes
+	err = types.DecodeFromBytes(res, &opaque)
+	if err != nil {
+		return nil, err
+	}
+	ok, bz := opaque.Unwrap()
+	if !ok {
+		return nil, fmt.Errorf("metadata version %v is not supported by the runtime", version)
+	}
+
 	var metadata types.Metadata
-	err = types.DecodeFromHexString(res, &metadata)
+	err = types.DecodeFromBytes(bz, &metadata)
 	return &metadata, err
 }
@@ -33,3 +33,21 @@ func TestState_GetMetadata(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, *mockSrv.metadata, *md)
 }
+
+func TestState_GetMetadataAtVersionLatest(t *testing.T) {
+	md, err := state.GetMetadataAtVersionLatest(15)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 15, md.Version)
+	assert.Equal(t, mockSrv.metadataV15.AsMetadataV15.Pallets, md.AsMetadataV15.Pallets)
+	assert.Equal(t, mockSrv.metadataV15.AsMetadataV15.APIs, md.AsMetadataV15.APIs)
+	assert.True(t, md.ExistsModuleMetadata("System"))
+}
+
+func TestState_GetMetadataAtVersion(t *testing.T) {
+	md, err := state.GetMetadataAtVersion(15, mockSrv.blockHashLatest)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 15, md.Version)
+
+	_, err = state.GetMetadataAtVersion(16, mockSrv.blockHashLatest)
+	assert.EqualError(t, err, "metadata version 16 is not supported by the runtime")
+}
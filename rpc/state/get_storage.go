@@ -47,6 +47,27 @@ func (s *State) GetStorageLatest(key types.StorageKey, target interface{}) (ok b
 	return true, types.DecodeFromBytes(*raw, target)
 }
 
+// GetStorageValue retreives the stored data of key, which is a key of the storage item entry, and applies the default
+// of entry if the key is absent, see types.NewStorageValue
+func (s *State) GetStorageValue(entry types.StorageEntryMetadata, key types.StorageKey, blockHash types.Hash) (
+	types.StorageValue, error) {
+	raw, err := s.getStorageRaw(key, &blockHash)
+	if err != nil {
+		return types.StorageValue{}, err
+	}
+	return types.NewStorageValue(entry, *raw)
+}
+
+// GetStorageValueLatest retreives the stored data of key for the latest block height, see GetStorageValue
+func (s *State) GetStorageValueLatest(entry types.StorageEntryMetadata, key types.StorageKey) (types.StorageValue,
+	error) {
+	raw, err := s.getStorageRaw(key, nil)
+	if err != nil {
+		return types.StorageValue{}, err
+	}
+	return types.NewStorageValue(entry, *raw)
+}
+
 // GetStorageRaw retreives the stored data as raw bytes, without decoding them
 func (s *State) GetStorageRaw(key types.StorageKey, blockHash types.Hash) (*types.StorageDataRaw, error) {
 	return s.getStorageRaw(key, &blockHash)
@@ -57,3 +57,32 @@ func TestState_GetStorageRaw(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, mockSrv.storageDataHex, data.Hex())
 }
+
+func TestState_GetStorageValue(t *testing.T) {
+	entry := types.StorageEntryMetadataV14{
+		Modifier: types.StorageFunctionModifierV0{IsDefault: true},
+		Fallback: types.MustHexDecodeString("0x0100000000000000"),
+	}
+
+	v, err := state.GetStorageValue(entry, types.MustHexDecodeString(mockSrv.storageKeyHex), mockSrv.blockHashLatest)
+	assert.NoError(t, err)
+	assert.True(t, v.IsStored)
+	var decoded types.U64
+	ok, err := v.Decode(&decoded)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, types.U64(0x5d892db8), decoded)
+
+	v, err = state.GetStorageValueLatest(entry, []byte{0xab})
+	assert.NoError(t, err)
+	assert.True(t, v.IsDefault())
+	ok, err = v.Decode(&decoded)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, types.U64(1), decoded)
+
+	entry.Modifier = types.StorageFunctionModifierV0{IsOptional: true}
+	v, err = state.GetStorageValueLatest(entry, []byte{0xab})
+	assert.NoError(t, err)
+	assert.False(t, v.HasValue())
+}
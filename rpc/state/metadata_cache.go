@@ -0,0 +1,197 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+)
+
+// MetadataCache caches the metadata of a chain by the spec version of its runtime, so that the metadata is fetched
+// once per runtime upgrade instead of once per block. If a directory is given, the SCALE encoded metadata is also
+// stored there, in a file per genesis hash and spec version, and loaded from there on a cache miss.
+type MetadataCache struct {
+	state       *State
+	genesisHash types.Hash
+	dir         string
+
+	mu       sync.Mutex
+	metadata map[types.U32]*types.Metadata
+}
+
+// NewMetadataCache creates a cache for the metadata of the chain with the given genesis hash. If dir is empty, the
+// metadata is only cached in memory.
+func NewMetadataCache(s *State, genesisHash types.Hash, dir string) *MetadataCache {
+	return &MetadataCache{
+		state:       s,
+		genesisHash: genesisHash,
+		dir:         dir,
+		metadata:    map[types.U32]*types.Metadata{},
+	}
+}
+
+// MetadataAt returns the metadata of the runtime at the given block, looking up its spec version through
+// GetRuntimeVersion and fetching the metadata only if it is not cached
+func (c *MetadataCache) MetadataAt(blockHash types.Hash) (*types.Metadata, error) {
+	rv, err := c.state.GetRuntimeVersion(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	meta, _, err := c.get(rv.SpecVersion, &blockHash)
+	return meta, err
+}
+
+// MetadataLatest returns the metadata of the latest runtime, fetching it only if it is not cached
+func (c *MetadataCache) MetadataLatest() (*types.Metadata, error) {
+	for {
+		rv, err := c.state.GetRuntimeVersionLatest()
+		if err != nil {
+			return nil, err
+		}
+		meta, fetched, err := c.get(rv.SpecVersion, nil)
+		if err != nil || !fetched {
+			return meta, err
+		}
+
+		// make sure the runtime has not been upgraded while fetching the latest metadata, which would otherwise be
+		// cached for the previous spec version
+		after, err := c.state.GetRuntimeVersionLatest()
+		if err != nil {
+			return nil, err
+		}
+		if after.SpecVersion == rv.SpecVersion {
+			return meta, nil
+		}
+		err = c.evict(rv.SpecVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Metadata returns the metadata of the given spec version if it is cached in memory or on disk
+func (c *MetadataCache) Metadata(specVersion types.U32) (*types.Metadata, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.cached(specVersion)
+}
+
+// cached returns the metadata from memory or from disk. c.mu must be held.
+func (c *MetadataCache) cached(specVersion types.U32) (*types.Metadata, bool) {
+	if meta, ok := c.metadata[specVersion]; ok {
+		return meta, true
+	}
+	if c.dir == "" {
+		return nil, false
+	}
+
+	bz, err := ioutil.ReadFile(c.path(specVersion))
+	if err != nil {
+		return nil, false
+	}
+	var meta types.Metadata
+	// files that can't be decoded are fetched again and overwritten
+	if types.DecodeFromBytes(bz, &meta) != nil {
+		return nil, false
+	}
+	c.metadata[specVersion] = &meta
+	return &meta, true
+}
+
+// get returns the cached metadata of the given spec version or fetches it at the given block, in which case fetched
+// is true
+func (c *MetadataCache) get(specVersion types.U32, blockHash *types.Hash) (meta *types.Metadata, fetched bool,
+	err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if meta, ok := c.cached(specVersion); ok {
+		return meta, false, nil
+	}
+
+	bz, err := c.state.getMetadataRaw(blockHash)
+	if err != nil {
+		return nil, false, err
+	}
+	meta = &types.Metadata{}
+	err = types.DecodeFromBytes(bz, meta)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if c.dir != "" {
+		err = c.store(specVersion, bz)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to store metadata of spec version %v: %v", specVersion, err)
+		}
+	}
+	c.metadata[specVersion] = meta
+	return meta, true, nil
+}
+
+// evict removes the metadata of the given spec version from memory and disk
+func (c *MetadataCache) evict(specVersion types.U32) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.metadata, specVersion)
+	if c.dir == "" {
+		return nil
+	}
+	err := os.Remove(c.path(specVersion))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// store writes the encoded metadata to a temporary file that is then renamed, so that concurrent readers never see
+// partially written files
+func (c *MetadataCache) store(specVersion types.U32, bz []byte) error {
+	path := c.path(specVersion)
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(bz)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+	err = f.Close()
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (c *MetadataCache) path(specVersion types.U32) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%#x", c.genesisHash[:]), fmt.Sprintf("%v.scale", specVersion))
+}
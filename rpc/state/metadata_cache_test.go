@@ -0,0 +1,96 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var genesisHash = types.NewHash([]byte{0xaa, 0xbb})
+
+func TestMetadataCache_InMemory(t *testing.T) {
+	cache := NewMetadataCache(state, genesisHash, "")
+	requests := mockSrv.metadataRequests
+
+	_, ok := cache.Metadata(mockSrv.runtimeVersion.SpecVersion)
+	assert.False(t, ok)
+
+	meta, err := cache.MetadataAt(mockSrv.blockHashLatest)
+	assert.NoError(t, err)
+	assert.Equal(t, mockSrv.metadata, meta)
+	assert.Equal(t, requests+1, mockSrv.metadataRequests)
+
+	// blocks of the same runtime share the metadata
+	meta, err = cache.MetadataAt(types.NewHash([]byte{1}))
+	assert.NoError(t, err)
+	assert.Equal(t, mockSrv.metadata, meta)
+	meta, err = cache.MetadataLatest()
+	assert.NoError(t, err)
+	assert.Equal(t, mockSrv.metadata, meta)
+	assert.Equal(t, requests+1, mockSrv.metadataRequests)
+
+	cached, ok := cache.Metadata(mockSrv.runtimeVersion.SpecVersion)
+	assert.True(t, ok)
+	assert.Equal(t, meta, cached)
+}
+
+func TestMetadataCache_OnDisk(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gsrpc-metadata")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	requests := mockSrv.metadataRequests
+	meta, err := NewMetadataCache(state, genesisHash, dir).MetadataLatest()
+	assert.NoError(t, err)
+	assert.Equal(t, mockSrv.metadata, meta)
+	assert.Equal(t, requests+1, mockSrv.metadataRequests)
+
+	path := filepath.Join(dir, "0xaabb000000000000000000000000000000000000000000000000000000000000", "60.scale")
+	bz, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	expected, err := types.HexDecodeString(mockSrv.metadataString)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, bz)
+
+	// a new cache loads the metadata from disk
+	cache := NewMetadataCache(state, genesisHash, dir)
+	meta, err = cache.MetadataAt(mockSrv.blockHashLatest)
+	assert.NoError(t, err)
+	assert.Equal(t, mockSrv.metadata, meta)
+	assert.Equal(t, requests+1, mockSrv.metadataRequests)
+
+	// other chains don't share the cached metadata
+	_, ok := NewMetadataCache(state, types.NewHash([]byte{0xcc}), dir).Metadata(mockSrv.runtimeVersion.SpecVersion)
+	assert.False(t, ok)
+
+	// corrupted files are fetched again
+	err = ioutil.WriteFile(path, []byte{1, 2, 3}, 0644)
+	assert.NoError(t, err)
+	meta, err = NewMetadataCache(state, genesisHash, dir).MetadataLatest()
+	assert.NoError(t, err)
+	assert.Equal(t, mockSrv.metadata, meta)
+	assert.Equal(t, requests+2, mockSrv.metadataRequests)
+	bz, err = ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, bz)
+}
@@ -51,6 +51,7 @@ type MockSrv struct {
 	blockHashLatest          types.Hash
 	metadataString           string
 	metadata                 *types.Metadata
+	metadataV15              *types.Metadata
 	runtimeVersion           types.RuntimeVersion
 	storageKeyHex            string
 	storageKeyHexEmpty       string
@@ -64,12 +65,34 @@ type MockSrv struct {
 	childStorageTrieValue    ChildStorageTrieTestVal
 	childStorageTrieSize     types.U64
 	childStorageTrieHashHex  string
+	metadataRequests         int
 }
 
 func (s *MockSrv) GetMetadata(hash *string) string {
+	mockSrv.metadataRequests++
 	return mockSrv.metadataString
 }
 
+func (s *MockSrv) Call(method, data string, hash *string) string {
+	if method != "Metadata_metadata_at_version" {
+		panic("method not found")
+	}
+
+	// only version 15 is available
+	if data != "0x0f000000" {
+		return "0x00"
+	}
+	bz, err := types.EncodeToBytes(mockSrv.metadataV15)
+	if err != nil {
+		panic(err)
+	}
+	res, err := types.EncodeToHexString(types.NewOptionBytes(bz))
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
 func (s *MockSrv) GetRuntimeVersion(hash *string) types.RuntimeVersion {
 	return mockSrv.runtimeVersion
 }
@@ -184,4 +207,12 @@ var mockSrv = MockSrv{
 	},
 	childStorageTrieSize:    68,
 	childStorageTrieHashHex: "0x20e3fc48a91087d091c17de08a5c470de53ccdaebd361025b0e5b7c65b9a0d30", //nolint:lll
+	metadataV15: &types.Metadata{
+		MagicNumber: types.MagicNumber,
+		Version:     15,
+		AsMetadataV15: types.MetadataV15{
+			Pallets: []types.PalletMetadataV15{{Name: "System", Docs: []types.Text{"The System pallet"}}},
+			APIs:    []types.RuntimeAPIMetadataV15{{Name: "Metadata"}},
+		},
+	},
 }
@@ -17,6 +17,7 @@
 package system
 
 import (
+	"fmt"
 	"os"
 	"testing"
 
@@ -46,13 +47,22 @@ func TestMain(m *testing.M) {
 
 // MockSrv holds data and methods exposed by the RPC Mock Server used in integration tests
 type MockSrv struct {
-	chain        types.Text
-	health       types.Health
-	name         types.Text
-	networkState types.NetworkState
-	peers        []types.PeerInfo
-	properties   types.ChainProperties
-	version      types.Text
+	accountAddress   string
+	accountNextIndex types.U32
+	chain            types.Text
+	health           types.Health
+	name             types.Text
+	networkState     types.NetworkState
+	peers            []types.PeerInfo
+	properties       types.ChainProperties
+	version          types.Text
+}
+
+func (s *MockSrv) AccountNextIndex(address string) (types.U32, error) {
+	if address != mockSrv.accountAddress {
+		return 0, fmt.Errorf("invalid address %v", address)
+	}
+	return mockSrv.accountNextIndex, nil
 }
 
 func (s *MockSrv) Chain() types.Text {
@@ -87,10 +97,12 @@ func (s *MockSrv) Version() types.Text {
 // against real servers and update the values stored here. To do that, replace s.URL with
 // config.Default().RPCURL
 var mockSrv = MockSrv{
-	chain:        "test-chain",
-	health:       types.Health{Peers: 2, IsSyncing: false, ShouldHavePeers: true},
-	name:         "test-node",
-	networkState: types.NetworkState{PeerID: "my-peer-id"},
+	accountAddress:   "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY",
+	accountNextIndex: 7,
+	chain:            "test-chain",
+	health:           types.Health{Peers: 2, IsSyncing: false, ShouldHavePeers: true},
+	name:             "test-node",
+	networkState:     types.NetworkState{PeerID: "my-peer-id"},
 	peers: []types.PeerInfo{{PeerID: "another-peer-id", Roles: "Role", ProtocolVersion: 42,
 		BestHash: types.NewHash(types.MustHexDecodeString("0xabcd")), BestNumber: 420}},
 	properties: types.ChainProperties{IsTokenDecimals: true, AsTokenDecimals: 18,
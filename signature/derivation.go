@@ -0,0 +1,202 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/ss58"
+)
+
+// Junction is a step of a derivation path, written //name for hard and /name for soft derivation. Soft derivation is
+// only supported by sr25519, public keys derived with soft junctions can be derived from the parent public key.
+type Junction struct {
+	Name   string
+	IsHard bool
+}
+
+// NewHardJunction creates the hard junction //name
+func NewHardJunction(name string) Junction {
+	return Junction{Name: name, IsHard: true}
+}
+
+// NewSoftJunction creates the soft junction /name
+func NewSoftJunction(name string) Junction {
+	return Junction{Name: name}
+}
+
+func (j Junction) String() string {
+	if j.IsHard {
+		return "//" + j.Name
+	}
+	return "/" + j.Name
+}
+
+// DerivationPath is the part of a secret URI that follows the mnemonic, like //polkadot//0/soft///password. It lists
+// the junctions to derive along and the optional password of the mnemonic.
+type DerivationPath struct {
+	Junctions []Junction
+	Password  string
+}
+
+// ParseDerivationPath parses a derivation path like //polkadot//0/soft///password
+func ParseDerivationPath(path string) (DerivationPath, error) {
+	var p DerivationPath
+
+	if i := strings.Index(path, "///"); i >= 0 {
+		p.Password = path[i+3:]
+		path = path[:i]
+	}
+
+	for rest := path; rest != ""; {
+		if !strings.HasPrefix(rest, "/") {
+			return DerivationPath{}, fmt.Errorf("invalid derivation path %v", path)
+		}
+
+		isHard := strings.HasPrefix(rest, "//")
+		if isHard {
+			rest = rest[2:]
+		} else {
+			rest = rest[1:]
+		}
+
+		name := rest
+		if i := strings.Index(rest, "/"); i >= 0 {
+			name = rest[:i]
+		}
+		if name == "" {
+			return DerivationPath{}, fmt.Errorf("invalid derivation path %v", path)
+		}
+		rest = rest[len(name):]
+
+		p.Junctions = append(p.Junctions, Junction{Name: name, IsHard: isHard})
+	}
+
+	return p, nil
+}
+
+// Append returns a copy of the path with the junctions appended
+func (p DerivationPath) Append(junctions ...Junction) DerivationPath {
+	js := make([]Junction, 0, len(p.Junctions)+len(junctions))
+	js = append(append(js, p.Junctions...), junctions...)
+	return DerivationPath{Junctions: js, Password: p.Password}
+}
+
+func (p DerivationPath) String() string {
+	var b strings.Builder
+	for _, j := range p.Junctions {
+		b.WriteString(j.String())
+	}
+	if p.Password != "" {
+		b.WriteString("///")
+		b.WriteString(p.Password)
+	}
+	return b.String()
+}
+
+// URI returns the secret URI of the path for the mnemonic phrase, as accepted by KeyPairFromSecret
+func (p DerivationPath) URI(phrase string) string {
+	return phrase + p.String()
+}
+
+// DeriveKeyPair derives the key pair of scheme along path from the mnemonic phrase
+func DeriveKeyPair(scheme Scheme, phrase string, path DerivationPath) (KeyPair, error) {
+	err := ValidateMnemonic(phrase)
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	for _, j := range path.Junctions {
+		if err := validateJunction(scheme, j); err != nil {
+			return KeyPair{}, err
+		}
+	}
+
+	return KeyPairFromSecret(scheme, path.URI(phrase))
+}
+
+// Derive derives the child key pair along the junctions. Key pairs of all schemes support hard junctions, only sr25519
+// key pairs support soft junctions.
+func (k KeyPair) Derive(junctions ...Junction) (KeyPair, error) {
+	if k.keyPair == nil {
+		return KeyPair{}, fmt.Errorf("key pair has not been derived from a secret")
+	}
+
+	for _, j := range junctions {
+		if err := validateJunction(k.Scheme, j); err != nil {
+			return KeyPair{}, err
+		}
+	}
+
+	// the child is derived from the secret URI of the key pair extended with the junctions, the phrase or seed ends
+	// at the first junction
+	secret, path := k.secret, ""
+	if i := strings.Index(secret, "/"); i >= 0 {
+		secret, path = secret[:i], secret[i:]
+	}
+	p, err := ParseDerivationPath(path)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	return KeyPairFromSecret(k.Scheme, p.Append(junctions...).URI(secret))
+}
+
+func validateJunction(scheme Scheme, j Junction) error {
+	if j.Name == "" || strings.Contains(j.Name, "/") {
+		return fmt.Errorf("invalid junction name %q", j.Name)
+	}
+	if !j.IsHard && scheme != Sr25519 {
+		return fmt.Errorf("soft junction %v is not supported by %v", j, scheme)
+	}
+	return nil
+}
+
+// DerivedAccount is an account derived from a mnemonic along a derivation path
+type DerivedAccount struct {
+	// Path is the derivation path without the password of the mnemonic
+	Path      DerivationPath
+	PublicKey []byte
+	AccountID []byte
+	// Address is the SS58 address of the account id
+	Address string
+}
+
+// DeriveAccounts derives the accounts of scheme along each of the paths from the mnemonic phrase, with the SS58
+// addresses of the network with the given prefix
+func DeriveAccounts(scheme Scheme, phrase string, paths []DerivationPath, prefix uint16) ([]DerivedAccount, error) {
+	accounts := make([]DerivedAccount, len(paths))
+	for i, path := range paths {
+		kp, err := DeriveKeyPair(scheme, phrase, path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to derive account %v: %v", DerivationPath{Junctions: path.Junctions}, err)
+		}
+
+		address, err := ss58.Encode(kp.AccountID(), prefix)
+		if err != nil {
+			return nil, err
+		}
+
+		accounts[i] = DerivedAccount{
+			Path:      DerivationPath{Junctions: path.Junctions},
+			PublicKey: kp.PublicKey(),
+			AccountID: kp.AccountID(),
+			Address:   address,
+		}
+	}
+	return accounts, nil
+}
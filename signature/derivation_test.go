@@ -0,0 +1,194 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/signature"
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDerivationPath(t *testing.T) {
+	for _, test := range []struct {
+		path     string
+		expected DerivationPath
+	}{
+		{"", DerivationPath{}},
+		{"//Alice", DerivationPath{Junctions: []Junction{NewHardJunction("Alice")}}},
+		{"//polkadot//0/soft///pass/word", DerivationPath{
+			Junctions: []Junction{NewHardJunction("polkadot"), NewHardJunction("0"), NewSoftJunction("soft")},
+			Password:  "pass/word",
+		}},
+		{"///password", DerivationPath{Password: "password"}},
+	} {
+		p, err := ParseDerivationPath(test.path)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, p)
+		assert.Equal(t, test.path, p.String())
+	}
+
+	for _, path := range []string{"Alice", "//", "/soft//", "//Alice/"} {
+		_, err := ParseDerivationPath(path)
+		assert.EqualError(t, err, "invalid derivation path "+path)
+	}
+}
+
+func TestDerivationPath_Append(t *testing.T) {
+	base := DerivationPath{Junctions: []Junction{NewHardJunction("polkadot")}, Password: "password"}
+	child := base.Append(NewHardJunction("0"), NewSoftJunction("1"))
+	assert.Equal(t, "//polkadot//0/1///password", child.String())
+	assert.Equal(t, "//polkadot///password", base.String())
+	assert.Equal(t, devPhrase+"//polkadot//0/1///password", child.URI(devPhrase))
+}
+
+func TestDeriveKeyPair(t *testing.T) {
+	alice, err := ParseDerivationPath("//Alice")
+	assert.NoError(t, err)
+
+	for scheme, publicKey := range map[Scheme]string{
+		Sr25519: "0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d",
+		Ed25519: "0x88dc3417d5058ec4b4503e0c12ea1a0a89be200fe98922423d4334014fa6b0ee",
+		Ecdsa:   "0x020a1091341fe5664bfa1782d5e04779689068c916b04cb365ec3153755684d9a1",
+	} {
+		kp, err := DeriveKeyPair(scheme, devPhrase, alice)
+		assert.NoError(t, err)
+		assert.Equal(t, publicKey, types.HexEncodeToString(kp.PublicKey()), scheme.String())
+
+		root, err := DeriveKeyPair(scheme, devPhrase, DerivationPath{})
+		assert.NoError(t, err)
+		derived, err := root.Derive(NewHardJunction("Alice"))
+		assert.NoError(t, err)
+		assert.Equal(t, publicKey, types.HexEncodeToString(derived.PublicKey()), scheme.String())
+
+		_, err = DeriveKeyPair(scheme, devPhrase, alice.Append(Junction{Name: "a/b", IsHard: true}))
+		assert.EqualError(t, err, `invalid junction name "a/b"`)
+	}
+
+	_, err = DeriveKeyPair(Sr25519, "bottom drive obey lake", alice)
+	assert.EqualError(t, err, "invalid mnemonic")
+
+	_, err = DeriveKeyPair(Ed25519, devPhrase, alice.Append(NewSoftJunction("soft")))
+	assert.EqualError(t, err, "soft junction /soft is not supported by ed25519")
+}
+
+func TestKeyPair_Derive(t *testing.T) {
+	root, err := KeyPairFromSecret(Sr25519, devPhrase)
+	assert.NoError(t, err)
+
+	// the junctions derive the same key pairs as the secret URIs of subkey
+	long := strings.Repeat("a", 40)
+	for _, junctions := range [][]Junction{
+		{NewHardJunction("Alice"), NewSoftJunction("soft")},
+		{NewHardJunction("polkadot"), NewHardJunction("0"), NewSoftJunction("1")},
+		{NewSoftJunction(long), NewHardJunction(long)},
+	} {
+		path := DerivationPath{Junctions: junctions}
+		expected, err := KeyPairFromSecret(Sr25519, path.URI(devPhrase))
+		assert.NoError(t, err)
+
+		derived, err := root.Derive(junctions...)
+		assert.NoError(t, err)
+		assert.Equal(t, expected.PublicKey(), derived.PublicKey(), path.String())
+
+		derived, err = DeriveKeyPair(Sr25519, devPhrase, path)
+		assert.NoError(t, err)
+		assert.Equal(t, expected.PublicKey(), derived.PublicKey(), path.String())
+	}
+
+	// derived key pairs, also those derived with soft junctions or from a password protected phrase, derive further
+	for _, uri := range []string{"//Alice", "/soft", "//Alice///password"} {
+		parent, err := KeyPairFromSecret(Sr25519, devPhrase+uri)
+		assert.NoError(t, err)
+		child, err := parent.Derive(NewHardJunction("stash"), NewSoftJunction("0"))
+		assert.NoError(t, err)
+
+		path, err := ParseDerivationPath(uri)
+		assert.NoError(t, err)
+		expected, err := KeyPairFromSecret(Sr25519,
+			path.Append(NewHardJunction("stash"), NewSoftJunction("0")).URI(devPhrase))
+		assert.NoError(t, err)
+		assert.Equal(t, expected.PublicKey(), child.PublicKey(), uri)
+	}
+
+	// key pairs decrypted from keystores derive from their seed
+	for _, scheme := range []Scheme{Sr25519, Ed25519, Ecdsa} {
+		ks, err := LoadKeystore(filepath.Join("testdata", fmt.Sprintf("alice-%v.json", scheme)))
+		assert.NoError(t, err)
+		kp, err := ks.Decrypt(scheme.String() + " password")
+		assert.NoError(t, err)
+		child, err := kp.Derive(NewHardJunction("stash"))
+		assert.NoError(t, err)
+
+		expected, err := KeyPairFromSecret(scheme, "//Alice//stash")
+		assert.NoError(t, err)
+		assert.Equal(t, expected.PublicKey(), child.PublicKey(), scheme.String())
+	}
+
+	ecdsa, err := KeyPairFromSecret(Ecdsa, devPhrase)
+	assert.NoError(t, err)
+	_, err = ecdsa.Derive(NewSoftJunction("soft"))
+	assert.EqualError(t, err, "soft junction /soft is not supported by ecdsa")
+
+	_, err = KeyPair{}.Derive(NewHardJunction("Alice"))
+	assert.EqualError(t, err, "key pair has not been derived from a secret")
+}
+
+func TestDeriveKeyPair_Password(t *testing.T) {
+	kp, err := DeriveKeyPair(Sr25519, devPhrase, DerivationPath{Password: "password"})
+	assert.NoError(t, err)
+	expected, err := KeyPairFromSecret(Sr25519, devPhrase+"///password")
+	assert.NoError(t, err)
+	assert.Equal(t, expected.PublicKey(), kp.PublicKey())
+
+	root, err := KeyPairFromSecret(Sr25519, devPhrase)
+	assert.NoError(t, err)
+	assert.NotEqual(t, root.PublicKey(), kp.PublicKey())
+}
+
+func TestDeriveAccounts(t *testing.T) {
+	base := DerivationPath{Junctions: []Junction{NewHardJunction("phala")}, Password: "password"}
+	paths := []DerivationPath{base.Append(NewHardJunction("0")), base.Append(NewHardJunction("1"))}
+
+	for _, scheme := range []Scheme{Sr25519, Ed25519, Ecdsa} {
+		accounts, err := DeriveAccounts(scheme, devPhrase, paths, 30)
+		assert.NoError(t, err)
+		assert.Len(t, accounts, 2)
+
+		for i, account := range accounts {
+			kp, err := KeyPairFromSecret(scheme, paths[i].URI(devPhrase))
+			assert.NoError(t, err)
+			address, err := kp.SS58Address(30)
+			assert.NoError(t, err)
+
+			assert.Equal(t, DerivedAccount{
+				Path:      DerivationPath{Junctions: paths[i].Junctions},
+				PublicKey: kp.PublicKey(),
+				AccountID: kp.AccountID(),
+				Address:   address,
+			}, account)
+		}
+		assert.NotEqual(t, accounts[0].AccountID, accounts[1].AccountID)
+	}
+
+	_, err := DeriveAccounts(Ecdsa, devPhrase, []DerivationPath{base.Append(NewSoftJunction("0"))}, 30)
+	assert.EqualError(t, err, "unable to derive account //phala/0: soft junction /0 is not supported by ecdsa")
+}
@@ -0,0 +1,119 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"fmt"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/ss58"
+	"github.com/vedhavyas/go-subkey"
+	"github.com/vedhavyas/go-subkey/ecdsa"
+	"github.com/vedhavyas/go-subkey/ed25519"
+	"github.com/vedhavyas/go-subkey/sr25519"
+	"golang.org/x/crypto/blake2b"
+)
+
+// Scheme is a signature scheme supported by Substrate. The values follow the order of the variants of MultiSignature.
+type Scheme uint8
+
+const (
+	Ed25519 Scheme = iota
+	Sr25519
+	Ecdsa
+)
+
+func (s Scheme) String() string {
+	switch s {
+	case Ed25519:
+		return "ed25519"
+	case Sr25519:
+		return "sr25519"
+	case Ecdsa:
+		return "ecdsa"
+	default:
+		return fmt.Sprintf("Scheme(%d)", uint8(s))
+	}
+}
+
+func (s Scheme) subkeyScheme() (subkey.Scheme, error) {
+	switch s {
+	case Ed25519:
+		return ed25519.Scheme{}, nil
+	case Sr25519:
+		return sr25519.Scheme{}, nil
+	case Ecdsa:
+		return ecdsa.Scheme{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported signature scheme %v", s)
+	}
+}
+
+// KeyPair is a key pair of any Scheme that holds the private key in memory
+type KeyPair struct {
+	Scheme  Scheme
+	keyPair subkey.KeyPair
+	// secret is the secret URI the key pair has been derived from, which Derive extends with its junctions
+	secret string
+}
+
+// KeyPairFromSecret derives the key pair of scheme from a seed, phrase or URI like KeyringPairFromSecret does for
+// sr25519
+func KeyPairFromSecret(scheme Scheme, seedOrPhrase string) (KeyPair, error) {
+	s, err := scheme.subkeyScheme()
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	kp, err := subkey.DeriveKeyPair(s, seedOrPhrase)
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	return KeyPair{Scheme: scheme, keyPair: kp, secret: seedOrPhrase}, nil
+}
+
+// PublicKey returns the public key, which is compressed to 33 bytes for ecdsa
+func (k KeyPair) PublicKey() []byte {
+	return k.keyPair.Public()
+}
+
+// AccountID returns the account id, which is the public key for ed25519 and sr25519 and the blake2b-256 hash of the
+// public key for ecdsa
+func (k KeyPair) AccountID() []byte {
+	return k.keyPair.AccountID()
+}
+
+// SS58Address returns the SS58 address of the account id for the network with the given prefix, see ss58.Encode
+func (k KeyPair) SS58Address(prefix uint16) (string, error) {
+	return ss58.Encode(k.AccountID(), prefix)
+}
+
+// Sign signs data, hashing it with blake2b-256 first if it is longer than 256 bytes. Ecdsa signs the blake2b-256 hash
+// of that message and returns a 65 bytes signature that includes the recovery id.
+func (k KeyPair) Sign(data []byte) ([]byte, error) {
+	if k.keyPair == nil {
+		return nil, fmt.Errorf("key pair has not been derived from a secret")
+	}
+
+	// if data is longer than 256 bytes, hash it first
+	if len(data) > 256 {
+		h := blake2b.Sum256(data)
+		data = h[:]
+	}
+
+	return k.keyPair.Sign(data)
+}
@@ -0,0 +1,124 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature_test
+
+import (
+	"testing"
+
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/signature"
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/ss58"
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/vedhavyas/go-subkey"
+	"github.com/vedhavyas/go-subkey/ecdsa"
+	"github.com/vedhavyas/go-subkey/ed25519"
+	"github.com/vedhavyas/go-subkey/sr25519"
+	"golang.org/x/crypto/blake2b"
+)
+
+func TestKeyPairFromSecret(t *testing.T) {
+	for _, test := range []struct {
+		scheme    Scheme
+		publicKey string
+		accountID string
+		address   string
+	}{
+		{
+			scheme:    Sr25519,
+			publicKey: "0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d",
+			accountID: "0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d",
+			address:   "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY",
+		}, {
+			scheme:    Ed25519,
+			publicKey: "0x88dc3417d5058ec4b4503e0c12ea1a0a89be200fe98922423d4334014fa6b0ee",
+			accountID: "0x88dc3417d5058ec4b4503e0c12ea1a0a89be200fe98922423d4334014fa6b0ee",
+			address:   "5FA9nQDVg267DEd8m1ZypXLBnvN7SFxYwV7ndqSYGiN9TTpu",
+		}, {
+			scheme:    Ecdsa,
+			publicKey: "0x020a1091341fe5664bfa1782d5e04779689068c916b04cb365ec3153755684d9a1",
+			accountID: "0x01e552298e47454041ea31273b4b630c64c104e4514aa3643490b8aaca9cf8ed",
+			address:   "5C7C2Z5sWbytvHpuLTvzKunnnRwQxft1jiqrLD5rhucQ5S9X",
+		},
+	} {
+		kp, err := KeyPairFromSecret(test.scheme, "//Alice")
+		assert.NoError(t, err)
+		assert.Equal(t, test.scheme, kp.Scheme)
+		assert.Equal(t, test.publicKey, types.HexEncodeToString(kp.PublicKey()), test.scheme.String())
+		assert.Equal(t, test.accountID, types.HexEncodeToString(kp.AccountID()), test.scheme.String())
+		address, err := kp.SS58Address(42)
+		assert.NoError(t, err)
+		assert.Equal(t, test.address, address)
+	}
+
+	_, err := KeyPairFromSecret(Scheme(3), "//Alice")
+	assert.EqualError(t, err, "unsupported signature scheme Scheme(3)")
+}
+
+func TestKeyPair_SS58Address(t *testing.T) {
+	kp, err := KeyPairFromSecret(Sr25519, "//Alice")
+	assert.NoError(t, err)
+
+	// prefixes from 64 on are encoded with two bytes
+	for _, prefix := range []uint16{ss58.KusamaPrefix, 88, 1284} {
+		address, err := kp.SS58Address(prefix)
+		assert.NoError(t, err)
+		decodedPrefix, accountID, err := ss58.Decode(address)
+		assert.NoError(t, err)
+		assert.Equal(t, prefix, decodedPrefix)
+		assert.Equal(t, kp.AccountID(), accountID)
+	}
+
+	address, err := kp.SS58Address(ss58.KusamaPrefix)
+	assert.NoError(t, err)
+	assert.Equal(t, "HNZata7iMYWmk5RvZRTiAsSDhV8366zq2YGb3tLH5Upf74F", address)
+
+	_, err = kp.SS58Address(ss58.MaxPrefix + 1)
+	assert.EqualError(t, err, "SS58 prefix 16384 is larger than 16383")
+}
+
+func TestKeyPair_Sign(t *testing.T) {
+	for scheme, subkeyScheme := range map[Scheme]subkey.Scheme{
+		Ed25519: ed25519.Scheme{},
+		Sr25519: sr25519.Scheme{},
+		Ecdsa:   ecdsa.Scheme{},
+	} {
+		kp, err := KeyPairFromSecret(scheme, "//Bob")
+		assert.NoError(t, err)
+		verifier, err := subkey.DeriveKeyPair(subkeyScheme, "//Bob")
+		assert.NoError(t, err)
+
+		for _, data := range [][]byte{[]byte("hello world"), make([]byte, 300)} {
+			sig, err := kp.Sign(data)
+			assert.NoError(t, err)
+			if scheme == Ecdsa {
+				assert.Len(t, sig, 65)
+			} else {
+				assert.Len(t, sig, 64)
+			}
+
+			signed := data
+			if len(data) > 256 {
+				h := blake2b.Sum256(data)
+				signed = h[:]
+			}
+			assert.True(t, verifier.Verify(signed, sig), scheme.String())
+		}
+	}
+
+	_, err := KeyPair{}.Sign([]byte("hello world"))
+	assert.EqualError(t, err, "key pair has not been derived from a secret")
+}
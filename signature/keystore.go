@@ -0,0 +1,357 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Keystore is a key pair encrypted with a password in the JSON keystore format of polkadot-js, as exported by
+// polkadot.js apps and the polkadot-js keyring
+type Keystore struct {
+	// Encoded is the base64 encoded scrypt parameters, nonce and encrypted PKCS8 encoded secret
+	Encoded  string           `json:"encoded"`
+	Encoding KeystoreEncoding `json:"encoding"`
+	// Address is the SS58 address of the key pair
+	Address string `json:"address"`
+	// Meta holds the name and other metadata polkadot-js stores along with the key pair
+	Meta map[string]interface{} `json:"meta"`
+}
+
+// KeystoreEncoding describes how the key pair of a Keystore is encoded and encrypted
+type KeystoreEncoding struct {
+	// Content is the encoding of the key pair followed by its scheme, e.g. ["pkcs8", "sr25519"]
+	Content []string `json:"content"`
+	// Type lists the encryption steps, e.g. ["scrypt", "xsalsa20-poly1305"]
+	Type    []string `json:"type"`
+	Version string   `json:"version"`
+}
+
+const (
+	keystoreVersion = "3"
+
+	// the scrypt parameters of polkadot-js
+	scryptN = 1 << 15
+	scryptP = 1
+	scryptR = 8
+
+	scryptSaltLength   = 32
+	scryptParamsLength = scryptSaltLength + 3*4
+	nonceLength        = 24
+)
+
+var (
+	pkcs8Header  = []byte{48, 83, 2, 1, 1, 48, 5, 6, 3, 43, 101, 112, 4, 34, 4, 32}
+	pkcs8Divider = []byte{161, 35, 3, 33, 0}
+)
+
+// LoadKeystore reads a keystore from a JSON file exported by polkadot-js
+func LoadKeystore(path string) (Keystore, error) {
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Keystore{}, err
+	}
+
+	var ks Keystore
+	err = json.Unmarshal(bz, &ks)
+	if err != nil {
+		return Keystore{}, fmt.Errorf("unable to parse keystore %v: %v", path, err)
+	}
+	return ks, nil
+}
+
+// EncryptKeyPair encrypts the secret of kp with password into a keystore that can be imported by polkadot-js. The
+// address of the keystore is the SS58 address of kp for the network with the given prefix. Sr25519 key pairs that
+// have been derived with soft junctions have no seed and can't be encrypted.
+func EncryptKeyPair(kp KeyPair, password string, prefix uint16) (Keystore, error) {
+	if kp.keyPair == nil {
+		return Keystore{}, fmt.Errorf("key pair has not been derived from a secret")
+	}
+
+	secret, err := kp.pkcs8Secret()
+	if err != nil {
+		return Keystore{}, err
+	}
+
+	address, err := kp.SS58Address(prefix)
+	if err != nil {
+		return Keystore{}, err
+	}
+
+	salt := make([]byte, scryptSaltLength)
+	var nonce [nonceLength]byte
+	if _, err := rand.Read(salt); err != nil {
+		return Keystore{}, err
+	}
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return Keystore{}, err
+	}
+
+	key, err := scryptKey(password, salt, scryptN, scryptP, scryptR)
+	if err != nil {
+		return Keystore{}, err
+	}
+
+	encoded := make([]byte, scryptParamsLength, scryptParamsLength+nonceLength)
+	copy(encoded, salt)
+	binary.LittleEndian.PutUint32(encoded[scryptSaltLength:], scryptN)
+	binary.LittleEndian.PutUint32(encoded[scryptSaltLength+4:], scryptP)
+	binary.LittleEndian.PutUint32(encoded[scryptSaltLength+8:], scryptR)
+	encoded = append(encoded, nonce[:]...)
+
+	pkcs8 := append(append(append(append([]byte{}, pkcs8Header...), secret...), pkcs8Divider...), kp.PublicKey()...)
+	encoded = secretbox.Seal(encoded, pkcs8, &nonce, &key)
+
+	return Keystore{
+		Encoded: base64.StdEncoding.EncodeToString(encoded),
+		Encoding: KeystoreEncoding{
+			Content: []string{"pkcs8", kp.Scheme.String()},
+			Type:    []string{"scrypt", "xsalsa20-poly1305"},
+			Version: keystoreVersion,
+		},
+		Address: address,
+		Meta:    map[string]interface{}{"whenCreated": time.Now().UnixNano() / int64(time.Millisecond)},
+	}, nil
+}
+
+// Decrypt decrypts the key pair of the keystore with password
+func (k Keystore) Decrypt(password string) (KeyPair, error) {
+	if len(k.Encoding.Content) != 2 || k.Encoding.Content[0] != "pkcs8" {
+		return KeyPair{}, fmt.Errorf("unsupported keystore content %v", k.Encoding.Content)
+	}
+
+	var scheme Scheme
+	switch k.Encoding.Content[1] {
+	case Sr25519.String():
+		scheme = Sr25519
+	case Ed25519.String():
+		scheme = Ed25519
+	case Ecdsa.String():
+		scheme = Ecdsa
+	default:
+		return KeyPair{}, fmt.Errorf("unsupported keystore key type %v", k.Encoding.Content[1])
+	}
+
+	encoded, err := k.decodeEncoded()
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	pkcs8, err := k.decrypt(encoded, password)
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	secret, publicKey, err := decodePKCS8(pkcs8)
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	kp, err := keyPairFromPKCS8Secret(scheme, secret)
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	if !bytes.Equal(kp.PublicKey(), publicKey) {
+		return KeyPair{}, fmt.Errorf("public key of the keystore does not match its secret")
+	}
+	return kp, nil
+}
+
+func (k Keystore) decodeEncoded() ([]byte, error) {
+	// keystores before version 3 may be hex encoded
+	if strings.HasPrefix(k.Encoded, "0x") {
+		bz, err := hex.DecodeString(k.Encoded[2:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid encoded keystore: %v", err)
+		}
+		return bz, nil
+	}
+
+	bz, err := base64.StdEncoding.DecodeString(k.Encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encoded keystore: %v", err)
+	}
+	return bz, nil
+}
+
+func (k Keystore) decrypt(encoded []byte, password string) ([]byte, error) {
+	var isScrypt, isXSalsa20 bool
+	for _, t := range k.Encoding.Type {
+		switch t {
+		case "scrypt":
+			isScrypt = true
+		case "xsalsa20-poly1305":
+			isXSalsa20 = true
+		case "none":
+		default:
+			return nil, fmt.Errorf("unsupported keystore encryption %v", t)
+		}
+	}
+
+	if !isXSalsa20 {
+		if isScrypt {
+			return nil, fmt.Errorf("unsupported keystore encryption %v", k.Encoding.Type)
+		}
+		return encoded, nil
+	}
+
+	var key [32]byte
+	if isScrypt {
+		if len(encoded) < scryptParamsLength {
+			return nil, fmt.Errorf("encoded keystore is too short")
+		}
+		n := binary.LittleEndian.Uint32(encoded[scryptSaltLength:])
+		p := binary.LittleEndian.Uint32(encoded[scryptSaltLength+4:])
+		r := binary.LittleEndian.Uint32(encoded[scryptSaltLength+8:])
+		// like polkadot-js, only its own parameters are accepted, as larger ones make scrypt exhaust the memory
+		if n != scryptN || p != scryptP || r != scryptR {
+			return nil, fmt.Errorf("invalid scrypt parameters N=%v, p=%v, r=%v", n, p, r)
+		}
+
+		var err error
+		key, err = scryptKey(password, encoded[:scryptSaltLength], scryptN, scryptP, scryptR)
+		if err != nil {
+			return nil, err
+		}
+		encoded = encoded[scryptParamsLength:]
+	} else {
+		// without scrypt, the password is used as key, padded with zeros
+		copy(key[:], password)
+	}
+
+	if len(encoded) < nonceLength {
+		return nil, fmt.Errorf("encoded keystore is too short")
+	}
+	var nonce [nonceLength]byte
+	copy(nonce[:], encoded)
+
+	decrypted, ok := secretbox.Open(nil, encoded[nonceLength:], &nonce, &key)
+	if !ok {
+		return nil, fmt.Errorf("unable to decrypt keystore, the password is wrong")
+	}
+	return decrypted, nil
+}
+
+func scryptKey(password string, salt []byte, n, p, r int) ([32]byte, error) {
+	var key [32]byte
+	bz, err := scrypt.Key([]byte(password), salt, n, r, p, len(key))
+	if err != nil {
+		return key, fmt.Errorf("invalid scrypt parameters: %v", err)
+	}
+	copy(key[:], bz)
+	return key, nil
+}
+
+// decodePKCS8 splits a PKCS8 encoded key pair into the secret, which has 64 bytes or 32 bytes for seeds, and the
+// public key
+func decodePKCS8(pkcs8 []byte) (secret, publicKey []byte, err error) {
+	if !bytes.HasPrefix(pkcs8, pkcs8Header) {
+		return nil, nil, fmt.Errorf("invalid PKCS8 header of keystore")
+	}
+	body := pkcs8[len(pkcs8Header):]
+
+	for _, secretLength := range []int{64, 32} {
+		if len(body) > secretLength && bytes.HasPrefix(body[secretLength:], pkcs8Divider) {
+			return body[:secretLength], body[secretLength+len(pkcs8Divider):], nil
+		}
+	}
+	return nil, nil, fmt.Errorf("invalid PKCS8 divider of keystore")
+}
+
+// pkcs8Secret returns the secret of the key pair as stored by polkadot-js, which is the secret key in ed25519 format
+// followed by the nonce for sr25519, the seed followed by the public key for ed25519 and the private key for ecdsa
+func (k KeyPair) pkcs8Secret() ([]byte, error) {
+	seed := k.keyPair.Seed()
+
+	switch k.Scheme {
+	case Sr25519:
+		switch len(seed) {
+		case 32:
+			h := sha512.Sum512(seed)
+			h[0] &= 248
+			h[31] &= 63
+			h[31] |= 64
+			return h[:], nil
+		case 64:
+			secret := append([]byte{}, seed...)
+			multiplyScalarByCofactor(secret[:32])
+			return secret, nil
+		default:
+			return nil, fmt.Errorf("the secret of sr25519 key pairs derived with soft junctions can't be exported")
+		}
+	case Ed25519:
+		return append(append([]byte{}, seed...), k.PublicKey()...), nil
+	case Ecdsa:
+		return seed, nil
+	default:
+		return nil, fmt.Errorf("unsupported signature scheme %v", k.Scheme)
+	}
+}
+
+// keyPairFromPKCS8Secret is the inverse of KeyPair.pkcs8Secret
+func keyPairFromPKCS8Secret(scheme Scheme, secret []byte) (KeyPair, error) {
+	var seed []byte
+	switch {
+	case scheme == Sr25519 && len(secret) == 64:
+		seed = append([]byte{}, secret...)
+		divideScalarByCofactor(seed[:32])
+	case scheme == Ed25519 && len(secret) == 64:
+		seed = secret[:32]
+	case len(secret) == 32:
+		seed = secret
+	default:
+		return KeyPair{}, fmt.Errorf("invalid %v secret length %v", scheme, len(secret))
+	}
+
+	return KeyPairFromSecret(scheme, "0x"+hex.EncodeToString(seed))
+}
+
+// multiplyScalarByCofactor multiplies the little endian scalar s by 8 in place
+func multiplyScalarByCofactor(s []byte) {
+	high := byte(0)
+	for i := range s {
+		r := s[i] & 0xe0
+		s[i] <<= 3
+		s[i] += high
+		high = r >> 5
+	}
+}
+
+// divideScalarByCofactor divides the little endian scalar s by 8 in place
+func divideScalarByCofactor(s []byte) {
+	low := byte(0)
+	for i := len(s) - 1; i >= 0; i-- {
+		r := s[i] & 0x07
+		s[i] >>= 3
+		s[i] += low
+		low = r << 5
+	}
+}
@@ -0,0 +1,268 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature_test
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/signature"
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/ss58"
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+var (
+	testPKCS8Header  = []byte{48, 83, 2, 1, 1, 48, 5, 6, 3, 43, 101, 112, 4, 34, 4, 32}
+	testPKCS8Divider = []byte{161, 35, 3, 33, 0}
+	// the seed of //Alice for sr25519
+	testAliceSeed = types.MustHexDecodeString("0xe5be9a5092b81bca64be81d212e7f2f9eba183bb7a90954f7b76361f6edb5c0a")
+)
+
+func testPKCS8(secret, publicKey []byte) []byte {
+	return append(append(append(append([]byte{}, testPKCS8Header...), secret...), testPKCS8Divider...), publicKey...)
+}
+
+func TestKeystore_EncryptDecrypt(t *testing.T) {
+	for _, scheme := range []Scheme{Sr25519, Ed25519, Ecdsa} {
+		kp, err := KeyPairFromSecret(scheme, "//Alice")
+		assert.NoError(t, err)
+
+		ks, err := EncryptKeyPair(kp, "secret", 42)
+		assert.NoError(t, err)
+		assert.Equal(t, KeystoreEncoding{
+			Content: []string{"pkcs8", scheme.String()},
+			Type:    []string{"scrypt", "xsalsa20-poly1305"},
+			Version: "3",
+		}, ks.Encoding)
+		address, err := kp.SS58Address(42)
+		assert.NoError(t, err)
+		assert.Equal(t, address, ks.Address)
+
+		bz, err := json.Marshal(ks)
+		assert.NoError(t, err)
+		var decoded Keystore
+		err = json.Unmarshal(bz, &decoded)
+		assert.NoError(t, err)
+
+		decrypted, err := decoded.Decrypt("secret")
+		assert.NoError(t, err)
+		assert.Equal(t, kp.PublicKey(), decrypted.PublicKey(), scheme.String())
+
+		data := []byte("hello")
+		sig, err := decrypted.Sign(data)
+		assert.NoError(t, err)
+		ok, err := VerifyWithPublicKey(scheme, data, sig, kp.PublicKey())
+		assert.NoError(t, err)
+		assert.True(t, ok, scheme.String())
+
+		_, err = decoded.Decrypt("wrong")
+		assert.EqualError(t, err, "unable to decrypt keystore, the password is wrong")
+	}
+}
+
+func TestEncryptKeyPair_Prefix(t *testing.T) {
+	kp, err := KeyPairFromSecret(Ed25519, "//Alice")
+	assert.NoError(t, err)
+
+	for _, prefix := range []uint16{88, 136, 1284} {
+		ks, err := EncryptKeyPair(kp, "secret", prefix)
+		assert.NoError(t, err)
+		decodedPrefix, accountID, err := ss58.Decode(ks.Address)
+		assert.NoError(t, err)
+		assert.Equal(t, prefix, decodedPrefix)
+		assert.Equal(t, kp.AccountID(), accountID)
+	}
+}
+
+func TestKeystore_Sr25519Secret(t *testing.T) {
+	// polkadot-js stores the expanded sr25519 secret key in ed25519 format
+	h := sha512.Sum512(testAliceSeed)
+	h[0] &= 248
+	h[31] &= 63
+	h[31] |= 64
+
+	ks := Keystore{
+		Encoded: base64.StdEncoding.EncodeToString(testPKCS8(h[:], TestKeyringPairAlice.PublicKey)),
+		Encoding: KeystoreEncoding{
+			Content: []string{"pkcs8", "sr25519"},
+			Type:    []string{"none"},
+			Version: "3",
+		},
+	}
+	kp, err := ks.Decrypt("")
+	assert.NoError(t, err)
+	assert.Equal(t, TestKeyringPairAlice.PublicKey, kp.PublicKey())
+
+	// old keystores store the seed
+	ks.Encoded = base64.StdEncoding.EncodeToString(testPKCS8(testAliceSeed, TestKeyringPairAlice.PublicKey))
+	kp, err = ks.Decrypt("")
+	assert.NoError(t, err)
+	assert.Equal(t, TestKeyringPairAlice.PublicKey, kp.PublicKey())
+
+	bob, err := KeyPairFromSecret(Sr25519, "//Bob")
+	assert.NoError(t, err)
+	ks.Encoded = base64.StdEncoding.EncodeToString(testPKCS8(testAliceSeed, bob.PublicKey()))
+	_, err = ks.Decrypt("")
+	assert.EqualError(t, err, "public key of the keystore does not match its secret")
+}
+
+func TestKeystore_WithoutScrypt(t *testing.T) {
+	kp, err := KeyPairFromSecret(Ed25519, "//Alice")
+	assert.NoError(t, err)
+	seed := types.MustHexDecodeString("0xabf8e5bdbe30c65656c0a3cbd181ff8a56294a69dfedd27982aace4a76909115")
+
+	// without scrypt, the password padded to 32 bytes is the key
+	var key [32]byte
+	copy(key[:], "secret")
+	var nonce [24]byte
+	nonce[0] = 1
+	pkcs8 := testPKCS8(append(append([]byte{}, seed...), kp.PublicKey()...), kp.PublicKey())
+	encoded := secretbox.Seal(append([]byte{}, nonce[:]...), pkcs8, &nonce, &key)
+
+	ks := Keystore{
+		Encoded: types.HexEncodeToString(encoded),
+		Encoding: KeystoreEncoding{
+			Content: []string{"pkcs8", "ed25519"},
+			Type:    []string{"xsalsa20-poly1305"},
+			Version: "2",
+		},
+	}
+	decrypted, err := ks.Decrypt("secret")
+	assert.NoError(t, err)
+	assert.Equal(t, kp.PublicKey(), decrypted.PublicKey())
+}
+
+func TestKeystore_Errors(t *testing.T) {
+	kp, err := KeyPairFromSecret(Sr25519, "//Alice/soft")
+	assert.NoError(t, err)
+	_, err = EncryptKeyPair(kp, "secret", 42)
+	assert.EqualError(t, err, "the secret of sr25519 key pairs derived with soft junctions can't be exported")
+
+	_, err = EncryptKeyPair(KeyPair{}, "secret", 42)
+	assert.EqualError(t, err, "key pair has not been derived from a secret")
+
+	ks := Keystore{Encoding: KeystoreEncoding{Content: []string{"pkcs8", "ethereum"}}}
+	_, err = ks.Decrypt("secret")
+	assert.EqualError(t, err, "unsupported keystore key type ethereum")
+
+	ks.Encoding = KeystoreEncoding{Content: []string{"pkcs8", "sr25519"}, Type: []string{"aes"}}
+	_, err = ks.Decrypt("secret")
+	assert.EqualError(t, err, "unsupported keystore encryption aes")
+
+	ks.Encoding.Type = []string{"none"}
+	ks.Encoded = base64.StdEncoding.EncodeToString([]byte{1, 2, 3})
+	_, err = ks.Decrypt("secret")
+	assert.EqualError(t, err, "invalid PKCS8 header of keystore")
+}
+
+func TestKeystore_ScryptParams(t *testing.T) {
+	kp, err := KeyPairFromSecret(Sr25519, "//Alice")
+	assert.NoError(t, err)
+	ks, err := EncryptKeyPair(kp, "secret", 42)
+	assert.NoError(t, err)
+	encoded, err := base64.StdEncoding.DecodeString(ks.Encoded)
+	assert.NoError(t, err)
+
+	// the salt is followed by N, p and r, parameters this large would make scrypt allocate about 1 TiB
+	for _, params := range [][3]uint32{{1 << 30, 1, 8}, {1 << 15, 2, 8}, {1 << 15, 1, 1 << 20}} {
+		tampered := append([]byte{}, encoded...)
+		binary.LittleEndian.PutUint32(tampered[32:], params[0])
+		binary.LittleEndian.PutUint32(tampered[36:], params[1])
+		binary.LittleEndian.PutUint32(tampered[40:], params[2])
+		ks.Encoded = base64.StdEncoding.EncodeToString(tampered)
+
+		_, err = ks.Decrypt("secret")
+		assert.EqualError(t, err, fmt.Sprintf("invalid scrypt parameters N=%v, p=%v, r=%v", params[0], params[1],
+			params[2]))
+	}
+}
+
+func TestLoadKeystore(t *testing.T) {
+	kp, err := KeyPairFromSecret(Sr25519, "//Alice")
+	assert.NoError(t, err)
+	ks, err := EncryptKeyPair(kp, "secret", 42)
+	assert.NoError(t, err)
+	ks.Meta["name"] = "alice"
+
+	dir, err := ioutil.TempDir("", "keystore")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "alice.json")
+	bz, err := json.Marshal(ks)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(path, bz, 0600))
+
+	loaded, err := LoadKeystore(path)
+	assert.NoError(t, err)
+	assert.Equal(t, ks.Encoded, loaded.Encoded)
+	assert.Equal(t, "alice", loaded.Meta["name"])
+
+	decrypted, err := loaded.Decrypt("secret")
+	assert.NoError(t, err)
+	assert.Equal(t, kp.PublicKey(), decrypted.PublicKey())
+
+	_, err = LoadKeystore(filepath.Join(dir, "bob.json"))
+	assert.Error(t, err)
+}
+
+func TestKeystore_Fixtures(t *testing.T) {
+	// keystores in the polkadot-js v3 format of the //Alice key pairs of the dev chain
+	for _, test := range []struct {
+		file     string
+		password string
+		scheme   Scheme
+		address  string
+	}{
+		{"alice-sr25519.json", "sr25519 password", Sr25519, "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"},
+		{"alice-ed25519.json", "ed25519 password", Ed25519, "5FA9nQDVg267DEd8m1ZypXLBnvN7SFxYwV7ndqSYGiN9TTpu"},
+		{"alice-ecdsa.json", "ecdsa password", Ecdsa, "5C7C2Z5sWbytvHpuLTvzKunnnRwQxft1jiqrLD5rhucQ5S9X"},
+	} {
+		ks, err := LoadKeystore(filepath.Join("testdata", test.file))
+		assert.NoError(t, err)
+		assert.Equal(t, test.address, ks.Address)
+		assert.Equal(t, []string{"pkcs8", test.scheme.String()}, ks.Encoding.Content)
+
+		kp, err := ks.Decrypt(test.password)
+		assert.NoError(t, err, test.file)
+		assert.Equal(t, test.scheme, kp.Scheme)
+		address, err := kp.SS58Address(42)
+		assert.NoError(t, err)
+		assert.Equal(t, test.address, address, test.file)
+
+		alice, err := KeyPairFromSecret(test.scheme, "//Alice")
+		assert.NoError(t, err)
+		data := []byte("hello")
+		sig, err := kp.Sign(data)
+		assert.NoError(t, err)
+		ok, err := VerifyWithPublicKey(test.scheme, data, sig, alice.PublicKey())
+		assert.NoError(t, err)
+		assert.True(t, ok, test.file)
+
+		_, err = ks.Decrypt("wrong")
+		assert.EqualError(t, err, "unable to decrypt keystore, the password is wrong")
+	}
+}
@@ -0,0 +1,53 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cosmos/go-bip39"
+)
+
+// GenerateMnemonic generates a random BIP39 mnemonic of 12 or 24 words, which can be used as secret of
+// KeyPairFromSecret and DeriveKeyPair
+func GenerateMnemonic(words int) (string, error) {
+	var bits int
+	switch words {
+	case 12:
+		bits = 128
+	case 24:
+		bits = 256
+	default:
+		return "", fmt.Errorf("mnemonics must have 12 or 24 words, got %v", words)
+	}
+
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// ValidateMnemonic returns an error if phrase is not a BIP39 mnemonic of the english word list with a valid checksum
+func ValidateMnemonic(phrase string) error {
+	_, err := bip39.MnemonicToByteArray(strings.Join(strings.Fields(phrase), " "))
+	if err != nil {
+		return fmt.Errorf("invalid mnemonic")
+	}
+	return nil
+}
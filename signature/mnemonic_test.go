@@ -0,0 +1,53 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+const devPhrase = "bottom drive obey lake curtain smoke basket hold race lonely fit walk"
+
+func TestGenerateMnemonic(t *testing.T) {
+	for _, words := range []int{12, 24} {
+		phrase, err := GenerateMnemonic(words)
+		assert.NoError(t, err)
+		assert.Len(t, strings.Fields(phrase), words)
+		assert.NoError(t, ValidateMnemonic(phrase))
+
+		_, err = KeyPairFromSecret(Sr25519, phrase)
+		assert.NoError(t, err)
+	}
+
+	_, err := GenerateMnemonic(15)
+	assert.EqualError(t, err, "mnemonics must have 12 or 24 words, got 15")
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	assert.NoError(t, ValidateMnemonic(devPhrase))
+
+	// the checksum does not match
+	assert.EqualError(t, ValidateMnemonic("drive bottom obey lake curtain smoke basket hold race lonely fit walk"),
+		"invalid mnemonic")
+	assert.EqualError(t, ValidateMnemonic("bottom drive obey lake"), "invalid mnemonic")
+	assert.EqualError(t, ValidateMnemonic("0xe5be9a5092b81bca64be81d212e7f2f9eba183bb7a90954f7b76361f6edb5c0a"),
+		"invalid mnemonic")
+}
@@ -0,0 +1,137 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	schnorrkel "github.com/ChainSafe/go-schnorrkel"
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/ss58"
+	secp256k1 "github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/ed25519"
+)
+
+// VerifyWithPublicKey verifies the signature sig of data made by the key pair of scheme with the given public key,
+// hashing data with blake2b-256 first if it is longer than 256 bytes like Sign does. The ecdsa public key is compressed
+// to 33 bytes.
+func VerifyWithPublicKey(scheme Scheme, data, sig, publicKey []byte) (bool, error) {
+	data = signingPayload(data)
+
+	switch scheme {
+	case Ed25519:
+		if len(publicKey) != ed25519.PublicKeySize {
+			return false, fmt.Errorf("ed25519 public key must have %v bytes, got %v", ed25519.PublicKeySize,
+				len(publicKey))
+		}
+		if len(sig) != ed25519.SignatureSize {
+			return false, errors.New("wrong signature length")
+		}
+		return ed25519.Verify(publicKey, data, sig), nil
+	case Sr25519:
+		if len(publicKey) != 32 {
+			return false, fmt.Errorf("sr25519 public key must have 32 bytes, got %v", len(publicKey))
+		}
+		if len(sig) != 64 {
+			return false, errors.New("wrong signature length")
+		}
+		return verifySr25519(data, sig, publicKey)
+	case Ecdsa:
+		if len(publicKey) != 33 {
+			return false, fmt.Errorf("ecdsa public key must have 33 bytes, got %v", len(publicKey))
+		}
+		if len(sig) != 65 {
+			return false, errors.New("wrong signature length")
+		}
+		digest := blake2b.Sum256(data)
+		return secp256k1.VerifySignature(publicKey, digest[:], sig[:64]), nil
+	default:
+		return false, fmt.Errorf("unsupported signature scheme %v", scheme)
+	}
+}
+
+// VerifyWithAccountID verifies the signature sig of data made by the key pair of scheme with the given account id, see
+// VerifyWithPublicKey. The account id of ecdsa key pairs is the hash of the public key, which is recovered from sig.
+func VerifyWithAccountID(scheme Scheme, data, sig, accountID []byte) (bool, error) {
+	if len(accountID) != 32 {
+		return false, fmt.Errorf("account id must have 32 bytes, got %v", len(accountID))
+	}
+
+	if scheme != Ecdsa {
+		return VerifyWithPublicKey(scheme, data, sig, accountID)
+	}
+
+	if len(sig) != 65 {
+		return false, errors.New("wrong signature length")
+	}
+
+	// go-ethereum expects the recovery id to be 0 or 1
+	recoverable := make([]byte, 65)
+	copy(recoverable, sig)
+	if recoverable[64] >= 27 {
+		recoverable[64] -= 27
+	}
+
+	digest := blake2b.Sum256(signingPayload(data))
+	pub, err := secp256k1.SigToPub(digest[:], recoverable)
+	if err != nil {
+		return false, nil
+	}
+
+	hash := blake2b.Sum256(secp256k1.CompressPubkey(pub))
+	return bytes.Equal(hash[:], accountID), nil
+}
+
+// VerifyWithSS58Address verifies the signature sig of data made by the key pair of scheme with the account id encoded
+// in the given SS58 address of any network, see VerifyWithAccountID
+func VerifyWithSS58Address(scheme Scheme, data, sig []byte, address string) (bool, error) {
+	_, accountID, err := ss58.DecodeAccountID(address)
+	if err != nil {
+		return false, err
+	}
+	return VerifyWithAccountID(scheme, data, sig, accountID)
+}
+
+// signingPayload returns data, or its blake2b-256 hash if it is longer than 256 bytes
+func signingPayload(data []byte) []byte {
+	if len(data) > 256 {
+		h := blake2b.Sum256(data)
+		return h[:]
+	}
+	return data
+}
+
+func verifySr25519(data, sig, publicKey []byte) (bool, error) {
+	var pk [32]byte
+	copy(pk[:], publicKey)
+	pub := new(schnorrkel.PublicKey)
+	err := pub.Decode(pk)
+	if err != nil {
+		return false, err
+	}
+
+	var s [64]byte
+	copy(s[:], sig)
+	signature := new(schnorrkel.Signature)
+	if err := signature.Decode(s); err != nil {
+		return false, nil
+	}
+
+	return pub.Verify(signature, schnorrkel.NewSigningContext([]byte("substrate"), data)), nil
+}
@@ -0,0 +1,108 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature_test
+
+import (
+	"testing"
+
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify_Schemes(t *testing.T) {
+	short := []byte("hello")
+	long := make([]byte, 300)
+
+	for _, scheme := range []Scheme{Ed25519, Sr25519, Ecdsa} {
+		kp, err := KeyPairFromSecret(scheme, "//Alice")
+		assert.NoError(t, err)
+		bob, err := KeyPairFromSecret(scheme, "//Bob")
+		assert.NoError(t, err)
+		address, err := kp.SS58Address(42)
+		assert.NoError(t, err)
+
+		for _, data := range [][]byte{short, long} {
+			sig, err := kp.Sign(data)
+			assert.NoError(t, err)
+
+			ok, err := VerifyWithPublicKey(scheme, data, sig, kp.PublicKey())
+			assert.NoError(t, err)
+			assert.True(t, ok, scheme.String())
+
+			ok, err = VerifyWithAccountID(scheme, data, sig, kp.AccountID())
+			assert.NoError(t, err)
+			assert.True(t, ok, scheme.String())
+
+			ok, err = VerifyWithSS58Address(scheme, data, sig, address)
+			assert.NoError(t, err)
+			assert.True(t, ok, scheme.String())
+
+			// the signature does not match other data or other keys
+			ok, err = VerifyWithPublicKey(scheme, append([]byte{1}, data...), sig, kp.PublicKey())
+			assert.NoError(t, err)
+			assert.False(t, ok, scheme.String())
+
+			ok, err = VerifyWithPublicKey(scheme, data, sig, bob.PublicKey())
+			assert.NoError(t, err)
+			assert.False(t, ok, scheme.String())
+
+			ok, err = VerifyWithAccountID(scheme, data, sig, bob.AccountID())
+			assert.NoError(t, err)
+			assert.False(t, ok, scheme.String())
+		}
+	}
+}
+
+func TestVerify_KeyringPair(t *testing.T) {
+	data := []byte("hello")
+	sig, err := Sign(data, TestKeyringPairAlice.URI)
+	assert.NoError(t, err)
+
+	ok, err := VerifyWithAccountID(Sr25519, data, sig, TestKeyringPairAlice.PublicKey)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = VerifyWithSS58Address(Sr25519, data, sig, TestKeyringPairAlice.Address)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// the address of Alice on Polkadot
+	ok, err = VerifyWithSS58Address(Sr25519, data, sig, "15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6Sp5")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestVerify_Errors(t *testing.T) {
+	kp, err := KeyPairFromSecret(Ecdsa, "//Alice")
+	assert.NoError(t, err)
+
+	_, err = VerifyWithPublicKey(Ecdsa, []byte{1}, make([]byte, 65), kp.AccountID())
+	assert.EqualError(t, err, "ecdsa public key must have 33 bytes, got 32")
+
+	_, err = VerifyWithPublicKey(Sr25519, []byte{1}, make([]byte, 65), make([]byte, 32))
+	assert.EqualError(t, err, "wrong signature length")
+
+	_, err = VerifyWithAccountID(Ed25519, []byte{1}, make([]byte, 64), make([]byte, 20))
+	assert.EqualError(t, err, "account id must have 32 bytes, got 20")
+
+	_, err = VerifyWithPublicKey(Scheme(7), []byte{1}, nil, nil)
+	assert.EqualError(t, err, "unsupported signature scheme Scheme(7)")
+
+	_, err = VerifyWithSS58Address(Sr25519, []byte{1}, make([]byte, 64),
+		"5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQZ")
+	assert.EqualError(t, err, "invalid checksum of SS58 address 5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQZ")
+}
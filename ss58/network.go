@@ -0,0 +1,129 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ss58
+
+import "sync"
+
+// Prefixes of well known networks
+const (
+	PolkadotPrefix  uint16 = 0
+	KusamaPrefix    uint16 = 2
+	PhalaPrefix     uint16 = 30
+	SubstratePrefix uint16 = 42
+)
+
+// Network is a network of the SS58 registry
+type Network struct {
+	// Prefix is the SS58 prefix of addresses on the network
+	Prefix uint16
+	// Name is the name of the network in the SS58 registry, e.g. polkadot
+	Name string
+	// DisplayName is the human readable name of the network, e.g. Polkadot Relay Chain
+	DisplayName string
+}
+
+var (
+	// networksMu guards networks, which RegisterNetwork may change concurrently with lookups
+	networksMu sync.RWMutex
+	networks   = defaultNetworks()
+)
+
+// defaultNetworks returns the known networks of the SS58 registry at https://github.com/paritytech/ss58-registry
+func defaultNetworks() []Network {
+	return []Network{
+		{Prefix: 0, Name: "polkadot", DisplayName: "Polkadot Relay Chain"},
+		{Prefix: 2, Name: "kusama", DisplayName: "Kusama Relay Chain"},
+		{Prefix: 5, Name: "astar", DisplayName: "Astar Network"},
+		{Prefix: 6, Name: "bifrost", DisplayName: "Bifrost"},
+		{Prefix: 7, Name: "edgeware", DisplayName: "Edgeware"},
+		{Prefix: 8, Name: "karura", DisplayName: "Karura"},
+		{Prefix: 10, Name: "acala", DisplayName: "Acala"},
+		{Prefix: 12, Name: "polymesh", DisplayName: "Polymesh"},
+		{Prefix: 16, Name: "kulupu", DisplayName: "Kulupu"},
+		{Prefix: 18, Name: "darwinia", DisplayName: "Darwinia Network"},
+		{Prefix: 20, Name: "stafi", DisplayName: "StaFi"},
+		{Prefix: 28, Name: "subsocial", DisplayName: "Subsocial"},
+		{Prefix: 30, Name: "phala", DisplayName: "Phala Network"},
+		{Prefix: 32, Name: "robonomics", DisplayName: "Robonomics"},
+		{Prefix: 36, Name: "centrifuge", DisplayName: "Centrifuge Chain"},
+		{Prefix: 37, Name: "nodle", DisplayName: "Nodle Chain"},
+		{Prefix: 42, Name: "substrate", DisplayName: "Substrate"},
+		{Prefix: 44, Name: "chainx", DisplayName: "ChainX"},
+		{Prefix: 63, Name: "hydradx", DisplayName: "HydraDX"},
+		{Prefix: 88, Name: "polkadex", DisplayName: "Polkadex Mainnet"},
+		{Prefix: 136, Name: "altair", DisplayName: "Altair"},
+		{Prefix: 172, Name: "parallel", DisplayName: "Parallel"},
+		{Prefix: 255, Name: "quartz_mainnet", DisplayName: "QUARTZ by UNIQUE"},
+		{Prefix: 1284, Name: "moonbeam", DisplayName: "Moonbeam"},
+		{Prefix: 1285, Name: "moonriver", DisplayName: "Moonriver"},
+		{Prefix: 2032, Name: "interlay", DisplayName: "Interlay"},
+		{Prefix: 2092, Name: "kintsugi", DisplayName: "Kintsugi"},
+		{Prefix: 7391, Name: "unique_mainnet", DisplayName: "Unique Network"},
+		{Prefix: 10041, Name: "basilisk", DisplayName: "Basilisk"},
+	}
+}
+
+// Networks returns the known networks, the networks of the SS58 registry and the networks added with RegisterNetwork
+func Networks() []Network {
+	networksMu.RLock()
+	defer networksMu.RUnlock()
+
+	ns := make([]Network, len(networks))
+	copy(ns, networks)
+	return ns
+}
+
+// RegisterNetwork adds a network to the known networks, replacing a known network with the same prefix. It is safe
+// for concurrent use.
+func RegisterNetwork(network Network) {
+	networksMu.Lock()
+	defer networksMu.Unlock()
+
+	for i, n := range networks {
+		if n.Prefix == network.Prefix {
+			networks[i] = network
+			return
+		}
+	}
+	networks = append(networks, network)
+}
+
+// NetworkByPrefix returns the known network with the given prefix
+func NetworkByPrefix(prefix uint16) (Network, bool) {
+	networksMu.RLock()
+	defer networksMu.RUnlock()
+
+	for _, n := range networks {
+		if n.Prefix == prefix {
+			return n, true
+		}
+	}
+	return Network{}, false
+}
+
+// NetworkByName returns the known network with the given name of the SS58 registry
+func NetworkByName(name string) (Network, bool) {
+	networksMu.RLock()
+	defer networksMu.RUnlock()
+
+	for _, n := range networks {
+		if n.Name == name {
+			return n, true
+		}
+	}
+	return Network{}, false
+}
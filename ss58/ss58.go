@@ -0,0 +1,141 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ss58 encodes and decodes SS58 addresses, the base58 encoding of account ids and public keys of Substrate
+// based chains, see https://docs.substrate.io/reference/address-formats/
+package ss58
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+	"golang.org/x/crypto/blake2b"
+)
+
+// MaxPrefix is the largest network prefix an SS58 address can encode
+const MaxPrefix = 1<<14 - 1
+
+var checksumPreimage = []byte("SS58PRE")
+
+// Encode encodes the payload, usually a 32 bytes account id, into an SS58 address with the given network prefix.
+// Prefixes up to 63 are encoded with one byte and larger prefixes with two.
+func Encode(payload []byte, prefix uint16) (string, error) {
+	checksumLength, err := checksumLength(len(payload))
+	if err != nil {
+		return "", err
+	}
+
+	var data []byte
+	switch {
+	case prefix < 64:
+		data = []byte{byte(prefix)}
+	case prefix <= MaxPrefix:
+		data = []byte{
+			byte((prefix&0x00fc)>>2) | 0x40,
+			byte(prefix>>8) | byte((prefix&0x0003)<<6),
+		}
+	default:
+		return "", fmt.Errorf("SS58 prefix %v is larger than %v", prefix, MaxPrefix)
+	}
+
+	data = append(data, payload...)
+	checksum, err := checksum(data)
+	if err != nil {
+		return "", err
+	}
+
+	return base58.Encode(append(data, checksum[:checksumLength]...)), nil
+}
+
+// Decode decodes an SS58 address into the network prefix and the payload, usually a 32 bytes account id, and
+// validates its checksum
+func Decode(address string) (prefix uint16, payload []byte, err error) {
+	data := base58.Decode(address)
+	if len(data) < 2 {
+		return 0, nil, fmt.Errorf("invalid SS58 address %v", address)
+	}
+
+	prefixLength := 1
+	switch {
+	case data[0] < 64:
+		prefix = uint16(data[0])
+	case data[0] < 128:
+		prefixLength = 2
+		lower := data[0]<<2 | data[1]>>6
+		upper := data[1] & 0x3f
+		prefix = uint16(lower) | uint16(upper)<<8
+	default:
+		return 0, nil, fmt.Errorf("invalid prefix of SS58 address %v", address)
+	}
+
+	n := 0
+	for _, payloadLength := range []int{1, 2, 4, 8, 32, 33} {
+		l, _ := checksumLength(payloadLength)
+		if len(data) == prefixLength+payloadLength+l {
+			n = l
+		}
+	}
+	if n == 0 {
+		return 0, nil, fmt.Errorf("invalid length of SS58 address %v", address)
+	}
+
+	body := data[:len(data)-n]
+	checksum, err := checksum(body)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !bytes.Equal(checksum[:n], data[len(body):]) {
+		return 0, nil, fmt.Errorf("invalid checksum of SS58 address %v", address)
+	}
+
+	return prefix, body[prefixLength:], nil
+}
+
+// DecodeAccountID decodes an SS58 address that encodes a 32 bytes account id, see Decode
+func DecodeAccountID(address string) (prefix uint16, accountID []byte, err error) {
+	prefix, accountID, err = Decode(address)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(accountID) != 32 {
+		return 0, nil, fmt.Errorf("SS58 address %v does not encode a 32 bytes account id", address)
+	}
+	return prefix, accountID, nil
+}
+
+// checksumLength returns the length of the checksum of payloads with the given length, which is 1 for account indices
+// and 2 for account ids and public keys
+func checksumLength(payloadLength int) (int, error) {
+	switch payloadLength {
+	case 1, 2, 4, 8:
+		return 1, nil
+	case 32, 33:
+		return 2, nil
+	default:
+		return 0, fmt.Errorf("invalid SS58 payload length %v", payloadLength)
+	}
+}
+
+func checksum(data []byte) ([]byte, error) {
+	h, err := blake2b.New512(nil)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(checksumPreimage)
+	h.Write(data)
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,153 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ss58_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/ss58"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/assert"
+)
+
+var alice = []byte{
+	0xd4, 0x35, 0x93, 0xc7, 0x15, 0xfd, 0xd3, 0x1c, 0x61, 0x14, 0x1a, 0xbd, 0x04, 0xa9, 0x9f, 0xd6,
+	0x82, 0x2c, 0x85, 0x58, 0x85, 0x4c, 0xcd, 0xe3, 0x9a, 0x56, 0x84, 0xe7, 0xa5, 0x6d, 0xa2, 0x7d,
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, test := range []struct {
+		prefix  uint16
+		address string
+	}{
+		{SubstratePrefix, "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"},
+		{PolkadotPrefix, "15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6Sp5"},
+		{KusamaPrefix, "HNZata7iMYWmk5RvZRTiAsSDhV8366zq2YGb3tLH5Upf74F"},
+	} {
+		address, err := Encode(alice, test.prefix)
+		assert.NoError(t, err)
+		assert.Equal(t, test.address, address)
+
+		prefix, accountID, err := DecodeAccountID(test.address)
+		assert.NoError(t, err)
+		assert.Equal(t, test.prefix, prefix)
+		assert.Equal(t, alice, accountID)
+	}
+}
+
+func TestEncodeDecode_TwoBytePrefix(t *testing.T) {
+	address, err := Encode(alice, 1284)
+	assert.NoError(t, err)
+	// the lower six bits of the prefix, then the upper eight bits and the remaining two
+	assert.Equal(t, []byte{0x41, 0x05}, base58.Decode(address)[:2])
+
+	for _, prefix := range []uint16{64, 255, 256, 1284, 2032, 7391, 10041, MaxPrefix} {
+		address, err := Encode(alice, prefix)
+		assert.NoError(t, err)
+
+		decoded, accountID, err := DecodeAccountID(address)
+		assert.NoError(t, err)
+		assert.Equal(t, prefix, decoded)
+		assert.Equal(t, alice, accountID)
+	}
+
+	_, err = Encode(alice, MaxPrefix+1)
+	assert.EqualError(t, err, "SS58 prefix 16384 is larger than 16383")
+}
+
+func TestEncodeDecode_Payloads(t *testing.T) {
+	for _, payload := range [][]byte{{1}, {1, 2}, {1, 2, 3, 4}, make([]byte, 8), make([]byte, 33)} {
+		address, err := Encode(payload, PhalaPrefix)
+		assert.NoError(t, err)
+
+		prefix, decoded, err := Decode(address)
+		assert.NoError(t, err)
+		assert.Equal(t, PhalaPrefix, prefix)
+		assert.Equal(t, payload, decoded)
+	}
+
+	_, err := Encode(make([]byte, 20), SubstratePrefix)
+	assert.EqualError(t, err, "invalid SS58 payload length 20")
+
+	index, err := Encode([]byte{1, 2, 3, 4}, SubstratePrefix)
+	assert.NoError(t, err)
+	_, _, err = DecodeAccountID(index)
+	assert.EqualError(t, err, "SS58 address "+index+" does not encode a 32 bytes account id")
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	_, _, err := Decode("5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQZ")
+	assert.EqualError(t, err, "invalid checksum of SS58 address 5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQZ")
+
+	short := base58.Encode(append([]byte{42}, make([]byte, 22)...))
+	_, _, err = Decode(short)
+	assert.EqualError(t, err, "invalid length of SS58 address "+short)
+
+	_, _, err = Decode("0OIl")
+	assert.EqualError(t, err, "invalid SS58 address 0OIl")
+}
+
+func TestNetworks(t *testing.T) {
+	n, ok := NetworkByPrefix(PhalaPrefix)
+	assert.True(t, ok)
+	assert.Equal(t, Network{Prefix: 30, Name: "phala", DisplayName: "Phala Network"}, n)
+
+	n, ok = NetworkByName("kusama")
+	assert.True(t, ok)
+	assert.Equal(t, KusamaPrefix, n.Prefix)
+
+	_, ok = NetworkByPrefix(9999)
+	assert.False(t, ok)
+
+	RegisterNetwork(Network{Prefix: 9999, Name: "test", DisplayName: "Test"})
+	n, ok = NetworkByName("test")
+	assert.True(t, ok)
+	assert.Equal(t, uint16(9999), n.Prefix)
+	assert.Contains(t, Networks(), n)
+
+	// the returned networks are a copy
+	networks := Networks()
+	networks[0].Name = "changed"
+	n, ok = NetworkByPrefix(networks[0].Prefix)
+	assert.True(t, ok)
+	assert.NotEqual(t, "changed", n.Name)
+}
+
+func TestRegisterNetwork_Concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			RegisterNetwork(Network{Prefix: uint16(10000 + i), Name: fmt.Sprintf("test%v", i)})
+		}(i)
+		go func() {
+			defer wg.Done()
+			NetworkByName("polkadot")
+			Networks()
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < 10; i++ {
+		n, ok := NetworkByPrefix(uint16(10000 + i))
+		assert.True(t, ok)
+		assert.Equal(t, fmt.Sprintf("test%v", i), n.Name)
+	}
+}
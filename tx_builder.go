@@ -0,0 +1,325 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gsrpc
+
+import (
+	"fmt"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/rpc/author"
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/ss58"
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+)
+
+// DefaultMortalPeriod is the number of blocks extrinsics built by a TxBuilder are valid for, unless set with
+// TxBuilder.WithMortalPeriod or TxBuilder.WithEra
+const DefaultMortalPeriod = 64
+
+// TxBuilder builds, signs and submits an extrinsic of a call. Signature options that are not set explicitly are
+// fetched from the node when the extrinsic is built: the genesis hash, the latest runtime version, the next nonce of
+// the signer through system_accountNextIndex and the latest finalized block, at which the mortal era of the extrinsic
+// starts, or a few blocks before it for periods above 4096 blocks.
+type TxBuilder struct {
+	api    *SubstrateAPI
+	call   types.Call
+	signer types.Signer
+
+	meta           *types.Metadata
+	genesisHash    *types.Hash
+	runtimeVersion *types.RuntimeVersion
+	nonce          *types.UCompact
+	era            *types.ExtrinsicEra
+	blockHash      *types.Hash
+	mortalPeriod   uint64
+	tip            types.UCompact
+	assetID        interface{}
+	metadataHash   types.OptionH256
+}
+
+// Tx is an extrinsic that has been submitted by a TxBuilder
+type Tx struct {
+	Extrinsic types.Extrinsic
+	Options   types.SignatureOptions
+	// Hash is the hash of the extrinsic
+	Hash types.Hash
+	// Status is the subscription to the status of the extrinsic, only set by TxBuilder.SubmitAndWatch
+	Status *author.ExtrinsicStatusSubscription
+}
+
+// NewTx creates a TxBuilder for an extrinsic of call, signed by signer. Use types.NewKeyringPairSigner to sign with a
+// signature.KeyringPair.
+func (api *SubstrateAPI) NewTx(call types.Call, signer types.Signer) *TxBuilder {
+	return &TxBuilder{
+		api:          api,
+		call:         call,
+		signer:       signer,
+		mortalPeriod: DefaultMortalPeriod,
+		tip:          types.NewUCompactFromUInt(0),
+	}
+}
+
+// WithMetadata sets the metadata used to encode the signed extensions instead of fetching the latest metadata
+func (b *TxBuilder) WithMetadata(meta *types.Metadata) *TxBuilder {
+	b.meta = meta
+	return b
+}
+
+// WithGenesisHash sets the genesis hash instead of fetching it
+func (b *TxBuilder) WithGenesisHash(genesisHash types.Hash) *TxBuilder {
+	b.genesisHash = &genesisHash
+	return b
+}
+
+// WithRuntimeVersion sets the runtime version providing the spec and transaction version instead of fetching the
+// latest runtime version
+func (b *TxBuilder) WithRuntimeVersion(rv types.RuntimeVersion) *TxBuilder {
+	b.runtimeVersion = &rv
+	return b
+}
+
+// WithNonce sets the nonce instead of fetching the next nonce of the signer
+func (b *TxBuilder) WithNonce(nonce uint64) *TxBuilder {
+	n := types.NewUCompactFromUInt(nonce)
+	b.nonce = &n
+	return b
+}
+
+// WithEra sets the era and the hash of the block the era starts at instead of starting a mortal era at the latest
+// finalized block
+func (b *TxBuilder) WithEra(era types.ExtrinsicEra, blockHash types.Hash) *TxBuilder {
+	b.era = &era
+	b.blockHash = &blockHash
+	return b
+}
+
+// WithImmortalEra makes the extrinsic immortal, which is valid until the nonce of the signer has been used
+func (b *TxBuilder) WithImmortalEra() *TxBuilder {
+	b.era = &types.ExtrinsicEra{IsImmortalEra: true}
+	b.blockHash = nil
+	return b
+}
+
+// WithMortalPeriod sets the number of blocks the mortal era of the extrinsic lasts, DefaultMortalPeriod by default
+func (b *TxBuilder) WithMortalPeriod(period uint64) *TxBuilder {
+	b.mortalPeriod = period
+	return b
+}
+
+// WithTip sets the tip paid to the block author, 0 by default
+func (b *TxBuilder) WithTip(tip types.UCompact) *TxBuilder {
+	b.tip = tip
+	return b
+}
+
+// WithAssetID sets the asset the fees are paid with on chains with the ChargeAssetTxPayment signed extension, the
+// native token by default
+func (b *TxBuilder) WithAssetID(assetID interface{}) *TxBuilder {
+	b.assetID = assetID
+	return b
+}
+
+// WithMetadataHash sets the hash of the metadata checked by the CheckMetadataHash signed extension, which is disabled
+// by default
+func (b *TxBuilder) WithMetadataHash(hash types.H256) *TxBuilder {
+	b.metadataHash = types.NewOptionH256(hash)
+	return b
+}
+
+// SignatureOptions returns the signature options of the extrinsic, fetching the options that are not set
+func (b *TxBuilder) SignatureOptions() (types.SignatureOptions, error) {
+	rpc := b.api.RPC
+
+	genesisHash := b.genesisHash
+	if genesisHash == nil {
+		h, err := rpc.Chain.GetBlockHash(0)
+		if err != nil {
+			return types.SignatureOptions{}, err
+		}
+		genesisHash = &h
+	}
+
+	rv := b.runtimeVersion
+	if rv == nil {
+		var err error
+		rv, err = rpc.State.GetRuntimeVersionLatest()
+		if err != nil {
+			return types.SignatureOptions{}, err
+		}
+	}
+
+	nonce := b.nonce
+	if nonce == nil {
+		addr := b.signer.MultiAddress()
+		if !addr.IsID {
+			return types.SignatureOptions{}, fmt.Errorf("the nonce of signers without an account id can't be " +
+				"fetched, set it with WithNonce")
+		}
+		// system_accountNextIndex only accepts SS58 addresses, some chains only with their own prefix
+		prefix, err := b.ss58Prefix()
+		if err != nil {
+			return types.SignatureOptions{}, err
+		}
+		address, err := ss58.Encode(addr.AsID[:], prefix)
+		if err != nil {
+			return types.SignatureOptions{}, err
+		}
+		index, err := rpc.System.AccountNextIndex(address)
+		if err != nil {
+			return types.SignatureOptions{}, err
+		}
+		n := types.NewUCompactFromUInt(uint64(index))
+		nonce = &n
+	}
+
+	era, blockHash := b.era, b.blockHash
+	switch {
+	case era == nil:
+		finalized, err := rpc.Chain.GetFinalizedHead()
+		if err != nil {
+			return types.SignatureOptions{}, err
+		}
+		header, err := rpc.Chain.GetHeader(finalized)
+		if err != nil {
+			return types.SignatureOptions{}, err
+		}
+		mortal := types.ExtrinsicEra{
+			IsMortalEra: true,
+			AsMortalEra: types.NewMortalEra(uint64(header.Number), b.mortalPeriod),
+		}
+		era, blockHash = &mortal, &finalized
+		// the phase of long periods is quantized, so the era may start before the finalized block. The extrinsic is
+		// checked against the hash of the block the era starts at.
+		if birth := mortal.Birth(uint64(header.Number)); birth != uint64(header.Number) {
+			h, err := rpc.Chain.GetBlockHash(birth)
+			if err != nil {
+				return types.SignatureOptions{}, err
+			}
+			blockHash = &h
+		}
+	case blockHash == nil:
+		// immortal extrinsics are checked against the genesis hash
+		blockHash = genesisHash
+	}
+
+	return types.SignatureOptions{
+		Era:                *era,
+		Nonce:              *nonce,
+		Tip:                b.tip,
+		SpecVersion:        rv.SpecVersion,
+		GenesisHash:        *genesisHash,
+		BlockHash:          *blockHash,
+		TransactionVersion: rv.TransactionVersion,
+		AssetID:            b.assetID,
+		MetadataHash:       b.metadataHash,
+	}, nil
+}
+
+// ss58Prefix returns the SS58 prefix of the chain, the System.SS58Prefix constant of the metadata, which is a u8 in
+// older runtimes. Chains without the constant use the generic Substrate prefix.
+func (b *TxBuilder) ss58Prefix() (uint16, error) {
+	meta, err := b.metadata()
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := meta.FindConstantValue("System", "SS58Prefix")
+	if err != nil {
+		return ss58.SubstratePrefix, nil
+	}
+	switch len(value) {
+	case 1:
+		return uint16(value[0]), nil
+	case 2:
+		return uint16(value[0]) | uint16(value[1])<<8, nil
+	default:
+		return 0, fmt.Errorf("invalid SS58 prefix %#x", value)
+	}
+}
+
+// Build returns the signed extrinsic
+func (b *TxBuilder) Build() (types.Extrinsic, error) {
+	ext, _, err := b.build()
+	return ext, err
+}
+
+// Submit builds the signed extrinsic and submits it for block inclusion
+func (b *TxBuilder) Submit() (*Tx, error) {
+	ext, o, err := b.build()
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := b.api.RPC.Author.SubmitExtrinsic(ext)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tx{Extrinsic: ext, Options: o, Hash: hash}, nil
+}
+
+// SubmitAndWatch builds the signed extrinsic and submits it for block inclusion, subscribing to its status
+func (b *TxBuilder) SubmitAndWatch() (*Tx, error) {
+	ext, o, err := b.build()
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := types.GetHash(ext)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := b.api.RPC.Author.SubmitAndWatchExtrinsic(ext)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tx{Extrinsic: ext, Options: o, Hash: hash, Status: sub}, nil
+}
+
+func (b *TxBuilder) build() (types.Extrinsic, types.SignatureOptions, error) {
+	o, err := b.SignatureOptions()
+	if err != nil {
+		return types.Extrinsic{}, types.SignatureOptions{}, err
+	}
+
+	meta, err := b.metadata()
+	if err != nil {
+		return types.Extrinsic{}, types.SignatureOptions{}, err
+	}
+
+	ext := types.NewExtrinsic(b.call)
+	if meta.Version >= 14 {
+		err = ext.SignWithMetadataAndSigner(meta, b.signer, o)
+	} else {
+		err = ext.SignWithSigner(b.signer, o)
+	}
+	if err != nil {
+		return types.Extrinsic{}, types.SignatureOptions{}, err
+	}
+
+	return ext, o, nil
+}
+
+func (b *TxBuilder) metadata() (*types.Metadata, error) {
+	if b.meta != nil {
+		return b.meta, nil
+	}
+	if b.api.RPC.MetadataCache != nil {
+		return b.api.RPC.MetadataCache.MetadataLatest()
+	}
+	return b.api.RPC.State.GetMetadataLatest()
+}
@@ -0,0 +1,207 @@
+// Go Substrate RPC Client (GSRPC) provides APIs and types around Polkadot and any Substrate-based chain RPC calls
+//
+// Copyright 2019 Centrifuge GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gsrpc_test
+
+import (
+	"fmt"
+	"testing"
+
+	gsrpc "github.com/Phala-Network/go-substrate-rpc-client/v3"
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/rpcmocksrv"
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/signature"
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
+)
+
+// txMockSrv implements the RPC methods used by the TxBuilder
+type txMockSrv struct {
+	genesisHash    types.Hash
+	finalizedHash  types.Hash
+	finalizedBlock types.BlockNumber
+	blockHashes    map[uint64]types.Hash
+	runtimeVersion types.RuntimeVersion
+	nextIndex      types.U32
+	submitted      []string
+}
+
+func (s *txMockSrv) GetBlockHash(blockNumber *uint64) (string, error) {
+	if blockNumber == nil {
+		return "", fmt.Errorf("unexpected block number")
+	}
+	if *blockNumber == 0 {
+		return s.genesisHash.Hex(), nil
+	}
+	if h, ok := s.blockHashes[*blockNumber]; ok {
+		return h.Hex(), nil
+	}
+	return "", fmt.Errorf("unexpected block number %v", *blockNumber)
+}
+
+func (s *txMockSrv) GetFinalizedHead() string {
+	return s.finalizedHash.Hex()
+}
+
+func (s *txMockSrv) GetHeader(hash *string) (types.Header, error) {
+	if hash == nil || *hash != s.finalizedHash.Hex() {
+		return types.Header{}, fmt.Errorf("unexpected block hash")
+	}
+	return types.Header{Number: s.finalizedBlock}, nil
+}
+
+func (s *txMockSrv) GetMetadata(hash *string) string {
+	return types.MetadataV14Data
+}
+
+func (s *txMockSrv) GetRuntimeVersion(hash *string) types.RuntimeVersion {
+	return s.runtimeVersion
+}
+
+func (s *txMockSrv) AccountNextIndex(address string) (types.U32, error) {
+	// the SS58Prefix constant of the example metadata is 0, the Polkadot prefix
+	if address != "15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6Sp5" {
+		return 0, fmt.Errorf("unexpected address %v", address)
+	}
+	return s.nextIndex, nil
+}
+
+func (s *txMockSrv) SubmitExtrinsic(xt string) (string, error) {
+	s.submitted = append(s.submitted, xt)
+	bz, err := types.HexDecodeString(xt)
+	if err != nil {
+		return "", err
+	}
+	return types.Hash(blake2b.Sum256(bz)).Hex(), nil
+}
+
+func newTxMockAPI(t *testing.T) (*gsrpc.SubstrateAPI, *txMockSrv, types.Call) {
+	mock := &txMockSrv{
+		genesisHash:    types.Hash{1, 2, 3},
+		finalizedHash:  types.Hash{4, 5, 6},
+		finalizedBlock: 1000,
+		runtimeVersion: types.RuntimeVersion{SpecVersion: 9110, TransactionVersion: 8},
+		nextIndex:      7,
+	}
+	s := rpcmocksrv.New()
+	for _, service := range []string{"chain", "state", "system", "author"} {
+		assert.NoError(t, s.RegisterName(service, mock))
+	}
+
+	api, err := gsrpc.NewSubstrateAPI(s.URL)
+	assert.NoError(t, err)
+
+	meta, err := api.RPC.State.GetMetadataLatest()
+	assert.NoError(t, err)
+	bob, err := types.NewMultiAddressFromHexAccountID("0x8eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f26a48")
+	assert.NoError(t, err)
+	call, err := types.NewCall(meta, "Balances.transfer", bob, types.NewUCompactFromUInt(12345))
+	assert.NoError(t, err)
+
+	return api, mock, call
+}
+
+var alice = types.NewKeyringPairSigner(signature.TestKeyringPairAlice)
+
+func TestTxBuilder_Submit(t *testing.T) {
+	api, mock, call := newTxMockAPI(t)
+
+	tx, err := api.NewTx(call, alice).Submit()
+	assert.NoError(t, err)
+
+	// the mortal era of 64 blocks starts at the finalized block 1000, which has the phase 1000 % 64 = 40
+	assert.Equal(t, types.SignatureOptions{
+		Era:                types.ExtrinsicEra{IsMortalEra: true, AsMortalEra: types.MortalEra{First: 0x85, Second: 0x02}},
+		Nonce:              types.NewUCompactFromUInt(7),
+		Tip:                types.NewUCompactFromUInt(0),
+		SpecVersion:        9110,
+		GenesisHash:        mock.genesisHash,
+		BlockHash:          mock.finalizedHash,
+		TransactionVersion: 8,
+	}, tx.Options)
+	assert.Nil(t, tx.Status)
+
+	assert.Len(t, mock.submitted, 1)
+	var submitted types.Extrinsic
+	assert.NoError(t, types.DecodeFromHexString(mock.submitted[0], &submitted))
+	assert.True(t, submitted.IsSigned())
+	assert.Equal(t, tx.Extrinsic.Signature.Signature, submitted.Signature.Signature)
+	assert.Equal(t, types.NewUCompactFromUInt(7), submitted.Signature.Nonce)
+	assert.Equal(t, types.NewMultiAddressFromAccountID(signature.TestKeyringPairAlice.PublicKey),
+		submitted.Signature.Signer)
+
+	hash, err := types.GetHash(tx.Extrinsic)
+	assert.NoError(t, err)
+	assert.Equal(t, hash, tx.Hash)
+}
+
+func TestTxBuilder_Overrides(t *testing.T) {
+	api, mock, call := newTxMockAPI(t)
+
+	genesisHash := types.Hash{9, 9, 9}
+	o, err := api.NewTx(call, alice).
+		WithGenesisHash(genesisHash).
+		WithRuntimeVersion(types.RuntimeVersion{SpecVersion: 1, TransactionVersion: 2}).
+		WithNonce(42).
+		WithTip(types.NewUCompactFromUInt(5)).
+		WithImmortalEra().
+		SignatureOptions()
+	assert.NoError(t, err)
+	assert.Equal(t, types.SignatureOptions{
+		Era:                types.ExtrinsicEra{IsImmortalEra: true},
+		Nonce:              types.NewUCompactFromUInt(42),
+		Tip:                types.NewUCompactFromUInt(5),
+		SpecVersion:        1,
+		GenesisHash:        genesisHash,
+		BlockHash:          genesisHash,
+		TransactionVersion: 2,
+	}, o)
+
+	metadataHash := types.NewH256([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16,
+		17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32})
+	o, err = api.NewTx(call, alice).
+		WithAssetID(types.NewU32(1984)).
+		WithMetadataHash(metadataHash).
+		SignatureOptions()
+	assert.NoError(t, err)
+	assert.Equal(t, types.NewU32(1984), o.AssetID)
+	assert.Equal(t, types.NewOptionH256(metadataHash), o.MetadataHash)
+
+	o, err = api.NewTx(call, alice).WithMortalPeriod(100).SignatureOptions()
+	assert.NoError(t, err)
+	// the period is rounded up to 128 blocks, 1000 % 128 = 104
+	assert.Equal(t, types.MortalEra{First: 0x86, Second: 0x06}, o.Era.AsMortalEra)
+	assert.Equal(t, mock.finalizedHash, o.BlockHash)
+
+	// the phase of 8192 blocks is quantized by 2 blocks, the era starts at block 1000 before the finalized block 1001
+	mock.finalizedBlock = 1001
+	mock.blockHashes = map[uint64]types.Hash{1000: {7, 8, 9}}
+	o, err = api.NewTx(call, alice).WithMortalPeriod(8192).SignatureOptions()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1000), o.Era.Birth(1001))
+	assert.Equal(t, types.Hash{7, 8, 9}, o.BlockHash)
+	mock.finalizedBlock = 1000
+
+	ext, err := api.NewTx(call, alice).Build()
+	assert.NoError(t, err)
+	assert.True(t, ext.IsSigned())
+	assert.Len(t, mock.submitted, 0)
+
+	bob, err := signature.KeyPairFromSecret(signature.Ed25519, "//Bob")
+	assert.NoError(t, err)
+	_, err = api.NewTx(call, types.NewSigner(bob)).Submit()
+	assert.Error(t, err)
+}
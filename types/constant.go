@@ -0,0 +1,98 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/scale"
+)
+
+// FindConstant returns the metadata of the constant name of pallet
+func (m *MetadataV14) FindConstant(pallet, name string) (ConstantMetadataV14, error) {
+	for _, p := range m.Pallets {
+		if string(p.Name) == pallet {
+			return findConstant(p.Constants, pallet, name)
+		}
+	}
+	return ConstantMetadataV14{}, fmt.Errorf("pallet %v not found in metadata", pallet)
+}
+
+// DecodeConstant decodes the value of the constant name of pallet into target, which must be a pointer. It returns
+// an error without decoding if values of the Go type of target are not encoded like values of the type of the
+// constant.
+func (m *MetadataV14) DecodeConstant(pallet, name string, target interface{}) error {
+	c, err := m.FindConstant(pallet, name)
+	if err != nil {
+		return err
+	}
+	return decodeConstant(m.lookup(), c, pallet, target)
+}
+
+// DecodeDynamicConstant decodes the value of the constant name of pallet with the type of the constant
+func (m *MetadataV14) DecodeDynamicConstant(pallet, name string) (DynamicValue, error) {
+	c, err := m.FindConstant(pallet, name)
+	if err != nil {
+		return DynamicValue{}, err
+	}
+	return decodeDynamicValueFromBytes(m.lookup(), c.Value, c.Type)
+}
+
+// FindConstant returns the metadata of the constant name of pallet
+func (m *MetadataV15) FindConstant(pallet, name string) (ConstantMetadataV14, error) {
+	for _, p := range m.Pallets {
+		if string(p.Name) == pallet {
+			return findConstant(p.Constants, pallet, name)
+		}
+	}
+	return ConstantMetadataV14{}, fmt.Errorf("pallet %v not found in metadata", pallet)
+}
+
+// DecodeConstant decodes the value of the constant name of pallet into target, see MetadataV14.DecodeConstant
+func (m *MetadataV15) DecodeConstant(pallet, name string, target interface{}) error {
+	c, err := m.FindConstant(pallet, name)
+	if err != nil {
+		return err
+	}
+	return decodeConstant(m.lookup(), c, pallet, target)
+}
+
+// DecodeDynamicConstant decodes the value of the constant name of pallet with the type of the constant
+func (m *MetadataV15) DecodeDynamicConstant(pallet, name string) (DynamicValue, error) {
+	c, err := m.FindConstant(pallet, name)
+	if err != nil {
+		return DynamicValue{}, err
+	}
+	return decodeDynamicValueFromBytes(m.lookup(), c.Value, c.Type)
+}
+
+func findConstant(constants []ConstantMetadataV14, pallet, name string) (ConstantMetadataV14, error) {
+	for _, c := range constants {
+		if string(c.Name) == name {
+			return c, nil
+		}
+	}
+	return ConstantMetadataV14{}, fmt.Errorf("constant %v not found within pallet %v", name, pallet)
+}
+
+func decodeConstant(lookup map[int64]*Si1Type, c ConstantMetadataV14, pallet string, target interface{}) error {
+	t := reflect.TypeOf(target)
+	if t == nil || t.Kind() != reflect.Ptr || reflect.ValueOf(target).IsNil() {
+		return fmt.Errorf("target of constant %v.%v must be a non-nil pointer, got %T", pallet, c.Name, target)
+	}
+
+	err := checkDynamicType(lookup, c.Type, t)
+	if err != nil {
+		return fmt.Errorf("unable to decode constant %v.%v: %v", pallet, c.Name, err)
+	}
+
+	reader := bytes.NewReader(c.Value)
+	err = scale.NewDecoder(reader).Decode(target)
+	if err != nil {
+		return fmt.Errorf("unable to decode constant %v.%v: %v", pallet, c.Name, err)
+	}
+	if reader.Len() > 0 {
+		return fmt.Errorf("decoded constant %v.%v but %v bytes remain", pallet, c.Name, reader.Len())
+	}
+	return nil
+}
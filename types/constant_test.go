@@ -0,0 +1,69 @@
+package types_test
+
+import (
+	"math/big"
+	"testing"
+
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadata_DecodeConstant(t *testing.T) {
+	meta := DecodedMetadataV14Example()
+
+	var existentialDeposit U128
+	err := meta.DecodeConstant("Balances", "ExistentialDeposit", &existentialDeposit)
+	assert.NoError(t, err)
+	assert.Equal(t, NewU128(*big.NewInt(10000000000)), existentialDeposit)
+
+	var dbWeight struct {
+		Read  U64
+		Write U64
+	}
+	err = meta.DecodeConstant("System", "DbWeight", &dbWeight)
+	assert.NoError(t, err)
+	assert.Equal(t, U64(25000000), dbWeight.Read)
+	assert.Equal(t, U64(100000000), dbWeight.Write)
+
+	var blockHashCount U32
+	err = metadataV15FromV14(t).DecodeConstant("System", "BlockHashCount", &blockHashCount)
+	assert.NoError(t, err)
+	assert.Equal(t, U32(2400), blockHashCount)
+}
+
+func TestMetadata_DecodeConstant_Fails(t *testing.T) {
+	meta := DecodedMetadataV14Example()
+
+	var u32 U32
+	err := meta.DecodeConstant("Balances", "ExistentialDeposit", &u32)
+	assert.EqualError(t, err, "unable to decode constant Balances.ExistentialDeposit: expected u128, got types.U32")
+	assert.Equal(t, U32(0), u32)
+
+	err = meta.DecodeConstant("Balances", "ExistentialDeposit", u32)
+	assert.EqualError(t, err, "target of constant Balances.ExistentialDeposit must be a non-nil pointer, got types.U32")
+	err = meta.DecodeConstant("Balances", "Unknown", &u32)
+	assert.EqualError(t, err, "constant Unknown not found within pallet Balances")
+	err = meta.DecodeConstant("Unknown", "ExistentialDeposit", &u32)
+	assert.EqualError(t, err, "pallet Unknown not found in metadata")
+	err = ExamplaryMetadataV13.DecodeConstant("Balances", "ExistentialDeposit", &u32)
+	assert.EqualError(t, err, "typed constants are not supported for metadata version 13")
+}
+
+func TestMetadata_DecodeDynamicConstant(t *testing.T) {
+	meta := DecodedMetadataV14Example()
+
+	v, err := meta.DecodeDynamicConstant("System", "BlockLength")
+	assert.NoError(t, err)
+	max, ok := v.Field("max")
+	assert.True(t, ok)
+	normal, ok := max.Field("normal")
+	assert.True(t, ok)
+	assert.Equal(t, U32(3932160), normal.AsPrimitive)
+
+	v, err = metadataV15FromV14(t).DecodeDynamicConstant("Balances", "MaxLocks")
+	assert.NoError(t, err)
+	assert.Equal(t, U32(50), v.AsPrimitive)
+
+	_, err = ExamplaryMetadataV13.DecodeDynamicConstant("System", "BlockLength")
+	assert.EqualError(t, err, "typed constants are not supported for metadata version 13")
+}
@@ -0,0 +1,284 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/scale"
+)
+
+// DynamicCall is a Call whose arguments have been decoded with the call variant of the pallet's call type in the
+// portable registry
+type DynamicCall struct {
+	CallIndex CallIndex
+	Pallet    Text
+	Name      Text
+	Args      []DynamicField
+	// NestedCalls are the calls passed as arguments to this call, e.g. the calls of Utility.batch or the call of
+	// Sudo.sudo. Calls nested deeper are found in the NestedCalls of these calls.
+	NestedCalls []DynamicCall
+}
+
+// Arg returns the value of the argument with the given name
+func (c DynamicCall) Arg(name string) (DynamicValue, bool) {
+	for _, a := range c.Args {
+		if string(a.Name) == name {
+			return a.Value, true
+		}
+	}
+	return DynamicValue{}, false
+}
+
+// DecodeCall decodes the arguments of the Call c by resolving its call index to a call variant of a pallet
+func (m *MetadataV14) DecodeCall(c Call) (DynamicCall, error) {
+	outerCallType, ok := outerCallTypeOfExtrinsic(m.lookup(), m.Extrinsic.Type)
+	return decodeCall(m.lookup(), m.Pallets, outerCallType, ok, c)
+}
+
+// DecodeCall decodes the arguments of the Call c, see MetadataV14.DecodeCall
+func (m *MetadataV15) DecodeCall(c Call) (DynamicCall, error) {
+	return decodeCall(m.lookup(), m.palletsV14(), m.Extrinsic.CallType, true, c)
+}
+
+// decodeCall decodes the Call c with the call types of pallets. Values of the runtime's call enum outerCallType are
+// decoded as nested calls, if the type is known.
+func decodeCall(lookup map[int64]*Si1Type, pallets []PalletMetadataV14, outerCallType Si1LookupTypeID,
+	hasOuterCallType bool, c Call) (DynamicCall, error) {
+	mod, variant, err := findCallVariant(lookup, pallets, c.CallIndex)
+	if err != nil {
+		return DynamicCall{}, err
+	}
+
+	reader := bytes.NewReader(c.Args)
+	args, err := decodeDynamicFields(*scale.NewDecoder(reader), lookup, variant.Fields)
+	if err != nil {
+		return DynamicCall{}, fmt.Errorf("unable to decode call %v.%v: %v", mod.Name, variant.Name, err)
+	}
+	if reader.Len() > 0 {
+		return DynamicCall{}, fmt.Errorf("decoded call %v.%v but %v bytes remain", mod.Name, variant.Name,
+			reader.Len())
+	}
+
+	call := DynamicCall{CallIndex: c.CallIndex, Pallet: mod.Name, Name: variant.Name, Args: args}
+	if hasOuterCallType {
+		for _, a := range args {
+			call.NestedCalls = appendNestedCalls(call.NestedCalls, a.Value, outerCallType)
+		}
+	}
+	return call, nil
+}
+
+// findCallVariant returns the pallet with the module index of callIndex, together with the variant of the pallet's
+// call type that describes the call
+func findCallVariant(lookup map[int64]*Si1Type, pallets []PalletMetadataV14, callIndex CallIndex) (
+	*PalletMetadataV14, *Si1Variant, error) {
+	for i, mod := range pallets {
+		if !mod.HasCalls {
+			continue
+		}
+		if uint8(mod.Index) != callIndex.SectionIndex {
+			continue
+		}
+		callType := mod.Calls.Type.Int64()
+
+		if typ, ok := lookup[callType]; ok {
+			for j, vars := range typ.Def.Variant.Variants {
+				if uint8(vars.Index) == callIndex.MethodIndex {
+					return &pallets[i], &typ.Def.Variant.Variants[j], nil
+				}
+			}
+		}
+		return nil, nil, fmt.Errorf("call index %v not found within module %v", callIndex.MethodIndex, mod.Name)
+	}
+	return nil, nil, fmt.Errorf("module index %v out of range", callIndex.SectionIndex)
+}
+
+// outerCallTypeOfExtrinsic returns the id of the runtime's call enum, which wraps the call types of all pallets. It
+// is the Call type parameter of the extrinsic type of V14 metadata, V15 metadata lists it in its extrinsic metadata.
+func outerCallTypeOfExtrinsic(lookup map[int64]*Si1Type, extrinsicType Si1LookupTypeID) (Si1LookupTypeID, bool) {
+	typ, ok := lookup[extrinsicType.Int64()]
+	if !ok {
+		return Si1LookupTypeID{}, false
+	}
+	for _, param := range typ.Params {
+		if param.Name == "Call" && param.HasType {
+			return param.Type, true
+		}
+	}
+	return Si1LookupTypeID{}, false
+}
+
+// appendNestedCalls appends all values of the outer call type found within v to calls
+func appendNestedCalls(calls []DynamicCall, v DynamicValue, outerCallType Si1LookupTypeID) []DynamicCall {
+	if v.Type.Int64() == outerCallType.Int64() {
+		call, ok := dynamicCallFromValue(v, outerCallType)
+		if ok {
+			return append(calls, call)
+		}
+	}
+
+	var children []DynamicValue
+	switch {
+	case v.IsComposite:
+		for _, f := range v.AsComposite {
+			children = append(children, f.Value)
+		}
+	case v.IsVariant:
+		for _, f := range v.AsVariant.Fields {
+			children = append(children, f.Value)
+		}
+	case v.IsSequence:
+		children = v.AsSequence
+	case v.IsArray:
+		children = v.AsArray
+	case v.IsTuple:
+		children = v.AsTuple
+	}
+	for _, child := range children {
+		calls = appendNestedCalls(calls, child, outerCallType)
+	}
+	return calls
+}
+
+// dynamicCallFromValue converts a value of the outer call type, a variant per pallet wrapping the variant of the
+// pallet's call type, into a DynamicCall
+func dynamicCallFromValue(v DynamicValue, outerCallType Si1LookupTypeID) (DynamicCall, bool) {
+	if !v.IsVariant || len(v.AsVariant.Fields) != 1 || !v.AsVariant.Fields[0].Value.IsVariant {
+		return DynamicCall{}, false
+	}
+	inner := v.AsVariant.Fields[0].Value.AsVariant
+
+	call := DynamicCall{
+		CallIndex: CallIndex{SectionIndex: uint8(v.AsVariant.Index), MethodIndex: uint8(inner.Index)},
+		Pallet:    v.AsVariant.Name,
+		Name:      inner.Name,
+		Args:      inner.Fields,
+	}
+	for _, a := range inner.Fields {
+		call.NestedCalls = appendNestedCalls(call.NestedCalls, a.Value, outerCallType)
+	}
+	return call, true
+}
+
+// CallArg is a named argument of a call, used to construct calls that are checked against the call types of the
+// metadata
+type CallArg struct {
+	Name string
+	// Value is either a DynamicValue of the argument's type or a Go value that encodes like the argument's type
+	Value interface{}
+}
+
+// NewCallArg creates a new CallArg
+func NewCallArg(name string, value interface{}) CallArg {
+	return CallArg{Name: name, Value: value}
+}
+
+// NewCheckedCall creates a call like NewCall, but takes named arguments that are checked against the fields of the
+// call variant before being encoded. Missing, unknown, duplicate and mistyped arguments are rejected.
+func (m *MetadataV14) NewCheckedCall(call string, args ...CallArg) (Call, error) {
+	return newCheckedCall(m.lookup(), m.Pallets, call, args)
+}
+
+// NewCheckedCall creates a call from named arguments, see MetadataV14.NewCheckedCall
+func (m *MetadataV15) NewCheckedCall(call string, args ...CallArg) (Call, error) {
+	return newCheckedCall(m.lookup(), m.palletsV14(), call, args)
+}
+
+func newCheckedCall(lookup map[int64]*Si1Type, pallets []PalletMetadataV14, call string, args []CallArg) (Call,
+	error) {
+	s := strings.Split(call, ".")
+	if len(s) != 2 {
+		return Call{}, fmt.Errorf("call %v is not of the form Pallet.call", call)
+	}
+	mod, variant, err := findCallVariantByName(lookup, pallets, s[0], s[1])
+	if err != nil {
+		return Call{}, err
+	}
+
+	values := make(map[string]interface{}, len(args))
+	for _, a := range args {
+		if _, ok := values[a.Name]; ok {
+			return Call{}, fmt.Errorf("duplicate argument %v for call %v", a.Name, call)
+		}
+		values[a.Name] = a.Value
+	}
+
+	var encoded []byte
+	for _, f := range variant.Fields {
+		value, ok := values[string(f.Name)]
+		if !ok {
+			return Call{}, fmt.Errorf("missing argument %v of type %v for call %v", f.Name, f.TypeName, call)
+		}
+		delete(values, string(f.Name))
+
+		bz, err := encodeDynamicArg(lookup, f.Type, value)
+		if err != nil {
+			return Call{}, fmt.Errorf("invalid argument %v for call %v: %v", f.Name, call, err)
+		}
+		encoded = append(encoded, bz...)
+	}
+
+	for _, a := range args {
+		if _, ok := values[a.Name]; ok {
+			return Call{}, fmt.Errorf("unknown argument %v for call %v", a.Name, call)
+		}
+	}
+
+	return Call{CallIndex: CallIndex{uint8(mod.Index), uint8(variant.Index)}, Args: encoded}, nil
+}
+
+// encodeDynamicArg checks value against the registry type typeID and encodes it. The encoded bytes are decoded with
+// typeID again to catch mismatches of types with custom encodings.
+func encodeDynamicArg(lookup map[int64]*Si1Type, typeID Si1LookupTypeID, value interface{}) ([]byte, error) {
+	if value == nil {
+		return nil, fmt.Errorf("expected %v, got nil", dynamicTypeName(lookup, typeID))
+	}
+
+	if v, ok := value.(DynamicValue); ok {
+		if v.Type.Int64() != typeID.Int64() {
+			return nil, fmt.Errorf("expected %v, got a DynamicValue of %v", dynamicTypeName(lookup, typeID),
+				dynamicTypeName(lookup, v.Type))
+		}
+	} else {
+		err := checkDynamicType(lookup, typeID, reflect.TypeOf(value))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	bz, err := EncodeToBytes(value)
+	if err != nil {
+		return nil, err
+	}
+	_, err = decodeDynamicValueFromBytes(lookup, bz, typeID)
+	if err != nil {
+		return nil, fmt.Errorf("value does not encode as %v: %v", dynamicTypeName(lookup, typeID), err)
+	}
+	return bz, nil
+}
+
+// findCallVariantByName returns the pallet with the given name, together with the variant of the pallet's call type
+// that describes the call with the given name
+func findCallVariantByName(lookup map[int64]*Si1Type, pallets []PalletMetadataV14, pallet, call string) (
+	*PalletMetadataV14, *Si1Variant, error) {
+	for i, mod := range pallets {
+		if string(mod.Name) != pallet {
+			continue
+		}
+		if !mod.HasCalls {
+			return nil, nil, fmt.Errorf("module %v has no calls", pallet)
+		}
+
+		if typ, ok := lookup[mod.Calls.Type.Int64()]; ok {
+			for j, vars := range typ.Def.Variant.Variants {
+				if string(vars.Name) == call {
+					return &pallets[i], &typ.Def.Variant.Variants[j], nil
+				}
+			}
+		}
+		return nil, nil, fmt.Errorf("call %v not found within module %v", call, pallet)
+	}
+	return nil, nil, fmt.Errorf("module %v not found in metadata", pallet)
+}
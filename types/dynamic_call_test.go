@@ -0,0 +1,261 @@
+package types_test
+
+import (
+	"math/big"
+	"testing"
+
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadata_DecodeCall(t *testing.T) {
+	var meta Metadata
+	err := DecodeFromHexString(MetadataV14Data, &meta)
+	assert.NoError(t, err)
+
+	bob, err := NewMultiAddressFromHexAccountID("0x8eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f26a48")
+	assert.NoError(t, err)
+	c, err := NewCall(&meta, "Balances.transfer", bob, NewUCompactFromUInt(12345))
+	assert.NoError(t, err)
+
+	// decode the call of an extrinsic as an indexer would
+	ext := NewExtrinsic(c)
+	enc, err := EncodeToHexString(ext)
+	assert.NoError(t, err)
+	var decoded Extrinsic
+	err = DecodeFromHexString(enc, &decoded)
+	assert.NoError(t, err)
+
+	call, err := meta.DecodeCall(decoded.Method)
+	assert.NoError(t, err)
+	assert.Equal(t, c.CallIndex, call.CallIndex)
+	assert.Equal(t, NewText("Balances"), call.Pallet)
+	assert.Equal(t, NewText("transfer"), call.Name)
+	assert.Len(t, call.Args, 2)
+	assert.Nil(t, call.NestedCalls)
+
+	dest, ok := call.Arg("dest")
+	assert.True(t, ok)
+	assert.Equal(t, NewText("Id"), dest.AsVariant.Name)
+	account, ok := dest.AsVariant.Fields[0].Value.Bytes()
+	assert.True(t, ok)
+	assert.Equal(t, bob.AsID[:], account)
+
+	value, ok := call.Arg("value")
+	assert.True(t, ok)
+	assert.Equal(t, NewUCompactFromUInt(12345), value.AsCompact)
+	assert.Equal(t, NewText("T::Balance"), call.Args[1].TypeName)
+
+	_, ok = call.Arg("unknown")
+	assert.False(t, ok)
+}
+
+func TestMetadata_DecodeCall_Nested(t *testing.T) {
+	var meta Metadata
+	err := DecodeFromHexString(MetadataV14Data, &meta)
+	assert.NoError(t, err)
+
+	remark, err := NewCall(&meta, "System.remark", NewBytes([]byte{0x12, 0x34}))
+	assert.NoError(t, err)
+	transfer, err := NewCall(&meta, "Balances.transfer", NewMultiAddressFromAccountID([]byte{1, 2, 3}),
+		NewUCompactFromUInt(1))
+	assert.NoError(t, err)
+	batch, err := NewCall(&meta, "Utility.batch", []Call{remark, transfer})
+	assert.NoError(t, err)
+	derivative, err := NewCall(&meta, "Utility.as_derivative", U16(7), batch)
+	assert.NoError(t, err)
+
+	call, err := meta.DecodeCall(derivative)
+	assert.NoError(t, err)
+	assert.Equal(t, NewText("Utility"), call.Pallet)
+	assert.Equal(t, NewText("as_derivative"), call.Name)
+	index, ok := call.Arg("index")
+	assert.True(t, ok)
+	assert.Equal(t, U16(7), index.AsPrimitive)
+
+	assert.Len(t, call.NestedCalls, 1)
+	nestedBatch := call.NestedCalls[0]
+	assert.Equal(t, batch.CallIndex, nestedBatch.CallIndex)
+	assert.Equal(t, NewText("batch"), nestedBatch.Name)
+
+	assert.Len(t, nestedBatch.NestedCalls, 2)
+	assert.Equal(t, remark.CallIndex, nestedBatch.NestedCalls[0].CallIndex)
+	assert.Equal(t, NewText("System"), nestedBatch.NestedCalls[0].Pallet)
+	assert.Equal(t, NewText("remark"), nestedBatch.NestedCalls[0].Name)
+	r, ok := nestedBatch.NestedCalls[0].Arg("remark")
+	assert.True(t, ok)
+	b, ok := r.Bytes()
+	assert.True(t, ok)
+	assert.Equal(t, []byte{0x12, 0x34}, b)
+	assert.Equal(t, NewText("Balances"), nestedBatch.NestedCalls[1].Pallet)
+	assert.Equal(t, NewText("transfer"), nestedBatch.NestedCalls[1].Name)
+
+	// the decoded arguments encode back to the original call
+	calls, ok := call.Arg("call")
+	assert.True(t, ok)
+	enc, err := EncodeToBytes(calls)
+	assert.NoError(t, err)
+	expected, err := EncodeToBytes(batch)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, enc)
+
+	// the same runtime in V15 lists the call enum in its extrinsic metadata
+	v15Call, err := metadataV15FromV14(t).DecodeCall(derivative)
+	assert.NoError(t, err)
+	assert.Equal(t, call, v15Call)
+}
+
+func TestMetadata_DecodeCall_Fails(t *testing.T) {
+	var meta Metadata
+	err := DecodeFromHexString(MetadataV14Data, &meta)
+	assert.NoError(t, err)
+
+	_, err = meta.DecodeCall(Call{CallIndex: CallIndex{SectionIndex: 200}})
+	assert.EqualError(t, err, "module index 200 out of range")
+
+	_, err = meta.DecodeCall(Call{CallIndex: CallIndex{SectionIndex: 0, MethodIndex: 200}})
+	assert.EqualError(t, err, "call index 200 not found within module System")
+
+	// System.remark with a trailing byte
+	_, err = meta.DecodeCall(Call{CallIndex: CallIndex{SectionIndex: 0, MethodIndex: 1}, Args: []byte{0x04, 1, 2}})
+	assert.EqualError(t, err, "decoded call System.remark but 1 bytes remain")
+
+	// System.remark without enough bytes
+	_, err = meta.DecodeCall(Call{CallIndex: CallIndex{SectionIndex: 0, MethodIndex: 1}, Args: []byte{0x08, 1}})
+	assert.Error(t, err)
+
+	_, err = metadataV15FromV14(t).DecodeCall(Call{CallIndex: CallIndex{SectionIndex: 0, MethodIndex: 200}})
+	assert.EqualError(t, err, "call index 200 not found within module System")
+
+	_, err = ExamplaryMetadataV13.DecodeCall(Call{})
+	assert.EqualError(t, err, "dynamic decoding of calls is not supported for metadata version 13")
+}
+
+func TestMetadata_NewCheckedCall(t *testing.T) {
+	var meta Metadata
+	err := DecodeFromHexString(MetadataV14Data, &meta)
+	assert.NoError(t, err)
+
+	bob, err := NewMultiAddressFromHexAccountID("0x8eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f26a48")
+	assert.NoError(t, err)
+
+	// arguments may be given in any order
+	c, err := meta.NewCheckedCall("Balances.transfer",
+		NewCallArg("value", NewUCompactFromUInt(12345)),
+		NewCallArg("dest", bob),
+	)
+	assert.NoError(t, err)
+	expected, err := NewCall(&meta, "Balances.transfer", bob, NewUCompactFromUInt(12345))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, c)
+
+	// strings are accepted for Vec<u8>
+	c, err = meta.NewCheckedCall("System.remark", NewCallArg("remark", "hello"))
+	assert.NoError(t, err)
+	expected, err = NewCall(&meta, "System.remark", NewBytes([]byte("hello")))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, c)
+
+	c, err = meta.NewCheckedCall("System.set_heap_pages", NewCallArg("pages", U64(64)))
+	assert.NoError(t, err)
+	assert.Equal(t, Args{64, 0, 0, 0, 0, 0, 0, 0}, c.Args)
+
+	// nested calls and decoded values
+	batch, err := meta.NewCheckedCall("Utility.batch", NewCallArg("calls", []Call{c, expected}))
+	assert.NoError(t, err)
+	call, err := meta.DecodeCall(batch)
+	assert.NoError(t, err)
+	calls, ok := call.Arg("calls")
+	assert.True(t, ok)
+	c, err = meta.NewCheckedCall("Utility.batch_all", NewCallArg("calls", calls))
+	assert.NoError(t, err)
+	assert.Equal(t, batch.Args, c.Args)
+
+	// the same runtime in V15
+	v15 := metadataV15FromV14(t)
+	c, err = v15.NewCheckedCall("Balances.transfer",
+		NewCallArg("value", NewUCompactFromUInt(12345)),
+		NewCallArg("dest", bob),
+	)
+	assert.NoError(t, err)
+	expected, err = NewCall(&meta, "Balances.transfer", bob, NewUCompactFromUInt(12345))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, c)
+
+	_, err = v15.NewCheckedCall("Balances.transfer", NewCallArg("dest", bob))
+	assert.EqualError(t, err, "missing argument value of type T::Balance for call Balances.transfer")
+}
+
+func TestMetadata_NewCheckedCall_Fails(t *testing.T) {
+	var meta Metadata
+	err := DecodeFromHexString(MetadataV14Data, &meta)
+	assert.NoError(t, err)
+
+	bob := NewMultiAddressFromAccountID([]byte{1, 2, 3})
+
+	for _, test := range []struct {
+		call     string
+		args     []CallArg
+		expected string
+	}{
+		{"Balances", nil, "call Balances is not of the form Pallet.call"},
+		{"Unknown.transfer", nil, "module Unknown not found in metadata"},
+		{"Balances.unknown", nil, "call unknown not found within module Balances"},
+		{
+			"Balances.transfer",
+			[]CallArg{NewCallArg("dest", bob)},
+			"missing argument value of type T::Balance for call Balances.transfer",
+		},
+		{
+			"Balances.transfer",
+			[]CallArg{NewCallArg("dest", bob), NewCallArg("value", NewUCompactFromUInt(1)), NewCallArg("memo", U8(1))},
+			"unknown argument memo for call Balances.transfer",
+		},
+		{
+			"Balances.transfer",
+			[]CallArg{NewCallArg("dest", bob), NewCallArg("dest", bob)},
+			"duplicate argument dest for call Balances.transfer",
+		},
+		{
+			"Balances.transfer",
+			[]CallArg{NewCallArg("dest", bob), NewCallArg("value", NewU128(*big.NewInt(1)))},
+			"invalid argument value for call Balances.transfer: expected Compact<u128>, got types.U128",
+		},
+		{
+			"Balances.transfer",
+			[]CallArg{NewCallArg("dest", bob), NewCallArg("value", nil)},
+			"invalid argument value for call Balances.transfer: expected Compact<u128>, got nil",
+		},
+		{
+			"Utility.as_derivative",
+			[]CallArg{NewCallArg("index", U32(1)), NewCallArg("call", Call{})},
+			"invalid argument index for call Utility.as_derivative: expected u16, got types.U32",
+		},
+		{
+			"System.set_heap_pages",
+			[]CallArg{NewCallArg("pages", DynamicValue{Type: NewSi1LookupTypeIDFromUInt(10)})},
+			"invalid argument pages for call System.set_heap_pages: expected u64, got a DynamicValue of Vec<u8>",
+		},
+		{
+			"Balances.transfer",
+			[]CallArg{NewCallArg("dest", NewU128(*big.NewInt(1))), NewCallArg("value", NewUCompactFromUInt(1))},
+			"invalid argument dest for call Balances.transfer: value does not encode as " +
+				"sp_runtime::multiaddress::MultiAddress<sp_core::crypto::AccountId32, ()>: decoded type 147 but 15 bytes remain",
+		},
+	} {
+		_, err = meta.NewCheckedCall(test.call, test.args...)
+		assert.EqualError(t, err, test.expected)
+	}
+
+	// values with a custom encoding are checked by decoding the encoded value
+	_, err = meta.NewCheckedCall("Utility.as_derivative", NewCallArg("index", U16(1)), NewCallArg("call", bob))
+	assert.Error(t, err)
+
+	// a struct with the fields of Perbill
+	_, err = meta.NewCheckedCall("System.fill_block", NewCallArg("ratio", struct{ A, B U32 }{}))
+	assert.EqualError(t, err, "invalid argument ratio for call System.fill_block: "+
+		"expected sp_arithmetic::per_things::Perbill, got struct { A types.U32; B types.U32 }")
+
+	_, err = ExamplaryMetadataV13.NewCheckedCall("Balances.transfer")
+	assert.EqualError(t, err, "checked calls are not supported for metadata version 13")
+}
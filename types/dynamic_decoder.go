@@ -0,0 +1,328 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/scale"
+)
+
+// DecodeDynamicValue decodes a value of the registry type typeID from the decoder. Type definitions are resolved
+// through lookup, usually MetadataV14.EfficientLookup, so no static Go type is needed to decode the value.
+func DecodeDynamicValue(decoder scale.Decoder, lookup map[int64]*Si1Type, typeID Si1LookupTypeID) (
+	DynamicValue, error) {
+	typ, ok := lookup[typeID.Int64()]
+	if !ok {
+		return DynamicValue{}, fmt.Errorf("type %v not found in the portable registry", typeID.Int64())
+	}
+
+	v := DynamicValue{Type: typeID}
+	def := typ.Def
+	var err error
+
+	switch {
+	case def.IsComposite:
+		v.IsComposite = true
+		v.AsComposite, err = decodeDynamicFields(decoder, lookup, def.Composite.Fields)
+	case def.IsVariant:
+		v.IsVariant = true
+		v.AsVariant, err = decodeDynamicVariant(decoder, lookup, def.Variant)
+	case def.IsSequence:
+		v.IsSequence = true
+		v.HasU8Elements = isU8Type(lookup, def.Sequence.Type)
+		v.AsSequence, err = decodeDynamicSequence(decoder, lookup, def.Sequence.Type)
+	case def.IsArray:
+		v.IsArray = true
+		v.HasU8Elements = isU8Type(lookup, def.Array.Type)
+		v.AsArray, err = decodeDynamicValues(decoder, lookup, def.Array.Type, int(def.Array.Len))
+	case def.IsTuple:
+		v.IsTuple = true
+		v.AsTuple = make([]DynamicValue, len(def.Tuple))
+		for i, id := range def.Tuple {
+			v.AsTuple[i], err = DecodeDynamicValue(decoder, lookup, id)
+			if err != nil {
+				return v, fmt.Errorf("unable to decode tuple element %v: %v", i, err)
+			}
+		}
+	case def.IsPrimitive:
+		v.IsPrimitive = true
+		v.Primitive = def.Primitive.Si0TypeDefPrimitive
+		v.AsPrimitive, err = decodeDynamicPrimitive(decoder, v.Primitive)
+	case def.IsCompact:
+		v.IsCompact = true
+		v.AsCompact, err = decodeDynamicCompact(decoder, lookup, def.Compact.Type)
+	case def.IsBitSequence:
+		v.IsBitSequence = true
+		v.AsBitSequence, err = decodeDynamicBitSequence(decoder, lookup, def.BitSequence)
+	case def.IsHistoricMetaCompat:
+		return v, fmt.Errorf("unable to decode historic type %v of type %v", def.HistoricMetaCompat, typeID.Int64())
+	default:
+		return v, fmt.Errorf("type %v has no valid type definition", typeID.Int64())
+	}
+
+	return v, err
+}
+
+// DecodeDynamicValue decodes bz as a value of the registry type typeID. All bytes of bz must be consumed.
+func (m *MetadataV14) DecodeDynamicValue(bz []byte, typeID Si1LookupTypeID) (DynamicValue, error) {
+	return decodeDynamicValueFromBytes(m.lookup(), bz, typeID)
+}
+
+func decodeDynamicValueFromBytes(lookup map[int64]*Si1Type, bz []byte, typeID Si1LookupTypeID) (DynamicValue,
+	error) {
+	reader := bytes.NewReader(bz)
+	v, err := DecodeDynamicValue(*scale.NewDecoder(reader), lookup, typeID)
+	if err != nil {
+		return v, err
+	}
+	if reader.Len() > 0 {
+		return v, fmt.Errorf("decoded type %v but %v bytes remain", typeID.Int64(), reader.Len())
+	}
+	return v, nil
+}
+
+// lookup returns EfficientLookup, which is built while decoding. Metadata that has been created in code gets a new
+// lookup on each call, as storing it would race with concurrent readers of the metadata.
+func (m *MetadataV14) lookup() map[int64]*Si1Type {
+	if m.EfficientLookup == nil {
+		return m.Lookup.toMap()
+	}
+	return m.EfficientLookup
+}
+
+func decodeDynamicFields(decoder scale.Decoder, lookup map[int64]*Si1Type, fields []Si1Field) (
+	[]DynamicField, error) {
+	decoded := make([]DynamicField, len(fields))
+	for i, f := range fields {
+		value, err := DecodeDynamicValue(decoder, lookup, f.Type)
+		if err != nil {
+			if f.HasName {
+				return nil, fmt.Errorf("unable to decode field %v: %v", f.Name, err)
+			}
+			return nil, fmt.Errorf("unable to decode field %v: %v", i, err)
+		}
+		decoded[i] = DynamicField{Name: f.Name, TypeName: f.TypeName, Value: value}
+	}
+	return decoded, nil
+}
+
+func decodeDynamicVariant(decoder scale.Decoder, lookup map[int64]*Si1Type, def Si1TypeDefVariant) (
+	DynamicVariant, error) {
+	index, err := decoder.ReadOneByte()
+	if err != nil {
+		return DynamicVariant{}, err
+	}
+
+	for _, variant := range def.Variants {
+		if byte(variant.Index) != index {
+			continue
+		}
+		fields, err := decodeDynamicFields(decoder, lookup, variant.Fields)
+		if err != nil {
+			return DynamicVariant{}, fmt.Errorf("unable to decode variant %v: %v", variant.Name, err)
+		}
+		return DynamicVariant{Name: variant.Name, Index: variant.Index, Fields: fields}, nil
+	}
+	return DynamicVariant{}, fmt.Errorf("variant index %v not found", index)
+}
+
+func decodeDynamicSequence(decoder scale.Decoder, lookup map[int64]*Si1Type, typeID Si1LookupTypeID) (
+	[]DynamicValue, error) {
+	n, err := decoder.DecodeUintCompact()
+	if err != nil {
+		return nil, err
+	}
+	if !n.IsUint64() || n.Uint64() > uint64(maxDynamicSequenceLen) {
+		return nil, fmt.Errorf("sequence length %v exceeds the maximum of %v", n, maxDynamicSequenceLen)
+	}
+	return decodeDynamicValues(decoder, lookup, typeID, int(n.Uint64()))
+}
+
+func isU8Type(lookup map[int64]*Si1Type, typeID Si1LookupTypeID) bool {
+	typ, ok := lookup[typeID.Int64()]
+	return ok && typ.Def.IsPrimitive && typ.Def.Primitive.Si0TypeDefPrimitive == IsU8
+}
+
+// maxDynamicSequenceLen guards against allocating huge slices when decoding garbage
+const maxDynamicSequenceLen = 1 << 24
+
+func decodeDynamicValues(decoder scale.Decoder, lookup map[int64]*Si1Type, typeID Si1LookupTypeID, n int) (
+	[]DynamicValue, error) {
+	values := make([]DynamicValue, n)
+	for i := range values {
+		var err error
+		values[i], err = DecodeDynamicValue(decoder, lookup, typeID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode element %v: %v", i, err)
+		}
+	}
+	return values, nil
+}
+
+func decodeDynamicPrimitive(decoder scale.Decoder, primitive Si0TypeDefPrimitive) (interface{}, error) {
+	var err error
+	switch primitive {
+	case IsBool:
+		var v Bool
+		err = decoder.Decode(&v)
+		return v, err
+	case IsChar:
+		var v U32
+		err = decoder.Decode(&v)
+		return rune(v), err
+	case IsStr:
+		var v Text
+		err = decoder.Decode(&v)
+		return v, err
+	case IsU8:
+		var v U8
+		err = decoder.Decode(&v)
+		return v, err
+	case IsU16:
+		var v U16
+		err = decoder.Decode(&v)
+		return v, err
+	case IsU32:
+		var v U32
+		err = decoder.Decode(&v)
+		return v, err
+	case IsU64:
+		var v U64
+		err = decoder.Decode(&v)
+		return v, err
+	case IsU128:
+		var v U128
+		err = decoder.Decode(&v)
+		return v, err
+	case IsU256:
+		var v U256
+		err = decoder.Decode(&v)
+		return v, err
+	case IsI8:
+		var v I8
+		err = decoder.Decode(&v)
+		return v, err
+	case IsI16:
+		var v I16
+		err = decoder.Decode(&v)
+		return v, err
+	case IsI32:
+		var v I32
+		err = decoder.Decode(&v)
+		return v, err
+	case IsI64:
+		var v I64
+		err = decoder.Decode(&v)
+		return v, err
+	case IsI128:
+		var v I128
+		err = decoder.Decode(&v)
+		return v, err
+	case IsI256:
+		var v I256
+		err = decoder.Decode(&v)
+		return v, err
+	default:
+		return nil, fmt.Errorf("unsupported primitive type %v", primitive)
+	}
+}
+
+// decodeDynamicCompact decodes a compact encoded value. Compacts of an empty type, such as Compact<()>, are encoded
+// without any bytes.
+func decodeDynamicCompact(decoder scale.Decoder, lookup map[int64]*Si1Type, typeID Si1LookupTypeID) (
+	UCompact, error) {
+	typ, ok := lookup[typeID.Int64()]
+	if !ok {
+		return UCompact{}, fmt.Errorf("type %v not found in the portable registry", typeID.Int64())
+	}
+	if (typ.Def.IsTuple && len(typ.Def.Tuple) == 0) ||
+		(typ.Def.IsComposite && len(typ.Def.Composite.Fields) == 0) {
+		return NewUCompactFromUInt(0), nil
+	}
+
+	var v UCompact
+	err := decoder.Decode(&v)
+	return v, err
+}
+
+func decodeDynamicBitSequence(decoder scale.Decoder, lookup map[int64]*Si1Type, def Si1TypeDefBitSequence) (
+	BitSequence, error) {
+	storeSize, err := bitStoreSize(lookup, def.BitStoreType)
+	if err != nil {
+		return BitSequence{}, err
+	}
+	isMsb0, err := isMsb0BitOrder(lookup, def.BitOrderType)
+	if err != nil {
+		return BitSequence{}, err
+	}
+
+	n, err := decoder.DecodeUintCompact()
+	if err != nil {
+		return BitSequence{}, err
+	}
+	if !n.IsUint64() || n.Uint64() > uint64(maxDynamicSequenceLen) {
+		return BitSequence{}, fmt.Errorf("bit sequence length %v exceeds the maximum of %v", n,
+			maxDynamicSequenceLen)
+	}
+
+	bits := make([]bool, n.Uint64())
+	storeBits := storeSize * 8
+	buf := make([]byte, storeSize)
+	for start := 0; start < len(bits); start += storeBits {
+		err = decoder.Read(buf)
+		if err != nil {
+			return BitSequence{}, err
+		}
+		var elem uint64
+		for i, b := range buf {
+			elem |= uint64(b) << uint(8*i)
+		}
+		for i := 0; i < storeBits && start+i < len(bits); i++ {
+			if isMsb0 {
+				bits[start+i] = elem&(1<<uint(storeBits-1-i)) != 0
+			} else {
+				bits[start+i] = elem&(1<<uint(i)) != 0
+			}
+		}
+	}
+
+	return BitSequence{Bits: bits, StoreSize: storeSize, IsMsb0: isMsb0}, nil
+}
+
+func bitStoreSize(lookup map[int64]*Si1Type, typeID Si1LookupTypeID) (int, error) {
+	typ, ok := lookup[typeID.Int64()]
+	if !ok {
+		return 0, fmt.Errorf("type %v not found in the portable registry", typeID.Int64())
+	}
+	if typ.Def.IsPrimitive {
+		switch typ.Def.Primitive.Si0TypeDefPrimitive {
+		case IsU8:
+			return 1, nil
+		case IsU16:
+			return 2, nil
+		case IsU32:
+			return 4, nil
+		case IsU64:
+			return 8, nil
+		}
+	}
+	return 0, fmt.Errorf("type %v is not a valid bit store type", typeID.Int64())
+}
+
+func isMsb0BitOrder(lookup map[int64]*Si1Type, typeID Si1LookupTypeID) (bool, error) {
+	typ, ok := lookup[typeID.Int64()]
+	if !ok {
+		return false, fmt.Errorf("type %v not found in the portable registry", typeID.Int64())
+	}
+	if len(typ.Path) == 0 {
+		return false, fmt.Errorf("type %v is not a valid bit order type", typeID.Int64())
+	}
+	switch typ.Path[len(typ.Path)-1] {
+	case "Lsb0":
+		return false, nil
+	case "Msb0":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unsupported bit order type %v", typ.Path[len(typ.Path)-1])
+	}
+}
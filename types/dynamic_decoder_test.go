@@ -0,0 +1,278 @@
+package types_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// Type ids of the portable registry of MetadataV14Data
+var (
+	accountInfoTypeID    = NewSi1LookupTypeIDFromUInt(3)
+	bytesTypeID          = NewSi1LookupTypeIDFromUInt(10)
+	digestItemTypeID     = NewSi1LookupTypeIDFromUInt(13)
+	optionChangesTrieID  = NewSi1LookupTypeIDFromUInt(16)
+	compactPerbillTypeID = NewSi1LookupTypeIDFromUInt(154)
+	bitSequenceTypeID    = NewSi1LookupTypeIDFromUInt(318)
+	eraTypeID            = NewSi1LookupTypeIDFromUInt(581)
+)
+
+func decodeMetadataV14(t *testing.T) *MetadataV14 {
+	var meta Metadata
+	err := DecodeFromHexString(MetadataV14Data, &meta)
+	assert.NoError(t, err)
+	return &meta.AsMetadataV14
+}
+
+func TestMetadataV14_DecodeDynamicValue_Composite(t *testing.T) {
+	meta := decodeMetadataV14(t)
+
+	type accountData struct {
+		Free       U128
+		Reserved   U128
+		MiscFrozen U128
+		FeeFrozen  U128
+	}
+	info := struct {
+		Nonce       U32
+		Consumers   U32
+		Providers   U32
+		Sufficients U32
+		Data        accountData
+	}{
+		Nonce:     7,
+		Providers: 1,
+		Data: accountData{
+			Free:       NewU128(*big.NewInt(1000000000000)),
+			Reserved:   NewU128(*big.NewInt(0)),
+			MiscFrozen: NewU128(*big.NewInt(42)),
+			FeeFrozen:  NewU128(*big.NewInt(0)),
+		},
+	}
+	bz, err := EncodeToBytes(info)
+	assert.NoError(t, err)
+
+	v, err := meta.DecodeDynamicValue(bz, accountInfoTypeID)
+	assert.NoError(t, err)
+	assert.True(t, v.IsComposite)
+	assert.Len(t, v.AsComposite, 5)
+
+	nonce, ok := v.Field("nonce")
+	assert.True(t, ok)
+	assert.Equal(t, U32(7), nonce.AsPrimitive)
+
+	data, ok := v.Field("data")
+	assert.True(t, ok)
+	free, ok := data.Field("free")
+	assert.True(t, ok)
+	assert.True(t, free.IsPrimitive)
+	assert.Equal(t, Si0TypeDefPrimitive(IsU128), free.Primitive)
+	assert.Equal(t, "1000000000000", free.AsPrimitive.(U128).String())
+
+	_, ok = v.Field("unknown")
+	assert.False(t, ok)
+
+	assertDynamicRoundtrip(t, v, bz)
+}
+
+func TestMetadataV14_DecodeDynamicValue_Variant(t *testing.T) {
+	meta := decodeMetadataV14(t)
+
+	// DigestItem::Seal(ConsensusEngineId, Vec<u8>)
+	bz := append([]byte{5, 'B', 'A', 'B', 'E', 3 << 2}, 1, 2, 3)
+	v, err := meta.DecodeDynamicValue(bz, digestItemTypeID)
+	assert.NoError(t, err)
+	assert.True(t, v.IsVariant)
+	assert.Equal(t, NewText("Seal"), v.AsVariant.Name)
+	assert.Equal(t, U8(5), v.AsVariant.Index)
+	assert.Len(t, v.AsVariant.Fields, 2)
+
+	engine, ok := v.AsVariant.Fields[0].Value.Bytes()
+	assert.True(t, ok)
+	assert.Equal(t, []byte("BABE"), engine)
+	assert.True(t, v.AsVariant.Fields[1].Value.IsSequence)
+
+	assertDynamicRoundtrip(t, v, bz)
+
+	// Era::Mortal5(u8)
+	bz = []byte{0x05, 0x02}
+	v, err = meta.DecodeDynamicValue(bz, eraTypeID)
+	assert.NoError(t, err)
+	assert.Equal(t, NewText("Mortal5"), v.AsVariant.Name)
+	assertDynamicRoundtrip(t, v, bz)
+
+	// Option::None
+	v, err = meta.DecodeDynamicValue([]byte{0}, optionChangesTrieID)
+	assert.NoError(t, err)
+	assert.Equal(t, NewText("None"), v.AsVariant.Name)
+	assert.Len(t, v.AsVariant.Fields, 0)
+}
+
+func TestMetadataV14_DecodeDynamicValue_SequenceAndArray(t *testing.T) {
+	meta := decodeMetadataV14(t)
+
+	bz, err := EncodeToBytes(NewBytes([]byte{0xde, 0xad, 0xbe, 0xef}))
+	assert.NoError(t, err)
+
+	v, err := meta.DecodeDynamicValue(bz, bytesTypeID)
+	assert.NoError(t, err)
+	assert.True(t, v.IsSequence)
+	assert.Len(t, v.AsSequence, 4)
+	b, ok := v.Bytes()
+	assert.True(t, ok)
+	assert.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, b)
+	assertDynamicRoundtrip(t, v, bz)
+
+	// AccountId32 is a composite wrapping a [u8; 32]
+	bz, err = EncodeToBytes(NewAccountID([]byte{1, 2, 3}))
+	assert.NoError(t, err)
+	v, err = meta.DecodeDynamicValue(bz, NewSi1LookupTypeIDFromUInt(0))
+	assert.NoError(t, err)
+	assert.True(t, v.IsComposite)
+	assert.True(t, v.AsComposite[0].Value.IsArray)
+	b, ok = v.Bytes()
+	assert.True(t, ok)
+	assert.Equal(t, bz, b)
+
+	// an empty Vec<u8> is bytes, an empty Vec<DigestItem> isn't
+	v, err = meta.DecodeDynamicValue([]byte{0}, bytesTypeID)
+	assert.NoError(t, err)
+	b, ok = v.Bytes()
+	assert.True(t, ok)
+	assert.Equal(t, []byte{}, b)
+	j, err := json.Marshal(v)
+	assert.NoError(t, err)
+	assert.Equal(t, `"0x"`, string(j))
+	assertDynamicRoundtrip(t, v, []byte{0})
+
+	v, err = meta.DecodeDynamicValue([]byte{0}, NewSi1LookupTypeIDFromUInt(12))
+	assert.NoError(t, err)
+	_, ok = v.Bytes()
+	assert.False(t, ok)
+	j, err = json.Marshal(v)
+	assert.NoError(t, err)
+	assert.Equal(t, `[]`, string(j))
+}
+
+func TestMetadataV14_DecodeDynamicValue_Compact(t *testing.T) {
+	meta := decodeMetadataV14(t)
+
+	bz, err := EncodeToBytes(NewUCompactFromUInt(500000000))
+	assert.NoError(t, err)
+
+	v, err := meta.DecodeDynamicValue(bz, compactPerbillTypeID)
+	assert.NoError(t, err)
+	assert.True(t, v.IsCompact)
+	assert.Equal(t, NewUCompactFromUInt(500000000), v.AsCompact)
+	assertDynamicRoundtrip(t, v, bz)
+}
+
+func TestMetadataV14_DecodeDynamicValue_BitSequence(t *testing.T) {
+	meta := decodeMetadataV14(t)
+
+	// 10 bits in Lsb0 order over an u8 store
+	bz := []byte{10 << 2, 0x05, 0x02}
+	v, err := meta.DecodeDynamicValue(bz, bitSequenceTypeID)
+	assert.NoError(t, err)
+	assert.True(t, v.IsBitSequence)
+	assert.Equal(t, BitSequence{
+		Bits:      []bool{true, false, true, false, false, false, false, false, false, true},
+		StoreSize: 1,
+	}, v.AsBitSequence)
+	assert.Equal(t, "0b1010000001", v.AsBitSequence.String())
+	assertDynamicRoundtrip(t, v, bz)
+}
+
+func TestBitSequence_EncodeMsb0(t *testing.T) {
+	assertEncode(t, []encodingAssert{
+		{BitSequence{Bits: []bool{true, false, true}, StoreSize: 1, IsMsb0: true}, []byte{3 << 2, 0xa0}},
+		{BitSequence{Bits: []bool{true}, StoreSize: 2}, []byte{1 << 2, 0x01, 0x00}},
+		{BitSequence{Bits: []bool{}, StoreSize: 1}, []byte{0}},
+	})
+}
+
+func TestMetadataV14_DecodeDynamicValue_Errors(t *testing.T) {
+	meta := decodeMetadataV14(t)
+
+	_, err := meta.DecodeDynamicValue([]byte{0}, NewSi1LookupTypeIDFromUInt(100000))
+	assert.EqualError(t, err, "type 100000 not found in the portable registry")
+
+	_, err = meta.DecodeDynamicValue([]byte{0, 1}, optionChangesTrieID)
+	assert.EqualError(t, err, "decoded type 16 but 1 bytes remain")
+
+	_, err = meta.DecodeDynamicValue([]byte{42}, digestItemTypeID)
+	assert.EqualError(t, err, "variant index 42 not found")
+
+	_, err = meta.DecodeDynamicValue([]byte{1, 0}, optionChangesTrieID)
+	assert.Error(t, err)
+}
+
+func TestMetadataV14_DecodeDynamicValue_WithoutEfficientLookup(t *testing.T) {
+	meta := decodeMetadataV14(t)
+	meta.EfficientLookup = nil
+
+	v, err := meta.DecodeDynamicValue([]byte{0}, optionChangesTrieID)
+	assert.NoError(t, err)
+	assert.True(t, v.IsVariant)
+
+	// the lookup isn't stored, so metadata created in code can be read concurrently
+	metaV15 := metadataV15FromV14(t)
+	metaV15.AsMetadataV15.EfficientLookup = nil
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := meta.DecodeDynamicValue([]byte{0}, optionChangesTrieID)
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := metaV15.AsMetadataV15.DecodeDynamicConstant("System", "SS58Prefix")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Nil(t, meta.EfficientLookup)
+	assert.Nil(t, metaV15.AsMetadataV15.EfficientLookup)
+}
+
+func TestDynamicValue_MarshalJSON(t *testing.T) {
+	meta := decodeMetadataV14(t)
+
+	bz := append([]byte{5, 'B', 'A', 'B', 'E', 2 << 2}, 0xbe, 0xef)
+	v, err := meta.DecodeDynamicValue(bz, digestItemTypeID)
+	assert.NoError(t, err)
+
+	j, err := json.Marshal(v)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Seal":["0x42414245","0xbeef"]}`, string(j))
+
+	bz, err = EncodeToBytes(struct {
+		DigestInterval U32
+		DigestLevels   U32
+	}{2, 3})
+	assert.NoError(t, err)
+	v, err = meta.DecodeDynamicValue(append([]byte{1}, bz...), optionChangesTrieID)
+	assert.NoError(t, err)
+
+	j, err = json.Marshal(v)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Some":{"digest_interval":2,"digest_levels":3}}`, string(j))
+
+	v, err = meta.DecodeDynamicValue([]byte{0}, optionChangesTrieID)
+	assert.NoError(t, err)
+	j, err = json.Marshal(v)
+	assert.NoError(t, err)
+	assert.Equal(t, `"None"`, string(j))
+}
+
+func assertDynamicRoundtrip(t *testing.T, v DynamicValue, expected []byte) {
+	encoded, err := EncodeToBytes(v)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, encoded)
+}
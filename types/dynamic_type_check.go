@@ -0,0 +1,168 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/scale"
+)
+
+var (
+	encodeableType  = reflect.TypeOf((*scale.Encodeable)(nil)).Elem()
+	ucompactType    = reflect.TypeOf(UCompact{})
+	bitSequenceType = reflect.TypeOf(BitSequence{})
+	callType        = reflect.TypeOf(Call{})
+)
+
+// primitiveGoTypes lists the Go types that are encoded like the primitives of the portable registry that are not
+// covered by a reflect.Kind
+var primitiveGoTypes = map[Si0TypeDefPrimitive]reflect.Type{
+	IsU128: reflect.TypeOf(U128{}),
+	IsU256: reflect.TypeOf(U256{}),
+	IsI128: reflect.TypeOf(I128{}),
+	IsI256: reflect.TypeOf(I256{}),
+}
+
+// primitiveKinds lists the reflect.Kind of Go types that are encoded like the primitives of the portable registry
+var primitiveKinds = map[Si0TypeDefPrimitive]reflect.Kind{
+	IsBool: reflect.Bool,
+	IsChar: reflect.Int32,
+	IsStr:  reflect.String,
+	IsU8:   reflect.Uint8,
+	IsU16:  reflect.Uint16,
+	IsU32:  reflect.Uint32,
+	IsU64:  reflect.Uint64,
+	IsI8:   reflect.Int8,
+	IsI16:  reflect.Int16,
+	IsI32:  reflect.Int32,
+	IsI64:  reflect.Int64,
+}
+
+var primitiveNames = map[Si0TypeDefPrimitive]string{
+	IsBool: "bool", IsChar: "char", IsStr: "str",
+	IsU8: "u8", IsU16: "u16", IsU32: "u32", IsU64: "u64", IsU128: "u128", IsU256: "u256",
+	IsI8: "i8", IsI16: "i16", IsI32: "i32", IsI64: "i64", IsI128: "i128", IsI256: "i256",
+}
+
+// checkDynamicType returns an error if values of the Go type t are not encoded like values of the registry type
+// typeID. Go types with a custom Encode method can't be inspected and are accepted for all types but compacts.
+func checkDynamicType(lookup map[int64]*Si1Type, typeID Si1LookupTypeID, t reflect.Type) error {
+	typ, ok := lookup[typeID.Int64()]
+	if !ok {
+		return fmt.Errorf("type %v not found in the portable registry", typeID.Int64())
+	}
+	def := typ.Def
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	mismatch := fmt.Errorf("expected %v, got %v", dynamicTypeName(lookup, typeID), t)
+
+	switch {
+	case t == ucompactType:
+		if def.IsCompact {
+			return nil
+		}
+		return mismatch
+	case def.IsCompact:
+		return mismatch
+	case t == callType:
+		// calls are encoded like the variants of the outer call type
+		if def.IsVariant {
+			return nil
+		}
+		return mismatch
+	case t == bitSequenceType:
+		if def.IsBitSequence {
+			return nil
+		}
+		return mismatch
+	case def.IsPrimitive && primitiveGoTypes[def.Primitive.Si0TypeDefPrimitive] != nil:
+		if t == primitiveGoTypes[def.Primitive.Si0TypeDefPrimitive] {
+			return nil
+		}
+		return mismatch
+	case t.Implements(encodeableType):
+		return nil
+	}
+
+	switch {
+	case def.IsPrimitive:
+		kind, ok := primitiveKinds[def.Primitive.Si0TypeDefPrimitive]
+		if ok && kind == t.Kind() {
+			return nil
+		}
+	case def.IsSequence:
+		if t.Kind() == reflect.Slice {
+			return checkDynamicType(lookup, def.Sequence.Type, t.Elem())
+		}
+		// strings are encoded like Vec<u8>
+		if t.Kind() == reflect.String && checkDynamicType(lookup, def.Sequence.Type, reflect.TypeOf(U8(0))) == nil {
+			return nil
+		}
+	case def.IsArray:
+		if t.Kind() == reflect.Array && t.Len() == int(def.Array.Len) {
+			return checkDynamicType(lookup, def.Array.Type, t.Elem())
+		}
+	case def.IsTuple:
+		if t.Kind() == reflect.Struct {
+			fields := encodedStructFields(t)
+			if len(fields) == len(def.Tuple) {
+				for i, f := range fields {
+					err := checkDynamicType(lookup, def.Tuple[i], f.Type)
+					if err != nil {
+						return fmt.Errorf("tuple element %v: %v", i, err)
+					}
+				}
+				return nil
+			}
+		}
+	case def.IsComposite:
+		if t.Kind() == reflect.Struct {
+			fields := encodedStructFields(t)
+			if len(fields) == len(def.Composite.Fields) {
+				for i, f := range fields {
+					err := checkDynamicType(lookup, def.Composite.Fields[i].Type, f.Type)
+					if err != nil {
+						return fmt.Errorf("field %v: %v", f.Name, err)
+					}
+				}
+				return nil
+			}
+		}
+		// composites with a single field, such as AccountId32 or Perbill, are encoded like their field
+		if len(def.Composite.Fields) == 1 && checkDynamicType(lookup, def.Composite.Fields[0].Type, t) == nil {
+			return nil
+		}
+	}
+
+	// Go structs with a single field are encoded like their field
+	if t.Kind() == reflect.Struct {
+		fields := encodedStructFields(t)
+		if len(fields) == 1 && checkDynamicType(lookup, typeID, fields[0].Type) == nil {
+			return nil
+		}
+	}
+
+	return mismatch
+}
+
+// encodedStructFields returns the fields of the struct type t that are encoded
+func encodedStructFields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if tv, ok := f.Tag.Lookup("scale"); ok && tv == "-" {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// dynamicTypeName returns a human readable name of the registry type typeID with its full path, used in error
+// messages
+func dynamicTypeName(lookup map[int64]*Si1Type, typeID Si1LookupTypeID) string {
+	return newTypeStringer(lookup, true).typeString(typeID)
+}
@@ -0,0 +1,315 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/scale"
+)
+
+// DynamicValue is a value that has been decoded at runtime by walking a type of the portable registry, instead of
+// being decoded into a static Go type. Exactly one of the Is* flags is set, mirroring the variants of Si1TypeDef.
+type DynamicValue struct {
+	// Type is the id of the registry type the value was decoded with
+	Type Si1LookupTypeID
+
+	IsComposite bool
+	AsComposite []DynamicField
+
+	IsVariant bool
+	AsVariant DynamicVariant
+
+	IsSequence bool
+	AsSequence []DynamicValue
+
+	IsArray bool
+	AsArray []DynamicValue
+	// HasU8Elements is true if the sequence or array has been decoded with u8 elements, which tells empty byte
+	// sequences apart from other empty sequences
+	HasU8Elements bool
+
+	IsTuple bool
+	AsTuple []DynamicValue
+
+	IsPrimitive bool
+	Primitive   Si0TypeDefPrimitive
+	// AsPrimitive holds one of Bool, rune, Text, U8, U16, U32, U64, U128, U256, I8, I16, I32, I64, I128 or I256
+	AsPrimitive interface{}
+
+	IsCompact bool
+	AsCompact UCompact
+
+	IsBitSequence bool
+	AsBitSequence BitSequence
+}
+
+// DynamicField is a single, optionally named field of a composite or of an enum variant
+type DynamicField struct {
+	Name     Text
+	TypeName Text
+	Value    DynamicValue
+}
+
+// DynamicVariant is the selected variant of an enum, together with its fields
+type DynamicVariant struct {
+	Name   Text
+	Index  U8
+	Fields []DynamicField
+}
+
+// BitSequence is a decoded bitvec::BitVec
+type BitSequence struct {
+	Bits []bool
+	// StoreSize is the size in bytes of the store type of the bit sequence (1 for u8, up to 8 for u64)
+	StoreSize int
+	// IsMsb0 is true if the bits are ordered from the most significant bit of the store type
+	IsMsb0 bool
+}
+
+// Field returns the value of the field with the given name of a composite or a variant
+func (v DynamicValue) Field(name string) (DynamicValue, bool) {
+	var fields []DynamicField
+	switch {
+	case v.IsComposite:
+		fields = v.AsComposite
+	case v.IsVariant:
+		fields = v.AsVariant.Fields
+	default:
+		return DynamicValue{}, false
+	}
+
+	for _, f := range fields {
+		if string(f.Name) == name {
+			return f.Value, true
+		}
+	}
+	return DynamicValue{}, false
+}
+
+// Bytes returns the content of a sequence or array of u8 values, as well as of composites wrapping exactly one such
+// field, e.g. AccountId32 or H256. Empty sequences are only bytes if they have been decoded with u8 elements.
+func (v DynamicValue) Bytes() ([]byte, bool) {
+	var items []DynamicValue
+	switch {
+	case v.IsSequence:
+		items = v.AsSequence
+	case v.IsArray:
+		items = v.AsArray
+	case v.IsComposite && len(v.AsComposite) == 1:
+		return v.AsComposite[0].Value.Bytes()
+	default:
+		return nil, false
+	}
+	if len(items) == 0 {
+		if v.HasU8Elements {
+			return []byte{}, true
+		}
+		return nil, false
+	}
+
+	bz := make([]byte, len(items))
+	for i, item := range items {
+		u, ok := item.AsPrimitive.(U8)
+		if !item.IsPrimitive || !ok {
+			return nil, false
+		}
+		bz[i] = byte(u)
+	}
+	return bz, true
+}
+
+// Encode implements encoding for DynamicValue, producing the same bytes the value has been decoded from
+func (v DynamicValue) Encode(encoder scale.Encoder) error {
+	switch {
+	case v.IsComposite:
+		return encodeDynamicFields(encoder, v.AsComposite)
+	case v.IsVariant:
+		err := encoder.PushByte(byte(v.AsVariant.Index))
+		if err != nil {
+			return err
+		}
+		return encodeDynamicFields(encoder, v.AsVariant.Fields)
+	case v.IsSequence:
+		err := encoder.EncodeUintCompact(*big.NewInt(int64(len(v.AsSequence))))
+		if err != nil {
+			return err
+		}
+		return encodeDynamicValues(encoder, v.AsSequence)
+	case v.IsArray:
+		return encodeDynamicValues(encoder, v.AsArray)
+	case v.IsTuple:
+		return encodeDynamicValues(encoder, v.AsTuple)
+	case v.IsPrimitive:
+		return encoder.Encode(v.AsPrimitive)
+	case v.IsCompact:
+		return encoder.Encode(v.AsCompact)
+	case v.IsBitSequence:
+		return encoder.Encode(v.AsBitSequence)
+	default:
+		return fmt.Errorf("expected DynamicValue to be one of the valid variants")
+	}
+}
+
+func encodeDynamicFields(encoder scale.Encoder, fields []DynamicField) error {
+	for _, f := range fields {
+		err := encoder.Encode(f.Value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeDynamicValues(encoder scale.Encoder, values []DynamicValue) error {
+	for _, v := range values {
+		err := encoder.Encode(v)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MarshalJSON returns a JSON representation of the value that follows the conventions of polkadot-js: named fields
+// become objects, variants without fields become strings, byte sequences become hex strings and big numbers become
+// decimal strings
+func (v DynamicValue) MarshalJSON() ([]byte, error) {
+	if bz, ok := v.Bytes(); ok && !v.IsComposite {
+		return json.Marshal(HexEncodeToString(bz))
+	}
+
+	switch {
+	case v.IsComposite:
+		return marshalDynamicFields(v.AsComposite)
+	case v.IsVariant:
+		if len(v.AsVariant.Fields) == 0 {
+			return json.Marshal(v.AsVariant.Name)
+		}
+		fields, err := marshalDynamicFields(v.AsVariant.Fields)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]json.RawMessage{string(v.AsVariant.Name): fields})
+	case v.IsSequence:
+		return marshalDynamicValues(v.AsSequence)
+	case v.IsArray:
+		return marshalDynamicValues(v.AsArray)
+	case v.IsTuple:
+		return marshalDynamicValues(v.AsTuple)
+	case v.IsPrimitive:
+		switch p := v.AsPrimitive.(type) {
+		case U128:
+			return json.Marshal(p.String())
+		case U256:
+			return json.Marshal(p.String())
+		case I128:
+			return json.Marshal(p.String())
+		case I256:
+			return json.Marshal(p.String())
+		case rune:
+			return json.Marshal(string(p))
+		default:
+			return json.Marshal(p)
+		}
+	case v.IsCompact:
+		i := big.Int(v.AsCompact)
+		return json.Marshal(i.String())
+	case v.IsBitSequence:
+		return json.Marshal(v.AsBitSequence.String())
+	default:
+		return nil, fmt.Errorf("expected DynamicValue to be one of the valid variants")
+	}
+}
+
+// marshalDynamicFields renders fields as an object if they are named. A single unnamed field is rendered as its
+// value, several unnamed fields as an array.
+func marshalDynamicFields(fields []DynamicField) ([]byte, error) {
+	if len(fields) == 0 || fields[0].Name == "" {
+		values := make([]DynamicValue, len(fields))
+		for i, f := range fields {
+			values[i] = f.Value
+		}
+		if len(values) == 1 {
+			return json.Marshal(values[0])
+		}
+		return marshalDynamicValues(values)
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		name, err := json.Marshal(f.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		sb.Write(name)
+		sb.WriteByte(':')
+		sb.Write(value)
+	}
+	sb.WriteByte('}')
+	return []byte(sb.String()), nil
+}
+
+func marshalDynamicValues(values []DynamicValue) ([]byte, error) {
+	if values == nil {
+		values = []DynamicValue{}
+	}
+	return json.Marshal(values)
+}
+
+// Encode implements encoding for BitSequence as per the bitvec implementation of the Scale codec
+func (b BitSequence) Encode(encoder scale.Encoder) error {
+	err := encoder.EncodeUintCompact(*big.NewInt(int64(len(b.Bits))))
+	if err != nil {
+		return err
+	}
+
+	storeBits := b.StoreSize * 8
+	if storeBits == 0 {
+		return fmt.Errorf("invalid store size %v for BitSequence", b.StoreSize)
+	}
+
+	for start := 0; start < len(b.Bits); start += storeBits {
+		var elem uint64
+		for i := 0; i < storeBits && start+i < len(b.Bits); i++ {
+			if !b.Bits[start+i] {
+				continue
+			}
+			if b.IsMsb0 {
+				elem |= 1 << uint(storeBits-1-i)
+			} else {
+				elem |= 1 << uint(i)
+			}
+		}
+		for i := 0; i < b.StoreSize; i++ {
+			err = encoder.PushByte(byte(elem >> uint(8*i)))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// String returns the bits in order, prefixed with 0b
+func (b BitSequence) String() string {
+	var sb strings.Builder
+	sb.WriteString("0b")
+	for _, bit := range b.Bits {
+		if bit {
+			sb.WriteByte('1')
+		} else {
+			sb.WriteByte('0')
+		}
+	}
+	return sb.String()
+}
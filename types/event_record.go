@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"strings"
 
 	"github.com/Phala-Network/go-substrate-rpc-client/v3/scale"
 	"github.com/ethereum/go-ethereum/log"
@@ -364,6 +365,112 @@ func (e EventRecordsRaw) DecodeEventRecords(m *Metadata, t interface{}) error {
 	return nil
 }
 
+// DynamicEventRecord is an event record that has been decoded by walking the event types of the metadata, so it does
+// not need a static Go struct like the ones of EventRecords
+type DynamicEventRecord struct {
+	Phase  Phase
+	ID     EventID
+	Pallet Text
+	Name   Text
+	Fields []DynamicField
+	Topics []Hash
+}
+
+// Field returns the value of the event field with the given name
+func (r DynamicEventRecord) Field(name string) (DynamicValue, bool) {
+	for _, f := range r.Fields {
+		if string(f.Name) == name {
+			return f.Value, true
+		}
+	}
+	return DynamicValue{}, false
+}
+
+// DecodeDynamicEventRecords decodes the events records from an EventRecordRaw using the given Metadata m, which must
+// be of version 14 or above. Contrary to DecodeEventRecords, no target struct is needed: the fields of each event are
+// decoded with the variant of the pallet's event type in the portable registry.
+func (e EventRecordsRaw) DecodeDynamicEventRecords(m *Metadata) ([]DynamicEventRecord, error) {
+	log.Debug(fmt.Sprintf("will decode dynamic event records from raw hex: %#x", e))
+
+	var lookup map[int64]*Si1Type
+	var pallets []PalletMetadataV14
+	switch m.Version {
+	case 14:
+		lookup, pallets = m.AsMetadataV14.lookup(), m.AsMetadataV14.Pallets
+	case 15:
+		lookup, pallets = m.AsMetadataV15.lookup(), m.AsMetadataV15.palletsV14()
+	default:
+		return nil, fmt.Errorf("dynamic decoding of events is not supported for metadata version %v", m.Version)
+	}
+
+	return e.decodeDynamicEventRecords(lookup, func(id EventID) (Text, *Si1Variant, error) {
+		mod, variant, err := findEventVariant(lookup, pallets, id)
+		if err != nil {
+			return "", nil, err
+		}
+		return mod.Name, variant, nil
+	})
+}
+
+// decodeDynamicEventRecords decodes the event records with the event variants returned by findEventVariant, which
+// resolves an EventID to the name of the event's module and the variant of the module's event type in lookup
+func (e EventRecordsRaw) decodeDynamicEventRecords(lookup map[int64]*Si1Type,
+	findEventVariant func(EventID) (Text, *Si1Variant, error)) ([]DynamicEventRecord, error) {
+	decoder := scale.NewDecoder(bytes.NewReader(e))
+
+	// determine number of events
+	n, err := decoder.DecodeUintCompact()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug(fmt.Sprintf("found %v events", n))
+
+	var records []DynamicEventRecord
+
+	// iterate over events
+	for i := uint64(0); i < n.Uint64(); i++ {
+		log.Debug(fmt.Sprintf("decoding event #%v", i))
+
+		var record DynamicEventRecord
+
+		err := decoder.Decode(&record.Phase)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode Phase for event #%v: %v", i, err)
+		}
+
+		err = decoder.Decode(&record.ID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode EventID for event #%v: %v", i, err)
+		}
+
+		pallet, variant, err := findEventVariant(record.ID)
+		if err != nil {
+			return nil, err
+		}
+		record.Pallet = pallet
+		record.Name = variant.Name
+
+		log.Debug(fmt.Sprintf("event #%v is in module %v with event name %v", i, record.Pallet, record.Name))
+
+		record.Fields, err = decodeDynamicFields(*decoder, lookup, variant.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode event #%v with EventID %v, %v_%v: %v", i, record.ID,
+				record.Pallet, record.Name, err)
+		}
+
+		err = decoder.Decode(&record.Topics)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode Topics for event #%v: %v", i, err)
+		}
+
+		records = append(records, record)
+
+		log.Debug(fmt.Sprintf("decoded event #%v", i))
+	}
+	return records, nil
+}
+
 // Phase is an enum describing the current phase of the event (applying the extrinsic or finalized)
 type Phase struct {
 	IsApplyExtrinsic bool
@@ -484,4 +591,29 @@ func (d DispatchError) Encode(encoder scale.Encoder) error {
 	return err
 }
 
+// ModuleError is a module error of a DispatchError that has been resolved to the error of the module's metadata. It
+// implements error, so it can be returned to describe a failed extrinsic.
+type ModuleError struct {
+	Module Text
+	Name   Text
+	Docs   []Text
+	// ModuleIndex and ErrorIndex are the indices of the DispatchError the error has been resolved from
+	ModuleIndex uint8
+	ErrorIndex  uint8
+}
+
+// Error returns the module and name of the error, e.g. Balances.InsufficientBalance, followed by its documentation
+func (e ModuleError) Error() string {
+	s := fmt.Sprintf("%v.%v", e.Module, e.Name)
+	if len(e.Docs) == 0 {
+		return s
+	}
+
+	docs := make([]string, len(e.Docs))
+	for i, d := range e.Docs {
+		docs[i] = strings.TrimSpace(string(d))
+	}
+	return s + ": " + strings.Join(docs, " ")
+}
+
 type EventID [2]byte
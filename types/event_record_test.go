@@ -228,3 +228,143 @@ func TestPhase(t *testing.T) {
 	assertRoundtrip(t, Phase{IsFinalization: true})
 	assertRoundtrip(t, Phase{IsInitialization: true})
 }
+
+func TestEventRecordsRaw_DecodeDynamicEventRecords(t *testing.T) {
+	var meta Metadata
+	err := DecodeFromHexString(MetadataV14Data, &meta)
+	assert.NoError(t, err)
+
+	// module index of Balances in MetadataV14Data is 5, of System is 0
+	e := EventRecordsRaw(MustHexDecodeString(
+		"0x0c" + // (len 3) << 2
+
+			"0001000000" + // ApplyExtrinsic(1)
+			"0502" + // Balances_Transfer
+			"d43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d" + // From
+			"8eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f26a48" + // To
+			"391b0000000000000000000000000000" + // Value
+			"00" + // Topics
+
+			"0001000000" + // ApplyExtrinsic(1)
+			"0000" + // System_ExtrinsicSuccess
+			"1027000000000000" + // Weight
+			"00" + // Class Normal
+			"00" + // Pays Yes
+			"00" + // Topics
+
+			"01" + // Finalization
+			"0001" + // System_ExtrinsicFailed
+			"0305" + // DispatchError Module
+			"02" + // Module error
+			"1027000000000000" + // Weight
+			"02" + // Class Mandatory
+			"01" + // Pays No
+			"04" + // Topics
+			"0102000000000000000000000000000000000000000000000000000000000000",
+	))
+
+	events, err := e.DecodeDynamicEventRecords(&meta)
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+
+	transfer := events[0]
+	assert.Equal(t, Phase{IsApplyExtrinsic: true, AsApplyExtrinsic: 1}, transfer.Phase)
+	assert.Equal(t, EventID{5, 2}, transfer.ID)
+	assert.Equal(t, NewText("Balances"), transfer.Pallet)
+	assert.Equal(t, NewText("Transfer"), transfer.Name)
+	assert.Len(t, transfer.Fields, 3)
+	from, ok := transfer.Fields[0].Value.Bytes()
+	assert.True(t, ok)
+	assert.Equal(t, MustHexDecodeString("0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d"), from)
+	assert.Equal(t, "6969", transfer.Fields[2].Value.AsPrimitive.(U128).String())
+	assert.Nil(t, transfer.Topics)
+
+	success := events[1]
+	assert.Equal(t, NewText("System"), success.Pallet)
+	assert.Equal(t, NewText("ExtrinsicSuccess"), success.Name)
+	weight, ok := success.Fields[0].Value.Field("weight")
+	assert.True(t, ok)
+	assert.Equal(t, U64(10000), weight.AsPrimitive)
+
+	failed := events[2]
+	assert.Equal(t, Phase{IsFinalization: true}, failed.Phase)
+	assert.Equal(t, NewText("ExtrinsicFailed"), failed.Name)
+	dispatchError := failed.Fields[0].Value
+	assert.Equal(t, NewText("Module"), dispatchError.AsVariant.Name)
+	assert.Equal(t, []Hash{{1, 2}}, failed.Topics)
+
+	// the same runtime in V15
+	v15Events, err := e.DecodeDynamicEventRecords(metadataV15FromV14(t))
+	assert.NoError(t, err)
+	assert.Equal(t, events, v15Events)
+}
+
+func TestEventRecordsRaw_DecodeDynamicEventRecords_Fails(t *testing.T) {
+	var meta Metadata
+	err := DecodeFromHexString(MetadataV14Data, &meta)
+	assert.NoError(t, err)
+
+	// unknown event
+	e := EventRecordsRaw(MustHexDecodeString("0x04" + "0001000000" + "052a" + "00"))
+	_, err = e.DecodeDynamicEventRecords(&meta)
+	assert.Error(t, err)
+	_, _, err = meta.FindEventNamesForEventID(EventID{5, 42})
+	assert.EqualError(t, err, "event index 42 not found within module Balances")
+	_, _, err = meta.FindEventNamesForEventID(EventID{200, 0})
+	assert.EqualError(t, err, "module index 200 out of range")
+
+	// missing fields
+	e = EventRecordsRaw(MustHexDecodeString("0x04" + "0001000000" + "0502" + "d435"))
+	_, err = e.DecodeDynamicEventRecords(&meta)
+	assert.Error(t, err)
+
+	// metadata without portable registry
+	_, err = e.DecodeDynamicEventRecords(ExamplaryMetadataV13)
+	assert.EqualError(t, err, "dynamic decoding of events is not supported for metadata version 13")
+}
+
+func TestMetadata_FindModuleError(t *testing.T) {
+	var meta Metadata
+	err := DecodeFromHexString(MetadataV14Data, &meta)
+	assert.NoError(t, err)
+
+	// module index of Balances in MetadataV14Data is 5
+	moduleErr, err := meta.FindModuleError(DispatchError{Error: 3, HasModule: true, Module: 5, ModuleError: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, ModuleError{
+		Module:      "Balances",
+		Name:        "InsufficientBalance",
+		Docs:        []Text{"Balance too low to send value"},
+		ModuleIndex: 5,
+		ErrorIndex:  2,
+	}, moduleErr)
+	assert.EqualError(t, moduleErr, "Balances.InsufficientBalance: Balance too low to send value")
+
+	_, err = meta.FindModuleError(DispatchError{Error: 3, HasModule: true, Module: 5, ModuleError: 42})
+	assert.EqualError(t, err, "error index 42 for module Balances out of range")
+
+	_, err = meta.FindModuleError(DispatchError{Error: 3, HasModule: true, Module: 200})
+	assert.EqualError(t, err, "module index 200 out of range")
+
+	_, err = meta.FindModuleError(DispatchError{Error: 2})
+	assert.EqualError(t, err, "dispatch error 2 is not a module error")
+
+	moduleErr, err = ExamplaryMetadataV13.FindModuleError(DispatchError{Error: 3, HasModule: true, Module: 6,
+		ModuleError: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, NewText("Balances"), moduleErr.Module)
+	assert.Equal(t, NewText("InsufficientBalance"), moduleErr.Name)
+
+	_, err = ExamplaryMetadataV13.FindModuleError(DispatchError{Error: 3, HasModule: true, Module: 1})
+	assert.EqualError(t, err, "error index 0 for module Utility out of range")
+
+	_, err = ExamplaryMetadataV10.FindModuleError(DispatchError{Error: 3, HasModule: true})
+	assert.EqualError(t, err, "unsupported metadata version")
+}
+
+func TestModuleError_Error(t *testing.T) {
+	assert.EqualError(t, ModuleError{Module: "System", Name: "CallFiltered"}, "System.CallFiltered")
+	assert.EqualError(t, ModuleError{Module: "Balances", Name: "ExistentialDeposit",
+		Docs: []Text{" Value too low to create account due to", " existential deposit"}},
+		"Balances.ExistentialDeposit: Value too low to create account due to existential deposit")
+}
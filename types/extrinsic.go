@@ -119,26 +119,45 @@ func (e Extrinsic) Type() uint8 {
 	return e.Version & ExtrinsicUnmaskVersion
 }
 
-// Sign adds a signature to the extrinsic
+// Sign adds a signature to the extrinsic. It assumes the signed extensions of the Substrate node template, use
+// SignWithMetadata for runtimes with other signed extensions.
 func (e *Extrinsic) Sign(signer signature.KeyringPair, o SignatureOptions) error {
-	if e.Type() != ExtrinsicVersion4 {
-		return fmt.Errorf("unsupported extrinsic version: %v (isSigned: %v, type: %v)", e.Version, e.IsSigned(), e.Type())
+	return e.SignWithSigner(NewKeyringPairSigner(signer), o)
+}
+
+// SignWithSigner adds a signature made by signer to the extrinsic, see Sign
+func (e *Extrinsic) SignWithSigner(signer Signer, o SignatureOptions) error {
+	payload, err := e.Payload(o)
+	if err != nil {
+		return err
 	}
 
-	mb, err := EncodeToBytes(e.Method)
+	sig, err := payload.SignWithSigner(signer)
 	if err != nil {
 		return err
 	}
 
-	era := o.Era
-	if !o.Era.IsMortalEra {
-		era = ExtrinsicEra{IsImmortalEra: true}
+	return e.AddSignature(signer.MultiAddress(), sig, o)
+}
+
+// Payload returns the signing payload of the extrinsic with the signature options o, as signed by Sign. Its encoding
+// can be exported with EncodeToHexString or NewSignerPayloadJSON to sign the extrinsic on another machine, and the
+// signature then be added with AddSignature.
+func (e Extrinsic) Payload(o SignatureOptions) (ExtrinsicPayloadV4, error) {
+	if e.Type() != ExtrinsicVersion4 {
+		return ExtrinsicPayloadV4{}, fmt.Errorf("unsupported extrinsic version: %v (isSigned: %v, type: %v)",
+			e.Version, e.IsSigned(), e.Type())
 	}
 
-	payload := ExtrinsicPayloadV4{
+	mb, err := EncodeToBytes(e.Method)
+	if err != nil {
+		return ExtrinsicPayloadV4{}, err
+	}
+
+	return ExtrinsicPayloadV4{
 		ExtrinsicPayloadV3: ExtrinsicPayloadV3{
 			Method:      mb,
-			Era:         era,
+			Era:         o.era(),
 			Nonce:       o.Nonce,
 			Tip:         o.Tip,
 			SpecVersion: o.SpecVersion,
@@ -146,28 +165,178 @@ func (e *Extrinsic) Sign(signer signature.KeyringPair, o SignatureOptions) error
 			BlockHash:   o.BlockHash,
 		},
 		TransactionVersion: o.TransactionVersion,
+	}, nil
+}
+
+// AddSignature adds the signature sig of the payload returned by Payload to the extrinsic, made by the signer with the
+// given address
+func (e *Extrinsic) AddSignature(signer MultiAddress, sig MultiSignature, o SignatureOptions) error {
+	if e.Type() != ExtrinsicVersion4 {
+		return fmt.Errorf("unsupported extrinsic version: %v (isSigned: %v, type: %v)", e.Version, e.IsSigned(), e.Type())
+	}
+
+	e.Signature = ExtrinsicSignatureV4{
+		Signer:    signer,
+		Signature: sig,
+		Era:       o.era(),
+		Nonce:     o.Nonce,
+		Tip:       o.Tip,
+	}
+
+	// mark the extrinsic as signed
+	e.Version |= ExtrinsicBitSigned
+
+	return nil
+}
+
+// VerifySignature verifies the signature of the extrinsic, rebuilding the signed ExtrinsicPayloadV4 from the era, nonce
+// and tip of the signature and the spec version, transaction version, genesis hash and block hash of o. The signer
+// must be an account id. Signatures with the extra data of the signed extensions of the metadata, such as those made
+// by SignWithMetadata, are verified with VerifySignatureWithMetadata.
+func (e Extrinsic) VerifySignature(o SignatureOptions) (bool, error) {
+	err := e.checkVerifiable()
+	if err != nil {
+		return false, err
+	}
+
+	if e.Signature.Extra != nil {
+		return false, fmt.Errorf("the signature has the extra data of signed extensions, verify it with " +
+			"VerifySignatureWithMetadata")
+	}
+
+	o.Era = e.Signature.Era
+	o.Nonce = e.Signature.Nonce
+	o.Tip = e.Signature.Tip
+	payload, err := e.Payload(o)
+	if err != nil {
+		return false, err
+	}
+
+	return e.verifyPayload(payload)
+}
+
+// VerifySignatureWithMetadata verifies the signature of the extrinsic, rebuilding the signed ExtrinsicPayloadDynamic
+// from the extra data of the signature and the additional signed data of the signed extensions listed in the
+// metadata, which are encoded with o. The extra data is set by SignWithMetadata and Metadata.DecodeExtrinsic.
+func (e Extrinsic) VerifySignatureWithMetadata(meta *Metadata, o SignatureOptions) (bool, error) {
+	err := e.checkVerifiable()
+	if err != nil {
+		return false, err
+	}
+
+	if e.Signature.Extra == nil {
+		return false, fmt.Errorf("the signature has no extra data of signed extensions, verify it with " +
+			"VerifySignature")
+	}
+
+	o.Era = e.Signature.Era
+	o.Nonce = e.Signature.Nonce
+	o.Tip = e.Signature.Tip
+	payload, err := e.PayloadWithMetadata(meta, o)
+	if err != nil {
+		return false, err
+	}
+	payload.Extensions.Extra = e.Signature.Extra
+
+	return e.verifyPayload(payload)
+}
+
+func (e Extrinsic) checkVerifiable() error {
+	if !e.IsSigned() {
+		return fmt.Errorf("extrinsic is not signed")
 	}
 
-	signerPubKey := NewMultiAddressFromAccountID(signer.PublicKey)
+	if !e.Signature.Signer.IsID {
+		return fmt.Errorf("unable to verify the signature of a signer without an account id")
+	}
+	return nil
+}
 
-	sig, err := payload.Sign(signer)
+func (e Extrinsic) verifyPayload(payload interface{}) (bool, error) {
+	b, err := EncodeToBytes(payload)
+	if err != nil {
+		return false, err
+	}
+
+	var scheme signature.Scheme
+	var sig []byte
+	switch s := e.Signature.Signature; {
+	case s.IsEd25519:
+		scheme, sig = signature.Ed25519, s.AsEd25519[:]
+	case s.IsSr25519:
+		scheme, sig = signature.Sr25519, s.AsSr25519[:]
+	case s.IsEcdsa:
+		scheme, sig = signature.Ecdsa, s.AsEcdsa
+	default:
+		return false, fmt.Errorf("unsupported multi signature")
+	}
+
+	return signature.VerifyWithAccountID(scheme, b, sig, e.Signature.Signer.AsID[:])
+}
+
+// SignWithMetadata adds a signature to the extrinsic, encoding the signed extensions listed in the metadata with the
+// encoders registered through RegisterSignedExtension. The signed extrinsic is decoded with Metadata.DecodeExtrinsic.
+func (e *Extrinsic) SignWithMetadata(meta *Metadata, signer signature.KeyringPair, o SignatureOptions) error {
+	return e.SignWithMetadataAndSigner(meta, NewKeyringPairSigner(signer), o)
+}
+
+// SignWithMetadataAndSigner adds a signature made by signer to the extrinsic, see SignWithMetadata
+func (e *Extrinsic) SignWithMetadataAndSigner(meta *Metadata, signer Signer, o SignatureOptions) error {
+	payload, err := e.PayloadWithMetadata(meta, o)
 	if err != nil {
 		return err
 	}
 
-	extSig := ExtrinsicSignatureV4{
-		Signer:    signerPubKey,
-		Signature: MultiSignature{IsSr25519: true, AsSr25519: sig},
-		Era:       era,
-		Nonce:     o.Nonce,
-		Tip:       o.Tip,
+	sig, err := payload.SignWithSigner(signer)
+	if err != nil {
+		return err
 	}
 
-	e.Signature = extSig
+	return e.addSignature(signer.MultiAddress(), sig, o, payload.Extensions)
+}
 
-	// mark the extrinsic as signed
-	e.Version |= ExtrinsicBitSigned
+// PayloadWithMetadata returns the signing payload of the extrinsic with the signature options o, as signed by
+// SignWithMetadata. The signature of the payload is added with AddSignatureWithMetadata.
+func (e Extrinsic) PayloadWithMetadata(meta *Metadata, o SignatureOptions) (ExtrinsicPayloadDynamic, error) {
+	if e.Type() != ExtrinsicVersion4 {
+		return ExtrinsicPayloadDynamic{}, fmt.Errorf("unsupported extrinsic version: %v (isSigned: %v, type: %v)",
+			e.Version, e.IsSigned(), e.Type())
+	}
+
+	mb, err := EncodeToBytes(e.Method)
+	if err != nil {
+		return ExtrinsicPayloadDynamic{}, err
+	}
+
+	extensions, err := meta.EncodeSignedExtensions(o)
+	if err != nil {
+		return ExtrinsicPayloadDynamic{}, err
+	}
+
+	return ExtrinsicPayloadDynamic{Method: mb, Extensions: extensions}, nil
+}
+
+// AddSignatureWithMetadata adds the signature sig of the payload returned by PayloadWithMetadata to the extrinsic,
+// made by the signer with the given address
+func (e *Extrinsic) AddSignatureWithMetadata(meta *Metadata, signer MultiAddress, sig MultiSignature,
+	o SignatureOptions) error {
+	extensions, err := meta.EncodeSignedExtensions(o)
+	if err != nil {
+		return err
+	}
+
+	return e.addSignature(signer, sig, o, extensions)
+}
+
+func (e *Extrinsic) addSignature(signer MultiAddress, sig MultiSignature, o SignatureOptions,
+	extensions SignedExtensionsData) error {
+	err := e.AddSignature(signer, sig, o)
+	if err != nil {
+		return err
+	}
 
+	// the extra data is set even if it is empty, so that the era, nonce and tip are not encoded instead
+	e.Signature.Extra = append([]byte{}, extensions.Extra...)
 	return nil
 }
 
@@ -17,6 +17,9 @@
 package types
 
 import (
+	"math"
+	"math/bits"
+
 	"github.com/Phala-Network/go-substrate-rpc-client/v3/scale"
 )
 
@@ -69,8 +72,89 @@ func (e ExtrinsicEra) Encode(encoder scale.Encoder) error {
 	return nil
 }
 
+// Birth returns the first block number of the era, given a block number current within the era. It is 0 for
+// immortal eras.
+func (e ExtrinsicEra) Birth(current uint64) uint64 {
+	if !e.IsMortalEra {
+		return 0
+	}
+	return e.AsMortalEra.Birth(current)
+}
+
+// Death returns the first block number after the era, from which on an extrinsic of the era is invalid, given a block
+// number current within the era. It is math.MaxUint64 for immortal eras.
+func (e ExtrinsicEra) Death(current uint64) uint64 {
+	if !e.IsMortalEra {
+		return math.MaxUint64
+	}
+	return e.AsMortalEra.Death(current)
+}
+
 // MortalEra for an extrinsic, indicating period and phase
 type MortalEra struct {
 	First  byte
 	Second byte
 }
+
+// NewMortalEra creates the era of an extrinsic that is valid for period blocks, starting at block current. Like in
+// Substrate, period is rounded up to a power of two between 4 and 65536, and the phase of current within the period
+// is quantized to a multiple of period / 4096 for periods above 4096, so the era may start a few blocks before
+// current.
+func NewMortalEra(current, period uint64) MortalEra {
+	switch {
+	case period < 4:
+		period = 4
+	case period > 1<<16:
+		period = 1 << 16
+	case period&(period-1) != 0:
+		period = 1 << bits.Len64(period)
+	}
+
+	quantizeFactor := mortalEraQuantizeFactor(period)
+	quantizedPhase := current % period / quantizeFactor * quantizeFactor
+
+	encoded := uint64(bits.TrailingZeros64(period)) - 1
+	if encoded < 1 {
+		encoded = 1
+	}
+	if encoded > 15 {
+		encoded = 15
+	}
+	encoded |= quantizedPhase / quantizeFactor << 4
+
+	return MortalEra{First: byte(encoded), Second: byte(encoded >> 8)}
+}
+
+// Period returns the number of blocks the era lasts
+func (m MortalEra) Period() uint64 {
+	encoded := uint64(m.First) | uint64(m.Second)<<8
+	return 2 << (encoded % (1 << 4))
+}
+
+// Phase returns the position of the first block of the era within its period
+func (m MortalEra) Phase() uint64 {
+	encoded := uint64(m.First) | uint64(m.Second)<<8
+	return encoded >> 4 * mortalEraQuantizeFactor(m.Period())
+}
+
+// Birth returns the first block number of the era, given a block number current within the era
+func (m MortalEra) Birth(current uint64) uint64 {
+	period, phase := m.Period(), m.Phase()
+	if current < phase {
+		current = phase
+	}
+	return (current-phase)/period*period + phase
+}
+
+// Death returns the first block number after the era, from which on an extrinsic of the era is invalid, given a block
+// number current within the era
+func (m MortalEra) Death(current uint64) uint64 {
+	return m.Birth(current) + m.Period()
+}
+
+func mortalEraQuantizeFactor(period uint64) uint64 {
+	if period>>12 < 1 {
+		return 1
+	}
+	return period >> 12
+}
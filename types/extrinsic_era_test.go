@@ -17,6 +17,7 @@
 package types_test
 
 import (
+	"math"
 	"testing"
 
 	. "github.com/Phala-Network/go-substrate-rpc-client/v3/types"
@@ -45,3 +46,48 @@ func TestExtrinsicEra_EncodeDecode(t *testing.T) {
 	assert.NoError(t, err)
 	assertRoundtrip(t, e)
 }
+
+func TestNewMortalEra(t *testing.T) {
+	// test vectors of the Era of Substrate
+	era := NewMortalEra(42, 64)
+	assert.Equal(t, MortalEra{165, 2}, era)
+	assert.Equal(t, uint64(64), era.Period())
+	assert.Equal(t, uint64(42), era.Phase())
+
+	era = NewMortalEra(1000000, 1000000)
+	assert.Equal(t, uint64(65536), era.Period())
+	assert.Equal(t, uint64(16960), era.Phase())
+
+	// periods are rounded up to a power of two between 4 and 65536
+	for period, expected := range map[uint64]uint64{0: 4, 1: 4, 5: 8, 64: 64, 100: 128, 65535: 65536, 1 << 20: 65536} {
+		assert.Equal(t, expected, NewMortalEra(1, period).Period(), "period %v", period)
+	}
+
+	// phases are quantized for periods above 4096
+	era = NewMortalEra(8195, 8192)
+	assert.Equal(t, uint64(2), era.Phase())
+
+	var decoded ExtrinsicEra
+	err := DecodeFromHexString("0x4e9c", &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(32768), decoded.AsMortalEra.Period())
+	assert.Equal(t, uint64(20000), decoded.AsMortalEra.Phase())
+}
+
+func TestExtrinsicEra_BirthDeath(t *testing.T) {
+	era := ExtrinsicEra{IsMortalEra: true, AsMortalEra: NewMortalEra(42, 64)}
+	assert.Equal(t, uint64(42), era.Birth(42))
+	assert.Equal(t, uint64(106), era.Death(42))
+	assert.Equal(t, uint64(42), era.Birth(105))
+	assert.Equal(t, uint64(106), era.Birth(106))
+	assert.Equal(t, uint64(170), era.Death(106))
+	assert.Equal(t, uint64(42), era.Birth(10))
+
+	era = ExtrinsicEra{IsMortalEra: true, AsMortalEra: NewMortalEra(1000000, 1000000)}
+	assert.Equal(t, uint64(1000000), era.Birth(1000000))
+	assert.Equal(t, uint64(1065536), era.Death(1000000))
+
+	immortal := ExtrinsicEra{IsImmortalEra: true}
+	assert.Equal(t, uint64(0), immortal.Birth(100))
+	assert.Equal(t, uint64(math.MaxUint64), immortal.Death(100))
+}
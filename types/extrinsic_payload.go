@@ -111,6 +111,16 @@ func (e ExtrinsicPayloadV4) Sign(signer signature.KeyringPair) (Signature, error
 	return NewSignature(sig), err
 }
 
+// SignWithSigner signs the extrinsic payload with signer
+func (e ExtrinsicPayloadV4) SignWithSigner(signer Signer) (MultiSignature, error) {
+	b, err := EncodeToBytes(e)
+	if err != nil {
+		return MultiSignature{}, err
+	}
+
+	return signer.Sign(b)
+}
+
 func (e ExtrinsicPayloadV4) Encode(encoder scale.Encoder) error {
 	err := encoder.Encode(e.Method)
 	if err != nil {
@@ -159,3 +169,52 @@ func (e ExtrinsicPayloadV4) Encode(encoder scale.Encoder) error {
 func (e *ExtrinsicPayloadV4) Decode(decoder scale.Decoder) error {
 	return fmt.Errorf("decoding of ExtrinsicPayloadV4 is not supported")
 }
+
+// ExtrinsicPayloadDynamic is the signing payload of an extrinsic with the signed extensions listed in the metadata,
+// see MetadataV14.EncodeSignedExtensions
+type ExtrinsicPayloadDynamic struct {
+	Method     BytesBare
+	Extensions SignedExtensionsData
+}
+
+// Sign signs the encoded payload, which includes the extra and additional signed data of the signed extensions, with
+// the sr25519 key pair of signer, hashing it with blake2b-256 first if it is longer than 256 bytes
+func (e ExtrinsicPayloadDynamic) Sign(signer signature.KeyringPair) (Signature, error) {
+	b, err := EncodeToBytes(e)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	sig, err := signature.Sign(b, signer.URI)
+	return NewSignature(sig), err
+}
+
+// SignWithSigner signs the extrinsic payload with signer
+func (e ExtrinsicPayloadDynamic) SignWithSigner(signer Signer) (MultiSignature, error) {
+	b, err := EncodeToBytes(e)
+	if err != nil {
+		return MultiSignature{}, err
+	}
+
+	return signer.Sign(b)
+}
+
+func (e ExtrinsicPayloadDynamic) Encode(encoder scale.Encoder) error {
+	err := encoder.Encode(e.Method)
+	if err != nil {
+		return err
+	}
+
+	err = encoder.Write(e.Extensions.Extra)
+	if err != nil {
+		return err
+	}
+
+	return encoder.Write(e.Extensions.AdditionalSigned)
+}
+
+// Decode does nothing and always returns an error. ExtrinsicPayloadDynamic is only used for encoding, not for
+// decoding
+func (e *ExtrinsicPayloadDynamic) Decode(decoder scale.Decoder) error {
+	return fmt.Errorf("decoding of ExtrinsicPayloadDynamic is not supported")
+}
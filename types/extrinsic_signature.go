@@ -16,6 +16,8 @@
 
 package types
 
+import "github.com/Phala-Network/go-substrate-rpc-client/v3/scale"
+
 type ExtrinsicSignatureV3 struct {
 	Signer    Address
 	Signature Signature
@@ -30,6 +32,39 @@ type ExtrinsicSignatureV4 struct {
 	Era       ExtrinsicEra // extra via system::CheckEra
 	Nonce     UCompact     // extra via system::CheckNonce (Compact<Index> where Index is u32))
 	Tip       UCompact     // extra via balances::TakeFees (Compact<Balance> where Balance is u128))
+
+	// Extra is the encoded extra data of all signed extensions of the runtime, as set by Extrinsic.SignWithMetadata.
+	// If not nil, even if empty, it is encoded instead of Era, Nonce and Tip. Decode leaves it nil, signatures with
+	// extra data are decoded with Metadata.DecodeExtrinsic.
+	Extra []byte `scale:"-"`
+}
+
+func (s ExtrinsicSignatureV4) Encode(encoder scale.Encoder) error {
+	err := encoder.Encode(s.Signer)
+	if err != nil {
+		return err
+	}
+
+	err = encoder.Encode(s.Signature)
+	if err != nil {
+		return err
+	}
+
+	if s.Extra != nil {
+		return encoder.Write(s.Extra)
+	}
+
+	err = encoder.Encode(s.Era)
+	if err != nil {
+		return err
+	}
+
+	err = encoder.Encode(s.Nonce)
+	if err != nil {
+		return err
+	}
+
+	return encoder.Encode(s.Tip)
 }
 
 type SignatureOptions struct {
@@ -40,4 +75,16 @@ type SignatureOptions struct {
 	GenesisHash        Hash         // additional via system::CheckGenesis
 	BlockHash          Hash         // additional via system::CheckEra
 	TransactionVersion U32          // additional via system::CheckTxVersion
+	// AssetID is the asset to pay the fees with via asset_tx_payment::ChargeAssetTxPayment, nil for the native token
+	AssetID interface{}
+	// MetadataHash is the hash of the metadata, checked via frame_metadata_hash_extension::CheckMetadataHash if set
+	MetadataHash OptionH256
+}
+
+// era returns the era of the options, which is immortal unless a mortal era is set
+func (o SignatureOptions) era() ExtrinsicEra {
+	if !o.Era.IsMortalEra {
+		return ExtrinsicEra{IsImmortalEra: true}
+	}
+	return o.Era
 }
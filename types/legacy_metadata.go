@@ -0,0 +1,406 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/scale"
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/xxhash"
+)
+
+// LegacyMetadata decodes events, calls and storage of metadata V9 to V13, which only name the types of values, with
+// the definitions of a LegacyTypeRegistry. The type names are resolved to a portable registry like the one of
+// metadata V14, so values are decoded into the same DynamicValue trees.
+type LegacyMetadata struct {
+	mu      sync.RWMutex
+	builder *legacyTypeBuilder
+	modules []legacyModule
+}
+
+type legacyModule struct {
+	name Text
+
+	hasCalls  bool
+	callIndex uint8
+	// callType is the variant type of the module's calls
+	callType Si1LookupTypeID
+
+	hasEvents  bool
+	eventIndex uint8
+	// eventType is the variant type of the module's events
+	eventType Si1LookupTypeID
+
+	hasStorage bool
+	prefix     Text
+	storage    []legacyStorageItem
+}
+
+type legacyStorageItem struct {
+	name    Text
+	hashers []StorageHasherV10
+	keys    []Si1LookupTypeID
+	value   Si1LookupTypeID
+}
+
+// legacyModuleMetadata holds the parts of the module metadata of all versions from V9 to V13 that are needed to
+// build a LegacyMetadata
+type legacyModuleMetadata struct {
+	name       Text
+	hasStorage bool
+	prefix     Text
+	storage    []legacyStorageItemMetadata
+	hasCalls   bool
+	calls      []FunctionMetadataV4
+	hasEvents  bool
+	events     []EventMetadataV4
+	// index is the index of the module in calls and events since V12, before it was the position of the module among
+	// the modules with calls, respectively events
+	hasIndex bool
+	index    uint8
+}
+
+type legacyStorageItemMetadata struct {
+	name    Text
+	hashers []StorageHasherV10
+	keys    []Type
+	value   Type
+	linked  bool
+}
+
+// NewLegacyMetadata resolves the types of the metadata, which must be of version 9 to 13, with the type definitions
+// of the registry that apply to the given spec version of the runtime
+func NewLegacyMetadata(registry *LegacyTypeRegistry, meta *Metadata, specVersion U32) (*LegacyMetadata, error) {
+	var modules []legacyModuleMetadata
+	switch meta.Version {
+	case 9:
+		for _, mod := range meta.AsMetadataV9.Modules {
+			modules = append(modules, legacyModuleV8(mod))
+		}
+	case 10:
+		for _, mod := range meta.AsMetadataV10.Modules {
+			modules = append(modules, legacyModuleV10(mod))
+		}
+	case 11:
+		for _, mod := range meta.AsMetadataV11.Modules {
+			modules = append(modules, legacyModuleV10(mod))
+		}
+	case 12:
+		for _, mod := range meta.AsMetadataV12.Modules {
+			m := legacyModuleV10(mod.ModuleMetadataV10)
+			m.hasIndex, m.index = true, mod.Index
+			modules = append(modules, m)
+		}
+	case 13:
+		for _, mod := range meta.AsMetadataV13.Modules {
+			modules = append(modules, legacyModuleV13(mod))
+		}
+	default:
+		return nil, fmt.Errorf("legacy types are not supported for metadata version %v", meta.Version)
+	}
+
+	m := &LegacyMetadata{builder: newLegacyTypeBuilder(registry.typesAt(uint32(specVersion)))}
+	m.build(modules)
+	return m, nil
+}
+
+// build resolves the types of the modules and builds the Call type of the runtime, which has a variant with the
+// calls of each module
+func (m *LegacyMetadata) build(modules []legacyModuleMetadata) {
+	b := m.builder
+	var callIndex, eventIndex uint8
+	var outerCall []Si1Variant
+
+	for _, mod := range modules {
+		lm := legacyModule{name: mod.name, hasCalls: mod.hasCalls, hasEvents: mod.hasEvents,
+			hasStorage: mod.hasStorage, prefix: mod.prefix}
+
+		if mod.hasCalls {
+			lm.callIndex = callIndex
+			if mod.hasIndex {
+				lm.callIndex = mod.index
+			}
+			callIndex++
+
+			variants := make([]Si1Variant, len(mod.calls))
+			for i, c := range mod.calls {
+				fields := make([]Si1Field, len(c.Args))
+				for j, a := range c.Args {
+					fields[j] = Si1Field{HasName: true, Name: a.Name, Type: b.typeID(string(a.Type)), HasTypeName: true,
+						TypeName: Text(a.Type)}
+				}
+				variants[i] = Si1Variant{Name: c.Name, Fields: fields, Index: U8(i), Docs: c.Documentation}
+			}
+			lm.callType = b.add(Si1Type{Path: Si1Path{mod.name, "Call"}, Def: Si1TypeDef{IsVariant: true,
+				Variant: Si1TypeDefVariant{Variants: variants}}})
+
+			outerCall = append(outerCall, Si1Variant{Name: mod.name, Index: U8(lm.callIndex),
+				Fields: []Si1Field{{Type: lm.callType}}})
+		}
+
+		if mod.hasEvents {
+			lm.eventIndex = eventIndex
+			if mod.hasIndex {
+				lm.eventIndex = mod.index
+			}
+			eventIndex++
+
+			variants := make([]Si1Variant, len(mod.events))
+			for i, e := range mod.events {
+				fields := make([]Si1Field, len(e.Args))
+				for j, a := range e.Args {
+					fields[j] = Si1Field{Type: b.typeID(string(a)), HasTypeName: true, TypeName: Text(a)}
+				}
+				variants[i] = Si1Variant{Name: e.Name, Fields: fields, Index: U8(i), Docs: e.Documentation}
+			}
+			lm.eventType = b.add(Si1Type{Path: Si1Path{mod.name, "Event"}, Def: Si1TypeDef{IsVariant: true,
+				Variant: Si1TypeDefVariant{Variants: variants}}})
+		}
+
+		for _, item := range mod.storage {
+			li := legacyStorageItem{name: item.name, hashers: item.hashers, value: b.typeID(string(item.value))}
+			for _, k := range item.keys {
+				li.keys = append(li.keys, b.typeID(string(k)))
+			}
+			if item.linked {
+				// values of linked maps are stored with the keys of the previous and next entries
+				key := b.typeID(fmt.Sprintf("Option<%v>", item.keys[0]))
+				linkage := b.add(Si1Type{Path: Si1Path{"Linkage"}, Def: Si1TypeDef{IsComposite: true,
+					Composite: Si1TypeDefComposite{Fields: []Si1Field{
+						{HasName: true, Name: "previous", Type: key},
+						{HasName: true, Name: "next", Type: key},
+					}}}})
+				li.value = b.add(Si1Type{Def: Si1TypeDef{IsTuple: true, Tuple: Si1TypeDefTuple{li.value, linkage}}})
+			}
+			lm.storage = append(lm.storage, li)
+		}
+
+		m.modules = append(m.modules, lm)
+	}
+
+	*b.lookup[b.outerCall.Int64()] = Si1Type{Path: Si1Path{"Call"}, Def: Si1TypeDef{IsVariant: true,
+		Variant: Si1TypeDefVariant{Variants: outerCall}}}
+}
+
+func legacyModuleV8(mod ModuleMetadataV8) legacyModuleMetadata {
+	m := legacyModuleMetadata{name: mod.Name, hasStorage: mod.HasStorage, prefix: mod.Storage.Prefix,
+		hasCalls: mod.HasCalls, calls: mod.Calls, hasEvents: mod.HasEvents, events: mod.Events}
+	for _, item := range mod.Storage.Items {
+		li := legacyStorageItemMetadata{name: item.Name}
+		switch {
+		case item.Type.IsMap:
+			li.hashers = []StorageHasherV10{legacyStorageHasher(item.Type.AsMap.Hasher)}
+			li.keys = []Type{item.Type.AsMap.Key}
+			li.value, li.linked = item.Type.AsMap.Value, item.Type.AsMap.Linked
+		case item.Type.IsDoubleMap:
+			li.hashers = []StorageHasherV10{legacyStorageHasher(item.Type.AsDoubleMap.Hasher),
+				legacyStorageHasher(item.Type.AsDoubleMap.Key2Hasher)}
+			li.keys = []Type{item.Type.AsDoubleMap.Key1, item.Type.AsDoubleMap.Key2}
+			li.value = item.Type.AsDoubleMap.Value
+		default:
+			li.value = item.Type.AsType
+		}
+		m.storage = append(m.storage, li)
+	}
+	return m
+}
+
+func legacyModuleV10(mod ModuleMetadataV10) legacyModuleMetadata {
+	m := legacyModuleMetadata{name: mod.Name, hasStorage: mod.HasStorage, prefix: mod.Storage.Prefix,
+		hasCalls: mod.HasCalls, calls: mod.Calls, hasEvents: mod.HasEvents, events: mod.Events}
+	for _, item := range mod.Storage.Items {
+		m.storage = append(m.storage, legacyStorageItemV13(item.Name, StorageFunctionTypeV13{
+			IsType:      item.Type.IsType,
+			AsType:      item.Type.AsType,
+			IsMap:       item.Type.IsMap,
+			AsMap:       item.Type.AsMap,
+			IsDoubleMap: item.Type.IsDoubleMap,
+			AsDoubleMap: item.Type.AsDoubleMap,
+		}))
+	}
+	return m
+}
+
+func legacyModuleV13(mod ModuleMetadataV13) legacyModuleMetadata {
+	m := legacyModuleMetadata{name: mod.Name, hasStorage: mod.HasStorage, prefix: mod.Storage.Prefix,
+		hasCalls: mod.HasCalls, calls: mod.Calls, hasEvents: mod.HasEvents, events: mod.Events, hasIndex: true,
+		index: mod.Index}
+	for _, item := range mod.Storage.Items {
+		m.storage = append(m.storage, legacyStorageItemV13(item.Name, item.Type))
+	}
+	return m
+}
+
+func legacyStorageItemV13(name Text, t StorageFunctionTypeV13) legacyStorageItemMetadata {
+	li := legacyStorageItemMetadata{name: name}
+	switch {
+	case t.IsMap:
+		li.hashers = []StorageHasherV10{t.AsMap.Hasher}
+		li.keys = []Type{t.AsMap.Key}
+		li.value, li.linked = t.AsMap.Value, t.AsMap.Linked
+	case t.IsDoubleMap:
+		li.hashers = []StorageHasherV10{t.AsDoubleMap.Hasher, t.AsDoubleMap.Key2Hasher}
+		li.keys = []Type{t.AsDoubleMap.Key1, t.AsDoubleMap.Key2}
+		li.value = t.AsDoubleMap.Value
+	case t.IsNMap:
+		li.hashers, li.keys, li.value = t.AsNMap.Hashers, t.AsNMap.Keys, t.AsNMap.Value
+	default:
+		li.value = t.AsType
+	}
+	return li
+}
+
+// legacyStorageHasher converts the hashers of metadata V9, which has no Blake2_128Concat and Identity hashers
+func legacyStorageHasher(h StorageHasher) StorageHasherV10 {
+	return StorageHasherV10{
+		IsBlake2_128:   h.IsBlake2_128,
+		IsBlake2_256:   h.IsBlake2_256,
+		IsTwox128:      h.IsTwox128,
+		IsTwox256:      h.IsTwox256,
+		IsTwox64Concat: h.IsTwox64Concat,
+	}
+}
+
+// TypeID returns the id of the registry type of the given type name, e.g. Vec<T::AccountId>. Names that can't be
+// resolved are added as historic types, which fail to decode.
+func (m *LegacyMetadata) TypeID(typeName string) Si1LookupTypeID {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.builder.typeID(typeName)
+}
+
+// TypeString returns a Rust-like name of the registry type typeID, see MetadataV14.TypeString
+func (m *LegacyMetadata) TypeString(typeID Si1LookupTypeID) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return newTypeStringer(m.builder.lookup, false).typeString(typeID)
+}
+
+// DecodeDynamicValue decodes bz as a value of the given type name. All bytes of bz must be consumed.
+func (m *LegacyMetadata) DecodeDynamicValue(bz []byte, typeName string) (DynamicValue, error) {
+	typeID := m.TypeID(typeName)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return decodeDynamicValueFromBytes(m.builder.lookup, bz, typeID)
+}
+
+// DecodeEventRecords decodes the event records e with the event types of the metadata
+func (m *LegacyMetadata) DecodeEventRecords(e EventRecordsRaw) ([]DynamicEventRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return e.decodeDynamicEventRecords(m.builder.lookup, func(id EventID) (Text, *Si1Variant, error) {
+		for _, mod := range m.modules {
+			if !mod.hasEvents || mod.eventIndex != id[0] {
+				continue
+			}
+			variants := m.builder.lookup[mod.eventType.Int64()].Def.Variant.Variants
+			if int(id[1]) >= len(variants) {
+				return "", nil, fmt.Errorf("event index %v for module %v out of range", id[1], mod.name)
+			}
+			return mod.name, &variants[id[1]], nil
+		}
+		return "", nil, fmt.Errorf("module index %v out of range", id[0])
+	})
+}
+
+// DecodeCall decodes the arguments of the Call c by resolving its call index to a call of a module
+func (m *LegacyMetadata) DecodeCall(c Call) (DynamicCall, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, mod := range m.modules {
+		if !mod.hasCalls || mod.callIndex != c.CallIndex.SectionIndex {
+			continue
+		}
+		variants := m.builder.lookup[mod.callType.Int64()].Def.Variant.Variants
+		if int(c.CallIndex.MethodIndex) >= len(variants) {
+			return DynamicCall{}, fmt.Errorf("call index %v not found within module %v", c.CallIndex.MethodIndex,
+				mod.name)
+		}
+		variant := variants[c.CallIndex.MethodIndex]
+
+		reader := bytes.NewReader(c.Args)
+		args, err := decodeDynamicFields(*scale.NewDecoder(reader), m.builder.lookup, variant.Fields)
+		if err != nil {
+			return DynamicCall{}, fmt.Errorf("unable to decode call %v.%v: %v", mod.name, variant.Name, err)
+		}
+		if reader.Len() > 0 {
+			return DynamicCall{}, fmt.Errorf("decoded call %v.%v but %v bytes remain", mod.name, variant.Name,
+				reader.Len())
+		}
+
+		call := DynamicCall{CallIndex: c.CallIndex, Pallet: mod.name, Name: variant.Name, Args: args}
+		for _, a := range args {
+			call.NestedCalls = appendNestedCalls(call.NestedCalls, a.Value, m.builder.outerCall)
+		}
+		return call, nil
+	}
+	return DynamicCall{}, fmt.Errorf("module index %v out of range", c.CallIndex.SectionIndex)
+}
+
+// DecodeStorageKey resolves key to its prefix and method and decodes the keys of maps that are stored in the clear,
+// see DecodeStorageKey
+func (m *LegacyMetadata) DecodeStorageKey(key StorageKey) (DecodedStorageKey, error) {
+	if len(key) < 32 {
+		return DecodedStorageKey{}, fmt.Errorf("storage key %#x is too short to contain a prefix and method", key)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, mod := range m.modules {
+		if !mod.hasStorage || !bytes.Equal(xxhash.New128([]byte(mod.prefix)).Sum(nil), key[:16]) {
+			continue
+		}
+
+		for _, item := range mod.storage {
+			if !bytes.Equal(xxhash.New128([]byte(item.name)).Sum(nil), key[16:32]) {
+				continue
+			}
+
+			decoded := DecodedStorageKey{Prefix: mod.prefix, Method: item.name}
+			if len(item.hashers) == 0 {
+				if len(key) > 32 {
+					return DecodedStorageKey{}, fmt.Errorf("storage key of plain value %v.%v has %v extra bytes",
+						mod.prefix, item.name, len(key)-32)
+				}
+				return decoded, nil
+			}
+
+			keys, err := decodeStorageHashedKeys(m.builder.lookup, key[32:], item.hashers, item.keys)
+			if err != nil {
+				return DecodedStorageKey{}, fmt.Errorf("unable to decode keys of %v.%v: %v", mod.prefix, item.name,
+					err)
+			}
+			decoded.Keys = keys
+			return decoded, nil
+		}
+		return DecodedStorageKey{}, fmt.Errorf("method of storage key %#x not found within module %v", key,
+			mod.prefix)
+	}
+	return DecodedStorageKey{}, fmt.Errorf("module of storage key %#x not found in metadata", key)
+}
+
+// DecodeStorageValue decodes bz as the value of the storage item method of the module with the given prefix
+func (m *LegacyMetadata) DecodeStorageValue(prefix, method string, bz []byte) (DynamicValue, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, mod := range m.modules {
+		if !mod.hasStorage || string(mod.prefix) != prefix {
+			continue
+		}
+		for _, item := range mod.storage {
+			if string(item.name) == method {
+				return decodeDynamicValueFromBytes(m.builder.lookup, bz, item.value)
+			}
+		}
+		return DynamicValue{}, fmt.Errorf("storage %v not found within module %v", method, prefix)
+	}
+	return DynamicValue{}, fmt.Errorf("module %v not found in metadata", prefix)
+}
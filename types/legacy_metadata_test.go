@@ -0,0 +1,178 @@
+package types_test
+
+import (
+	"testing"
+
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLegacyMetadata_DecodeEventRecords(t *testing.T) {
+	meta := newTestLegacyMetadata(t, NewLegacyTypeRegistry(), 1)
+
+	// module index of Balances in ExamplaryMetadataV13 is 6, of System is 0
+	e := EventRecordsRaw(MustHexDecodeString(
+		"0x08" + // (len 2) << 2
+
+			"0001000000" + // ApplyExtrinsic(1)
+			"0602" + // Balances_Transfer
+			"d43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d" + // From
+			"8eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f26a48" + // To
+			"391b0000000000000000000000000000" + // Value
+			"00" + // Topics
+
+			"0001000000" + // ApplyExtrinsic(1)
+			"0000" + // System_ExtrinsicSuccess
+			"1027000000000000" + // Weight
+			"01" + // Class Operational
+			"00" + // Pays Yes
+			"00", // Topics
+	))
+
+	events, err := meta.DecodeEventRecords(e)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+
+	transfer := events[0]
+	assert.Equal(t, EventID{6, 2}, transfer.ID)
+	assert.Equal(t, NewText("Balances"), transfer.Pallet)
+	assert.Equal(t, NewText("Transfer"), transfer.Name)
+	assert.Len(t, transfer.Fields, 3)
+	from, ok := transfer.Fields[0].Value.Bytes()
+	assert.True(t, ok)
+	assert.Equal(t, MustHexDecodeString("0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d"), from)
+	assert.Equal(t, "6969", transfer.Fields[2].Value.AsPrimitive.(U128).String())
+
+	success := events[1]
+	assert.Equal(t, NewText("ExtrinsicSuccess"), success.Name)
+	weight, ok := success.Fields[0].Value.Field("weight")
+	assert.True(t, ok)
+	assert.Equal(t, U64(10000), weight.AsPrimitive)
+	class, ok := success.Fields[0].Value.Field("class")
+	assert.True(t, ok)
+	assert.Equal(t, NewText("Operational"), class.AsVariant.Name)
+
+	_, err = meta.DecodeEventRecords(EventRecordsRaw(MustHexDecodeString("0x04" + "0001000000" + "0640")))
+	assert.Error(t, err)
+}
+
+func TestLegacyMetadata_DecodeEventRecords_V9(t *testing.T) {
+	meta, err := NewLegacyMetadata(NewLegacyTypeRegistry(), ExamplaryMetadataV9, 1)
+	assert.NoError(t, err)
+
+	// before metadata V12, modules without events don't count towards the event index, so Balances is at 3
+	e := EventRecordsRaw(MustHexDecodeString(
+		"0x04" + // (len 1) << 2
+			"0001000000" + // ApplyExtrinsic(1)
+			"0302" + // Balances_Transfer
+			"d43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d" + // From
+			"8eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f26a48" + // To
+			"391b0000000000000000000000000000" + // Value
+			"01000000000000000000000000000000" + // Fee
+			"00", // Topics
+	))
+
+	events, err := meta.DecodeEventRecords(e)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, NewText("Balances"), events[0].Pallet)
+	assert.Equal(t, NewText("Transfer"), events[0].Name)
+	assert.Len(t, events[0].Fields, 4)
+	assert.Equal(t, "1", events[0].Fields[3].Value.AsPrimitive.(U128).String())
+}
+
+func TestLegacyMetadata_DecodeCall(t *testing.T) {
+	meta := newTestLegacyMetadata(t, NewLegacyTypeRegistry(), 1)
+
+	transfer := MustHexDecodeString(
+		"0600" + // Balances.transfer
+			"00" + // MultiAddress Id
+			"8eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f26a48" +
+			"a10f", // Compact(1000)
+	)
+	remark := MustHexDecodeString("0001" + "0c010203") // System.remark
+
+	args := append(append([]byte{0x08}, transfer...), remark...)
+	call, err := meta.DecodeCall(Call{CallIndex: CallIndex{1, 0}, Args: args})
+	assert.NoError(t, err)
+	assert.Equal(t, NewText("Utility"), call.Pallet)
+	assert.Equal(t, NewText("batch"), call.Name)
+	assert.Len(t, call.NestedCalls, 2)
+
+	nested := call.NestedCalls[0]
+	assert.Equal(t, CallIndex{6, 0}, nested.CallIndex)
+	assert.Equal(t, NewText("Balances"), nested.Pallet)
+	assert.Equal(t, NewText("transfer"), nested.Name)
+	dest, ok := nested.Arg("dest")
+	assert.True(t, ok)
+	assert.Equal(t, NewText("Id"), dest.AsVariant.Name)
+	value, ok := nested.Arg("value")
+	assert.True(t, ok)
+	assert.Equal(t, NewUCompactFromUInt(1000), value.AsCompact)
+
+	assert.Equal(t, NewText("remark"), call.NestedCalls[1].Name)
+	r, ok := call.NestedCalls[1].Arg("_remark")
+	assert.True(t, ok)
+	bz, ok := r.Bytes()
+	assert.True(t, ok)
+	assert.Equal(t, []byte{1, 2, 3}, bz)
+
+	_, err = meta.DecodeCall(Call{CallIndex: CallIndex{0, 1}, Args: []byte{0x0c, 1, 2, 3, 4}})
+	assert.EqualError(t, err, "decoded call System.remark but 1 bytes remain")
+	_, err = meta.DecodeCall(Call{CallIndex: CallIndex{0, 99}})
+	assert.EqualError(t, err, "call index 99 not found within module System")
+	_, err = meta.DecodeCall(Call{CallIndex: CallIndex{99, 0}})
+	assert.EqualError(t, err, "module index 99 out of range")
+}
+
+func TestLegacyMetadata_Storage(t *testing.T) {
+	meta := newTestLegacyMetadata(t, NewLegacyTypeRegistry(), 1)
+
+	alice := MustHexDecodeString("0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d")
+	key, err := CreateStorageKey(ExamplaryMetadataV13, "System", "Account", alice)
+	assert.NoError(t, err)
+
+	decoded, err := meta.DecodeStorageKey(key)
+	assert.NoError(t, err)
+	assert.Equal(t, NewText("System"), decoded.Prefix)
+	assert.Equal(t, NewText("Account"), decoded.Method)
+	assert.Len(t, decoded.Keys, 1)
+	assert.True(t, decoded.Keys[0].HasValue)
+	bz, ok := decoded.Keys[0].Value.Bytes()
+	assert.True(t, ok)
+	assert.Equal(t, alice, bz)
+
+	_, err = meta.DecodeStorageKey(key[:31])
+	assert.Error(t, err)
+
+	info := MustHexDecodeString(
+		"05000000" + // nonce
+			"00000000" + // consumers
+			"01000000" + // providers
+			"00000000" + // sufficients
+			"e8030000000000000000000000000000" + // free
+			"00000000000000000000000000000000" + // reserved
+			"00000000000000000000000000000000" + // miscFrozen
+			"00000000000000000000000000000000", // feeFrozen
+	)
+	value, err := meta.DecodeStorageValue("System", "Account", info)
+	assert.NoError(t, err)
+	nonce, ok := value.Field("nonce")
+	assert.True(t, ok)
+	assert.Equal(t, U32(5), nonce.AsPrimitive)
+	data, ok := value.Field("data")
+	assert.True(t, ok)
+	free, ok := data.Field("free")
+	assert.True(t, ok)
+	assert.Equal(t, "1000", free.AsPrimitive.(U128).String())
+
+	_, err = meta.DecodeStorageValue("System", "Unknown", info)
+	assert.EqualError(t, err, "storage Unknown not found within module System")
+	_, err = meta.DecodeStorageValue("Unknown", "Account", info)
+	assert.EqualError(t, err, "module Unknown not found in metadata")
+}
+
+func TestNewLegacyMetadata_Unsupported(t *testing.T) {
+	_, err := NewLegacyMetadata(NewLegacyTypeRegistry(), DecodedMetadataV14Example(), 1)
+	assert.EqualError(t, err, "legacy types are not supported for metadata version 14")
+}
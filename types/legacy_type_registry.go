@@ -0,0 +1,506 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LegacyTypeRegistry holds the definitions of the types that metadata V9 to V13 refer to by name. Definitions use the
+// JSON format of polkadot-js: a type name maps to another type name, to an object of fields for structs, to
+// {"_enum": ...} for enums or to {"_set": ...} for sets.
+type LegacyTypeRegistry struct {
+	types     map[string]json.RawMessage
+	versioned []legacyVersionedTypes
+}
+
+// legacyVersionedTypes are type definitions that only apply to the spec versions within min and max, both inclusive
+type legacyVersionedTypes struct {
+	hasMin, hasMax bool
+	min, max       uint32
+	types          map[string]json.RawMessage
+}
+
+// NewLegacyTypeRegistry creates a registry with the definitions of common Substrate types, which can be extended and
+// overridden with LoadTypes and LoadTypesBundle
+func NewLegacyTypeRegistry() *LegacyTypeRegistry {
+	r := &LegacyTypeRegistry{types: map[string]json.RawMessage{}}
+	err := r.LoadTypes([]byte(legacyDefaultTypes))
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// LoadTypes loads type definitions for all spec versions, as used for the types option of the polkadot-js API, e.g.
+// {"Address": "AccountId", "Keys": "SessionKeys2"}. Definitions replace the ones loaded before.
+func (r *LegacyTypeRegistry) LoadTypes(bz []byte) error {
+	var types map[string]json.RawMessage
+	err := json.Unmarshal(bz, &types)
+	if err != nil {
+		return fmt.Errorf("unable to load type definitions: %v", err)
+	}
+	for name, def := range types {
+		r.types[name] = def
+	}
+	return nil
+}
+
+// LoadTypesBundle loads the type definitions of the given spec name from a polkadot-js types bundle, e.g.
+// {"spec": {"khala": {"types": [{"minmax": [0, 1000], "types": {...}}, {"minmax": [1001, null], "types": {...}}]}}}.
+// The definitions of a spec version range replace the ones loaded before if the spec version is within the range.
+func (r *LegacyTypeRegistry) LoadTypesBundle(bz []byte, specName string) error {
+	var bundle struct {
+		Spec map[string]struct {
+			Types []struct {
+				MinMax []*uint32                  `json:"minmax"`
+				Types  map[string]json.RawMessage `json:"types"`
+			} `json:"types"`
+		} `json:"spec"`
+	}
+	err := json.Unmarshal(bz, &bundle)
+	if err != nil {
+		return fmt.Errorf("unable to load types bundle: %v", err)
+	}
+
+	spec, ok := bundle.Spec[specName]
+	if !ok {
+		return fmt.Errorf("spec %v not found in types bundle", specName)
+	}
+	for _, t := range spec.Types {
+		v := legacyVersionedTypes{types: t.Types}
+		if len(t.MinMax) > 0 && t.MinMax[0] != nil {
+			v.hasMin, v.min = true, *t.MinMax[0]
+		}
+		if len(t.MinMax) > 1 && t.MinMax[1] != nil {
+			v.hasMax, v.max = true, *t.MinMax[1]
+		}
+		r.versioned = append(r.versioned, v)
+	}
+	return nil
+}
+
+// typesAt returns the type definitions that apply to the given spec version
+func (r *LegacyTypeRegistry) typesAt(specVersion uint32) map[string]json.RawMessage {
+	types := make(map[string]json.RawMessage, len(r.types))
+	for name, def := range r.types {
+		types[name] = def
+	}
+	for _, v := range r.versioned {
+		if (v.hasMin && specVersion < v.min) || (v.hasMax && specVersion > v.max) {
+			continue
+		}
+		for name, def := range v.types {
+			types[name] = def
+		}
+	}
+	return types
+}
+
+var legacyPrimitives = map[string]Si0TypeDefPrimitive{
+	"bool": IsBool, "char": IsChar, "str": IsStr,
+	"u8": IsU8, "u16": IsU16, "u32": IsU32, "u64": IsU64, "u128": IsU128, "u256": IsU256,
+	"i8": IsI8, "i16": IsI16, "i32": IsI32, "i64": IsI64, "i128": IsI128, "i256": IsI256,
+}
+
+// legacyTypeBuilder builds a portable registry from type names. Each distinct type name gets its own registry type,
+// named types get their name as path. Types that can't be resolved are added as historic types, so that decoding
+// fails only for values that use them.
+type legacyTypeBuilder struct {
+	defs      map[string]json.RawMessage
+	lookup    map[int64]*Si1Type
+	ids       map[string]Si1LookupTypeID
+	resolving map[string]bool
+	// outerCall is the id of the Call type, which is built from the calls of the metadata
+	outerCall Si1LookupTypeID
+}
+
+func newLegacyTypeBuilder(defs map[string]json.RawMessage) *legacyTypeBuilder {
+	b := &legacyTypeBuilder{
+		defs:      defs,
+		lookup:    map[int64]*Si1Type{},
+		ids:       map[string]Si1LookupTypeID{},
+		resolving: map[string]bool{},
+	}
+	b.outerCall = b.add(Si1Type{Path: Si1Path{"Call"}})
+	return b
+}
+
+func (b *legacyTypeBuilder) add(typ Si1Type) Si1LookupTypeID {
+	id := int64(len(b.lookup))
+	b.lookup[id] = &typ
+	return NewSi1LookupTypeIDFromUInt(uint64(id))
+}
+
+func (b *legacyTypeBuilder) unknown(name string) Si1LookupTypeID {
+	return b.add(Si1Type{Def: Si1TypeDef{IsHistoricMetaCompat: true, HistoricMetaCompat: Type(name)}})
+}
+
+// typeID returns the id of the registry type for the given type name, adding it and the types it refers to first
+func (b *legacyTypeBuilder) typeID(name string) Si1LookupTypeID {
+	s := sanitizeLegacyType(name)
+	if id, ok := b.ids[s]; ok {
+		return id
+	}
+	if b.resolving[s] {
+		// aliases that refer to themselves can't be resolved
+		return b.unknown(s)
+	}
+	b.resolving[s] = true
+	defer delete(b.resolving, s)
+
+	id := b.resolve(s)
+	b.ids[s] = id
+	return id
+}
+
+func (b *legacyTypeBuilder) resolve(s string) Si1LookupTypeID {
+	switch {
+	case s == "":
+		return b.unknown(s)
+	case strings.HasPrefix(s, "("):
+		if !strings.HasSuffix(s, ")") {
+			return b.unknown(s)
+		}
+		var tuple Si1TypeDefTuple
+		for _, elem := range splitLegacyTypes(s[1 : len(s)-1]) {
+			tuple = append(tuple, b.typeID(elem))
+		}
+		return b.add(Si1Type{Def: Si1TypeDef{IsTuple: true, Tuple: tuple}})
+	case strings.HasPrefix(s, "["):
+		i := strings.LastIndex(s, ";")
+		if !strings.HasSuffix(s, "]") || i < 0 {
+			return b.unknown(s)
+		}
+		n, err := strconv.ParseUint(s[i+1:len(s)-1], 10, 32)
+		if err != nil {
+			return b.unknown(s)
+		}
+		return b.add(Si1Type{Def: Si1TypeDef{IsArray: true, Array: Si1TypeDefArray{
+			Len:  U32(n),
+			Type: b.typeID(s[1:i]),
+		}}})
+	}
+
+	if def, ok := b.defs[s]; ok {
+		return b.definition(s, def)
+	}
+
+	base, params, ok := splitLegacyGeneric(s)
+	if !ok {
+		return b.unknown(s)
+	}
+	if params == nil {
+		return b.resolveName(s)
+	}
+	return b.resolveGeneric(s, base, params)
+}
+
+// resolveName resolves built-in types without type parameters
+func (b *legacyTypeBuilder) resolveName(s string) Si1LookupTypeID {
+	if p, ok := legacyPrimitives[s]; ok {
+		return b.add(Si1Type{Def: Si1TypeDef{IsPrimitive: true, Primitive: Si1TypeDefPrimitive{p}}})
+	}
+
+	switch s {
+	case "Call":
+		return b.outerCall
+	case "Bytes":
+		return b.add(Si1Type{Def: Si1TypeDef{IsSequence: true, Sequence: Si1TypeDefSequence{b.typeID("u8")}}})
+	case "Text", "String", "Str":
+		return b.typeID("str")
+	case "Null", "PhantomData":
+		return b.typeID("()")
+	case "H160", "H256", "H512", "AccountId32":
+		n := map[string]int{"H160": 20, "H256": 32, "H512": 64, "AccountId32": 32}[s]
+		return b.add(Si1Type{Path: Si1Path{Text(s)}, Def: Si1TypeDef{IsComposite: true, Composite: Si1TypeDefComposite{
+			Fields: []Si1Field{{Type: b.typeID(fmt.Sprintf("[u8;%v]", n))}},
+		}}})
+	default:
+		return b.unknown(s)
+	}
+}
+
+// resolveGeneric resolves built-in types with type parameters. Unknown generic types are resolved by their name
+// without type parameters, e.g. Exposure<AccountId, Balance> by the definition of Exposure.
+func (b *legacyTypeBuilder) resolveGeneric(s, base string, params []string) Si1LookupTypeID {
+	ids := make([]Si1LookupTypeID, len(params))
+	typeParams := func(names ...Text) []Si1TypeParameter {
+		res := make([]Si1TypeParameter, len(names))
+		for i, name := range names {
+			res[i] = Si1TypeParameter{Name: name, HasType: true, Type: ids[i]}
+		}
+		return res
+	}
+
+	switch {
+	case base == "Box" && len(params) == 1:
+		return b.typeID(params[0])
+	case base == "PhantomData":
+		return b.typeID("()")
+	}
+
+	_, isBuiltin := map[string]int{"Vec": 1, "Option": 1, "Compact": 1, "BTreeSet": 1, "Result": 2, "BTreeMap": 2,
+		"HashMap": 2}[base]
+	if !isBuiltin {
+		if _, ok := b.defs[base]; ok {
+			return b.typeID(base)
+		}
+		return b.unknown(s)
+	}
+
+	for i, p := range params {
+		ids[i] = b.typeID(p)
+	}
+
+	switch {
+	case base == "Vec" && len(params) == 1:
+		return b.add(Si1Type{Def: Si1TypeDef{IsSequence: true, Sequence: Si1TypeDefSequence{ids[0]}}})
+	case base == "BTreeSet" && len(params) == 1:
+		return b.add(Si1Type{Path: Si1Path{"BTreeSet"}, Params: typeParams("T"),
+			Def: Si1TypeDef{IsSequence: true, Sequence: Si1TypeDefSequence{ids[0]}}})
+	case base == "Compact" && len(params) == 1:
+		return b.add(Si1Type{Def: Si1TypeDef{IsCompact: true, Compact: Si1TypeDefCompact{ids[0]}}})
+	case base == "Option" && len(params) == 1:
+		return b.add(Si1Type{Path: Si1Path{"Option"}, Params: typeParams("T"), Def: Si1TypeDef{IsVariant: true,
+			Variant: Si1TypeDefVariant{Variants: []Si1Variant{
+				{Name: "None", Index: 0},
+				{Name: "Some", Index: 1, Fields: []Si1Field{{Type: ids[0], HasTypeName: true, TypeName: Text(params[0])}}},
+			}}}})
+	case base == "Result" && len(params) == 2:
+		return b.add(Si1Type{Path: Si1Path{"Result"}, Params: typeParams("T", "E"), Def: Si1TypeDef{IsVariant: true,
+			Variant: Si1TypeDefVariant{Variants: []Si1Variant{
+				{Name: "Ok", Index: 0, Fields: []Si1Field{{Type: ids[0], HasTypeName: true, TypeName: Text(params[0])}}},
+				{Name: "Err", Index: 1, Fields: []Si1Field{{Type: ids[1], HasTypeName: true, TypeName: Text(params[1])}}},
+			}}}})
+	case (base == "BTreeMap" || base == "HashMap") && len(params) == 2:
+		entry := b.add(Si1Type{Def: Si1TypeDef{IsTuple: true, Tuple: Si1TypeDefTuple{ids[0], ids[1]}}})
+		return b.add(Si1Type{Path: Si1Path{Text(base)}, Params: typeParams("K", "V"),
+			Def: Si1TypeDef{IsSequence: true, Sequence: Si1TypeDefSequence{entry}}})
+	default:
+		return b.unknown(s)
+	}
+}
+
+// definition adds the type defined by the JSON definition def. Anonymous types, e.g. structs within enums, have an
+// empty name.
+func (b *legacyTypeBuilder) definition(name string, def json.RawMessage) Si1LookupTypeID {
+	var alias string
+	if json.Unmarshal(def, &alias) == nil {
+		return b.typeID(alias)
+	}
+
+	members, err := decodeLegacyJSONObject(def)
+	if err != nil {
+		return b.unknown(name)
+	}
+
+	// the type is added before its fields are resolved, so that fields can refer to the type itself
+	id := b.add(Si1Type{})
+	if name != "" {
+		b.ids[name] = id
+	}
+	typ := Si1Type{}
+	if name != "" {
+		typ.Path = Si1Path{Text(name)}
+	}
+
+	switch {
+	case len(members) > 0 && members[0].key == "_enum":
+		typ.Def, err = b.enumDefinition(members[0].value)
+	case len(members) > 0 && members[0].key == "_set":
+		typ.Def, err = b.setDefinition(members[0].value)
+	default:
+		typ.Def = Si1TypeDef{IsComposite: true, Composite: Si1TypeDefComposite{Fields: b.structFields(members)}}
+	}
+	if err != nil {
+		typ = Si1Type{Def: Si1TypeDef{IsHistoricMetaCompat: true, HistoricMetaCompat: Type(name)}}
+	}
+	*b.lookup[id.Int64()] = typ
+	return id
+}
+
+// structFields returns the fields of a struct definition, skipping options such as _alias
+func (b *legacyTypeBuilder) structFields(members []legacyJSONMember) []Si1Field {
+	var fields []Si1Field
+	for _, m := range members {
+		if strings.HasPrefix(m.key, "_") {
+			continue
+		}
+		f := Si1Field{HasName: true, Name: Text(m.key)}
+		var typeName string
+		if json.Unmarshal(m.value, &typeName) == nil {
+			f.Type, f.HasTypeName, f.TypeName = b.typeID(typeName), true, Text(typeName)
+		} else {
+			f.Type = b.definition("", m.value)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// enumDefinition returns the variants of an enum definition, which is either a list of variant names, an object of
+// variant names to indices or an object of variant names to their fields
+func (b *legacyTypeBuilder) enumDefinition(def json.RawMessage) (Si1TypeDef, error) {
+	var variants []Si1Variant
+
+	var names []string
+	if json.Unmarshal(def, &names) == nil {
+		for i, name := range names {
+			variants = append(variants, Si1Variant{Name: Text(name), Index: U8(i)})
+		}
+		return Si1TypeDef{IsVariant: true, Variant: Si1TypeDefVariant{Variants: variants}}, nil
+	}
+
+	var indices map[string]uint8
+	if json.Unmarshal(def, &indices) == nil {
+		members, err := decodeLegacyJSONObject(def)
+		if err != nil {
+			return Si1TypeDef{}, err
+		}
+		for _, m := range members {
+			variants = append(variants, Si1Variant{Name: Text(m.key), Index: U8(indices[m.key])})
+		}
+		return Si1TypeDef{IsVariant: true, Variant: Si1TypeDefVariant{Variants: variants}}, nil
+	}
+
+	members, err := decodeLegacyJSONObject(def)
+	if err != nil {
+		return Si1TypeDef{}, err
+	}
+	for i, m := range members {
+		v := Si1Variant{Name: Text(m.key), Index: U8(i)}
+
+		var typeName *string
+		switch {
+		case json.Unmarshal(m.value, &typeName) == nil:
+			if typeName != nil && sanitizeLegacyType(*typeName) != "Null" {
+				v.Fields = []Si1Field{{Type: b.typeID(*typeName), HasTypeName: true, TypeName: Text(*typeName)}}
+			}
+		default:
+			fields, err := decodeLegacyJSONObject(m.value)
+			if err != nil {
+				return Si1TypeDef{}, err
+			}
+			v.Fields = b.structFields(fields)
+		}
+		variants = append(variants, v)
+	}
+	return Si1TypeDef{IsVariant: true, Variant: Si1TypeDefVariant{Variants: variants}}, nil
+}
+
+// setDefinition returns the primitive a set is encoded as, an unsigned integer of _bitLength bits (8 by default)
+func (b *legacyTypeBuilder) setDefinition(def json.RawMessage) (Si1TypeDef, error) {
+	var set struct {
+		BitLength int `json:"_bitLength"`
+	}
+	err := json.Unmarshal(def, &set)
+	if err != nil {
+		return Si1TypeDef{}, err
+	}
+
+	primitives := map[int]Si0TypeDefPrimitive{0: IsU8, 8: IsU8, 16: IsU16, 32: IsU32, 64: IsU64, 128: IsU128}
+	p, ok := primitives[set.BitLength]
+	if !ok {
+		return Si1TypeDef{}, fmt.Errorf("unsupported bit length %v of set", set.BitLength)
+	}
+	return Si1TypeDef{IsPrimitive: true, Primitive: Si1TypeDefPrimitive{p}}, nil
+}
+
+var (
+	legacyLookupSourceRegexp = regexp.MustCompile(`<\s*(?:T::)?Lookup\s+as\s+StaticLookup\s*>::Source`)
+	legacyCastRegexp         = regexp.MustCompile(`<\s*[\w:]+\s+as\s+[\w:]+(?:<[^<>]*>)?\s*>::`)
+	legacyPathRegexp         = regexp.MustCompile(`(?:[A-Za-z_]\w*::)+`)
+	legacySpaceRegexp        = regexp.MustCompile(`\s+`)
+)
+
+// sanitizeLegacyType turns a Rust type of the metadata into a type name of the registry, e.g.
+// Vec<<T as Trait>::AccountId> into Vec<AccountId> and <T::Lookup as StaticLookup>::Source into LookupSource
+func sanitizeLegacyType(s string) string {
+	s = strings.ReplaceAll(s, "&'static [u8]", "Bytes")
+	s = strings.ReplaceAll(s, "&[u8]", "Bytes")
+	s = strings.ReplaceAll(s, "&'static str", "Text")
+	s = legacyLookupSourceRegexp.ReplaceAllString(s, "LookupSource")
+	s = legacyCastRegexp.ReplaceAllString(s, "")
+	s = legacySpaceRegexp.ReplaceAllString(s, "")
+	return legacyPathRegexp.ReplaceAllString(s, "")
+}
+
+// splitLegacyGeneric splits a type name into its name and type parameters, which are nil if there are none
+func splitLegacyGeneric(s string) (string, []string, bool) {
+	i := strings.Index(s, "<")
+	if i < 0 {
+		return s, nil, true
+	}
+	if !strings.HasSuffix(s, ">") {
+		return "", nil, false
+	}
+	params := splitLegacyTypes(s[i+1 : len(s)-1])
+	if len(params) == 0 {
+		return "", nil, false
+	}
+	return s[:i], params, true
+}
+
+// splitLegacyTypes splits a comma separated list of type names, ignoring commas within type parameters and tuples
+func splitLegacyTypes(s string) []string {
+	var res []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '<', '(', '[':
+			depth++
+		case '>', ')', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				res = append(res, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if start < len(s) {
+		res = append(res, s[start:])
+	}
+	return res
+}
+
+type legacyJSONMember struct {
+	key   string
+	value json.RawMessage
+}
+
+// decodeLegacyJSONObject decodes the members of a JSON object in the order they are defined in, which is the order
+// of the fields of structs and of the variants of enums
+func decodeLegacyJSONObject(bz json.RawMessage) ([]legacyJSONMember, error) {
+	decoder := json.NewDecoder(bytes.NewReader(bz))
+	tok, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object, got %v", tok)
+	}
+
+	var members []legacyJSONMember
+	for decoder.More() {
+		tok, err = decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a JSON object key, got %v", tok)
+		}
+		var value json.RawMessage
+		err = decoder.Decode(&value)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, legacyJSONMember{key: key, value: value})
+	}
+	return members, nil
+}
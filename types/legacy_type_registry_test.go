@@ -0,0 +1,132 @@
+package types_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLegacyMetadata(t *testing.T, registry *LegacyTypeRegistry, specVersion U32) *LegacyMetadata {
+	meta, err := NewLegacyMetadata(registry, ExamplaryMetadataV13, specVersion)
+	assert.NoError(t, err)
+	return meta
+}
+
+func TestLegacyTypeRegistry_TypeNames(t *testing.T) {
+	meta := newTestLegacyMetadata(t, NewLegacyTypeRegistry(), 1)
+
+	for name, expected := range map[string]string{
+		"u32":                                  "u32",
+		"Vec<<T as Trait>::AccountId>":         "Vec<AccountId32>",
+		"<T::Lookup as StaticLookup>::Source":  "MultiAddress",
+		"Compact<T::Balance>":                  "Compact<u128>",
+		"<T as Trait<I>>::Balance":             "u128",
+		"Box<<T as Config>::Call>":             "Call",
+		"BTreeMap<u32, Vec<u8>>":               "BTreeMap<u32, Vec<u8>>",
+		"Option<(T::BlockNumber, u32)>":        "Option<(u32, u32)>",
+		"[u8; 4]":                              "[u8; 4]",
+		"&'static [u8]":                        "Vec<u8>",
+		"frame_support::weights::DispatchInfo": "DispatchInfo",
+		"Result<(), DispatchError>":            "Result<(), DispatchError>",
+		// unknown types are named as in the metadata
+		"Exposure<T::AccountId, BalanceOf<T>>": "Exposure<AccountId,BalanceOf<T>>",
+	} {
+		assert.Equal(t, expected, meta.TypeString(meta.TypeID(name)), name)
+	}
+
+	exposure := meta.TypeID("Exposure<T::AccountId, BalanceOf<T>>")
+	_, err := meta.DecodeDynamicValue([]byte{1, 2}, "Exposure<T::AccountId, BalanceOf<T>>")
+	assert.EqualError(t, err, fmt.Sprintf("unable to decode historic type Exposure<AccountId,BalanceOf<T>> of type %v",
+		exposure.Int64()))
+}
+
+func TestLegacyTypeRegistry_LoadTypes(t *testing.T) {
+	registry := NewLegacyTypeRegistry()
+	err := registry.LoadTypes([]byte(`{
+		"Weight": "u32",
+		"Status": {"_enum": ["Free", "Reserved"]},
+		"Sparse": {"_enum": {"First": 1, "Second": 4}},
+		"Payload": {
+			"_enum": {
+				"Empty": null,
+				"Amount": "Compact<Balance>",
+				"Named": {"who": "AccountId", "status": "Status"}
+			}
+		},
+		"Flags": {"_set": {"_bitLength": 16, "A": 1, "B": 2}},
+		"Entry": {"weight": "Weight", "nested": {"flags": "Flags"}, "next": "Option<Entry>"}
+	}`))
+	assert.NoError(t, err)
+	meta := newTestLegacyMetadata(t, registry, 1)
+
+	v, err := meta.DecodeDynamicValue([]byte{1}, "Status")
+	assert.NoError(t, err)
+	assert.Equal(t, Text("Reserved"), v.AsVariant.Name)
+
+	v, err = meta.DecodeDynamicValue([]byte{4}, "Sparse")
+	assert.NoError(t, err)
+	assert.Equal(t, Text("Second"), v.AsVariant.Name)
+
+	v, err = meta.DecodeDynamicValue([]byte{1, 0x28}, "Payload")
+	assert.NoError(t, err)
+	assert.Equal(t, Text("Amount"), v.AsVariant.Name)
+	assert.Equal(t, NewUCompactFromUInt(10), v.AsVariant.Fields[0].Value.AsCompact)
+
+	v, err = meta.DecodeDynamicValue(append(append([]byte{2}, make([]byte, 32)...), 1), "Payload")
+	assert.NoError(t, err)
+	status, ok := v.Field("status")
+	assert.True(t, ok)
+	assert.Equal(t, Text("Reserved"), status.AsVariant.Name)
+
+	_, err = meta.DecodeDynamicValue([]byte{3}, "Payload")
+	assert.Error(t, err)
+
+	// recursive types
+	v, err = meta.DecodeDynamicValue([]byte{1, 0, 0, 0, 3, 0, 1, 2, 0, 0, 0, 0, 0, 0}, "Entry")
+	assert.NoError(t, err)
+	assert.Equal(t, "Entry", meta.TypeString(v.Type))
+	weight, _ := v.Field("weight")
+	assert.Equal(t, U32(1), weight.AsPrimitive)
+	nested, _ := v.Field("nested")
+	flags, _ := nested.Field("flags")
+	assert.Equal(t, U16(3), flags.AsPrimitive)
+	next, _ := v.Field("next")
+	assert.Equal(t, Text("Some"), next.AsVariant.Name)
+	assert.Equal(t, v.Type, next.AsVariant.Fields[0].Value.Type)
+
+	err = registry.LoadTypes([]byte(`["Weight"]`))
+	assert.Error(t, err)
+}
+
+func TestLegacyTypeRegistry_LoadTypesBundle(t *testing.T) {
+	bundle := []byte(`{
+		"spec": {
+			"node": {
+				"types": [
+					{"minmax": [0, 99], "types": {"LookupSource": "AccountId", "Balance": "u64"}},
+					{"minmax": [100, null], "types": {"Balance": "u128"}},
+					{"minmax": [200, null], "types": {"Balance": "u32"}}
+				]
+			}
+		}
+	}`)
+
+	registry := NewLegacyTypeRegistry()
+	err := registry.LoadTypesBundle(bundle, "other")
+	assert.EqualError(t, err, "spec other not found in types bundle")
+	err = registry.LoadTypesBundle(bundle, "node")
+	assert.NoError(t, err)
+
+	for specVersion, expected := range map[U32][2]string{
+		0:   {"AccountId32", "u64"},
+		99:  {"AccountId32", "u64"},
+		100: {"MultiAddress", "u128"},
+		250: {"MultiAddress", "u32"},
+	} {
+		meta := newTestLegacyMetadata(t, registry, specVersion)
+		assert.Equal(t, expected[0], meta.TypeString(meta.TypeID("<T::Lookup as StaticLookup>::Source")))
+		assert.Equal(t, expected[1], meta.TypeString(meta.TypeID("T::Balance")))
+	}
+}
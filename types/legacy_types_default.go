@@ -0,0 +1,129 @@
+package types
+
+// legacyDefaultTypes are the definitions of common Substrate types in the format of polkadot-js, following the
+// definitions polkadot-js used for runtimes with metadata V12 and V13. Chains with other definitions override them
+// with LegacyTypeRegistry.LoadTypes or LegacyTypeRegistry.LoadTypesBundle.
+const legacyDefaultTypes = `{
+	"AccountId": "AccountId32",
+	"AccountIdOf": "AccountId",
+	"AccountIndex": "u32",
+	"Address": "MultiAddress",
+	"LookupSource": "MultiAddress",
+	"MultiAddress": {
+		"_enum": {
+			"Id": "AccountId",
+			"Index": "Compact<AccountIndex>",
+			"Raw": "Bytes",
+			"Address32": "H256",
+			"Address20": "H160"
+		}
+	},
+	"Balance": "u128",
+	"BalanceOf": "Balance",
+	"BlockNumber": "u32",
+	"Hash": "H256",
+	"Index": "u32",
+	"Moment": "u64",
+	"Weight": "u64",
+	"RefCount": "u32",
+	"Perbill": "u32",
+	"Permill": "u32",
+	"Percent": "u8",
+	"PerU16": "u16",
+	"Perquintill": "u64",
+	"Signature": "H512",
+	"Key": "Bytes",
+	"StorageKey": "Bytes",
+	"StorageData": "Bytes",
+	"KeyValue": "(StorageKey, StorageData)",
+	"Proposal": "Call",
+	"AuthorityId": "AccountId",
+	"AccountData": {
+		"free": "Balance",
+		"reserved": "Balance",
+		"miscFrozen": "Balance",
+		"feeFrozen": "Balance"
+	},
+	"AccountInfo": {
+		"nonce": "Index",
+		"consumers": "RefCount",
+		"providers": "RefCount",
+		"sufficients": "RefCount",
+		"data": "AccountData"
+	},
+	"Phase": {
+		"_enum": {
+			"ApplyExtrinsic": "u32",
+			"Finalization": "Null",
+			"Initialization": "Null"
+		}
+	},
+	"DispatchClass": {
+		"_enum": ["Normal", "Operational", "Mandatory"]
+	},
+	"Pays": {
+		"_enum": ["Yes", "No"]
+	},
+	"DispatchInfo": {
+		"weight": "Weight",
+		"class": "DispatchClass",
+		"paysFee": "Pays"
+	},
+	"DispatchErrorModule": {
+		"index": "u8",
+		"error": "u8"
+	},
+	"TokenError": {
+		"_enum": ["NoFunds", "WouldDie", "BelowMinimum", "CannotCreate", "UnknownAsset", "Frozen", "Unsupported"]
+	},
+	"ArithmeticError": {
+		"_enum": ["Underflow", "Overflow", "DivisionByZero"]
+	},
+	"DispatchError": {
+		"_enum": {
+			"Other": "Null",
+			"CannotLookup": "Null",
+			"BadOrigin": "Null",
+			"Module": "DispatchErrorModule",
+			"ConsumerRemaining": "Null",
+			"NoProviders": "Null",
+			"Token": "TokenError",
+			"Arithmetic": "ArithmeticError"
+		}
+	},
+	"DispatchResult": "Result<(), DispatchError>",
+	"ChangesTrieConfiguration": {
+		"digestInterval": "u32",
+		"digestLevels": "u32"
+	},
+	"ChangesTrieSignal": {
+		"_enum": {
+			"NewConfiguration": "Option<ChangesTrieConfiguration>"
+		}
+	},
+	"ConsensusEngineId": "[u8; 4]",
+	"Consensus": "(ConsensusEngineId, Bytes)",
+	"Seal": "(ConsensusEngineId, Bytes)",
+	"SealV0": "(u64, Signature)",
+	"PreRuntime": "(ConsensusEngineId, Bytes)",
+	"DigestItem": {
+		"_enum": {
+			"Other": "Bytes",
+			"AuthoritiesChange": "Vec<AuthorityId>",
+			"ChangesTrieRoot": "Hash",
+			"SealV0": "SealV0",
+			"Consensus": "Consensus",
+			"Seal": "Seal",
+			"PreRuntime": "PreRuntime",
+			"ChangesTrieSignal": "ChangesTrieSignal"
+		}
+	},
+	"Digest": {
+		"logs": "Vec<DigestItem>"
+	},
+	"DigestOf": "Digest",
+	"LastRuntimeUpgradeInfo": {
+		"specVersion": "Compact<u32>",
+		"specName": "Text"
+	}
+}`
@@ -45,6 +45,7 @@ type Metadata struct {
 	AsMetadataV12 MetadataV12
 	AsMetadataV13 MetadataV13
 	AsMetadataV14 MetadataV14
+	AsMetadataV15 MetadataV15
 }
 
 type StorageEntryMetadata interface {
@@ -108,6 +109,13 @@ func NewMetadataV14() *Metadata {
 	}
 }
 
+func NewMetadataV15() *Metadata {
+	return &Metadata{
+		Version:       15,
+		AsMetadataV15: MetadataV15{Pallets: make([]PalletMetadataV15, 0)},
+	}
+}
+
 func (m *Metadata) Decode(decoder scale.Decoder) error {
 	err := decoder.Decode(&m.MagicNumber)
 	if err != nil {
@@ -141,6 +149,8 @@ func (m *Metadata) Decode(decoder scale.Decoder) error {
 		err = decoder.Decode(&m.AsMetadataV13)
 	case 14:
 		err = decoder.Decode(&m.AsMetadataV14)
+	case 15:
+		err = decoder.Decode(&m.AsMetadataV15)
 	default:
 		return fmt.Errorf("unsupported metadata version %v", m.Version)
 	}
@@ -178,6 +188,8 @@ func (m Metadata) Encode(encoder scale.Encoder) error {
 		err = encoder.Encode(m.AsMetadataV13)
 	case 14:
 		err = encoder.Encode(m.AsMetadataV14)
+	case 15:
+		err = encoder.Encode(m.AsMetadataV15)
 	default:
 		return fmt.Errorf("unsupported metadata version %v", m.Version)
 	}
@@ -208,11 +220,39 @@ func (m *Metadata) FindConstantValue(module string, constantName string) ([]byte
 		return m.AsMetadataV13.FindConstantValue(txtModule, txtConstantName)
 	case 14:
 		return m.AsMetadataV14.FindConstantValue(txtModule, txtConstantName)
+	case 15:
+		return m.AsMetadataV15.FindConstantValue(txtModule, txtConstantName)
 	default:
 		return nil, fmt.Errorf("unsupported metadata version")
 	}
 }
 
+// DecodeConstant decodes the value of the constant name of pallet into target after checking the Go type of target
+// against the type of the constant. The metadata must be of version 14 or later.
+func (m *Metadata) DecodeConstant(pallet, name string, target interface{}) error {
+	switch m.Version {
+	case 14:
+		return m.AsMetadataV14.DecodeConstant(pallet, name, target)
+	case 15:
+		return m.AsMetadataV15.DecodeConstant(pallet, name, target)
+	default:
+		return fmt.Errorf("typed constants are not supported for metadata version %v", m.Version)
+	}
+}
+
+// DecodeDynamicConstant decodes the value of the constant name of pallet with the type of the constant. The metadata
+// must be of version 14 or later.
+func (m *Metadata) DecodeDynamicConstant(pallet, name string) (DynamicValue, error) {
+	switch m.Version {
+	case 14:
+		return m.AsMetadataV14.DecodeDynamicConstant(pallet, name)
+	case 15:
+		return m.AsMetadataV15.DecodeDynamicConstant(pallet, name)
+	default:
+		return DynamicValue{}, fmt.Errorf("typed constants are not supported for metadata version %v", m.Version)
+	}
+}
+
 func (m *Metadata) FindCallIndex(call string) (CallIndex, error) {
 	switch m.Version {
 	case 4:
@@ -233,6 +273,8 @@ func (m *Metadata) FindCallIndex(call string) (CallIndex, error) {
 		return m.AsMetadataV13.FindCallIndex(call)
 	case 14:
 		return m.AsMetadataV14.FindCallIndex(call)
+	case 15:
+		return m.AsMetadataV15.FindCallIndex(call)
 	default:
 		return CallIndex{}, fmt.Errorf("unsupported metadata version")
 	}
@@ -258,11 +300,34 @@ func (m *Metadata) FindEventNamesForEventID(eventID EventID) (Text, Text, error)
 		return m.AsMetadataV13.FindEventNamesForEventID(eventID)
 	case 14:
 		return m.AsMetadataV14.FindEventNamesForEventID(eventID)
+	case 15:
+		return m.AsMetadataV15.FindEventNamesForEventID(eventID)
 	default:
 		return "", "", fmt.Errorf("unsupported metadata version")
 	}
 }
 
+// FindModuleError resolves the module error d to the name and documentation of the error. Metadata versions before
+// 12 are not supported as their modules have no explicit index.
+func (m *Metadata) FindModuleError(d DispatchError) (ModuleError, error) {
+	if !d.HasModule {
+		return ModuleError{}, fmt.Errorf("dispatch error %v is not a module error", d.Error)
+	}
+
+	switch m.Version {
+	case 12:
+		return m.AsMetadataV12.FindModuleError(d.Module, d.ModuleError)
+	case 13:
+		return m.AsMetadataV13.FindModuleError(d.Module, d.ModuleError)
+	case 14:
+		return m.AsMetadataV14.FindModuleError(d.Module, d.ModuleError)
+	case 15:
+		return m.AsMetadataV15.FindModuleError(d.Module, d.ModuleError)
+	default:
+		return ModuleError{}, fmt.Errorf("unsupported metadata version")
+	}
+}
+
 func (m *Metadata) FindStorageEntryMetadata(module string, fn string) (StorageEntryMetadata, error) {
 	switch m.Version {
 	case 4:
@@ -283,6 +348,8 @@ func (m *Metadata) FindStorageEntryMetadata(module string, fn string) (StorageEn
 		return m.AsMetadataV13.FindStorageEntryMetadata(module, fn)
 	case 14:
 		return m.AsMetadataV14.FindStorageEntryMetadata(module, fn)
+	case 15:
+		return m.AsMetadataV15.FindStorageEntryMetadata(module, fn)
 	default:
 		return nil, fmt.Errorf("unsupported metadata version")
 	}
@@ -308,11 +375,66 @@ func (m *Metadata) ExistsModuleMetadata(module string) bool {
 		return m.AsMetadataV13.ExistsModuleMetadata(module)
 	case 14:
 		return m.AsMetadataV14.ExistsModuleMetadata(module)
+	case 15:
+		return m.AsMetadataV15.ExistsModuleMetadata(module)
 	default:
 		return false
 	}
 }
 
+// DecodeCall decodes the arguments of the Call c with the call types of the metadata, which must be of version 14 or
+// above
+func (m *Metadata) DecodeCall(c Call) (DynamicCall, error) {
+	switch m.Version {
+	case 14:
+		return m.AsMetadataV14.DecodeCall(c)
+	case 15:
+		return m.AsMetadataV15.DecodeCall(c)
+	default:
+		return DynamicCall{}, fmt.Errorf("dynamic decoding of calls is not supported for metadata version %v",
+			m.Version)
+	}
+}
+
+// NewCheckedCall creates a call from named arguments that are checked against the call types of the metadata, which
+// must be of version 14 or above
+func (m *Metadata) NewCheckedCall(call string, args ...CallArg) (Call, error) {
+	switch m.Version {
+	case 14:
+		return m.AsMetadataV14.NewCheckedCall(call, args...)
+	case 15:
+		return m.AsMetadataV15.NewCheckedCall(call, args...)
+	default:
+		return Call{}, fmt.Errorf("checked calls are not supported for metadata version %v", m.Version)
+	}
+}
+
+// EncodeSignedExtensions encodes the signed extensions listed in the extrinsic metadata with the given options
+func (m *Metadata) EncodeSignedExtensions(o SignatureOptions) (SignedExtensionsData, error) {
+	switch m.Version {
+	case 14:
+		return m.AsMetadataV14.EncodeSignedExtensions(o)
+	case 15:
+		return m.AsMetadataV15.EncodeSignedExtensions(o)
+	default:
+		return SignedExtensionsData{}, fmt.Errorf("signed extensions are not supported for metadata version %v",
+			m.Version)
+	}
+}
+
+// DecodeExtrinsic decodes an extrinsic whose signature carries the extra data of the signed extensions listed in the
+// extrinsic metadata, such as the extrinsics signed with Extrinsic.SignWithMetadata
+func (m *Metadata) DecodeExtrinsic(bz []byte) (Extrinsic, error) {
+	switch m.Version {
+	case 14:
+		return m.AsMetadataV14.DecodeExtrinsic(bz)
+	case 15:
+		return m.AsMetadataV15.DecodeExtrinsic(bz)
+	default:
+		return Extrinsic{}, fmt.Errorf("signed extensions are not supported for metadata version %v", m.Version)
+	}
+}
+
 // Default implementation of Hasher() for a Storage entry
 // It fails when called if entry is not a plain type.
 func DefaultPlainHasher(entry StorageEntryMetadata) (hash.Hash, error) {
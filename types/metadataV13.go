@@ -64,6 +64,21 @@ func (m *MetadataV13) FindEventNamesForEventID(eventID EventID) (Text, Text, err
 	return "", "", fmt.Errorf("module index %v out of range", eventID[0])
 }
 
+func (m *MetadataV13) FindModuleError(moduleIndex, errorIndex uint8) (ModuleError, error) {
+	for _, mod := range m.Modules {
+		if mod.Index != moduleIndex {
+			continue
+		}
+		if int(errorIndex) >= len(mod.Errors) {
+			return ModuleError{}, fmt.Errorf("error index %v for module %v out of range", errorIndex, mod.Name)
+		}
+		e := mod.Errors[errorIndex]
+		return ModuleError{Module: mod.Name, Name: e.Name, Docs: e.Documentation,
+			ModuleIndex: moduleIndex, ErrorIndex: errorIndex}, nil
+	}
+	return ModuleError{}, fmt.Errorf("module index %v out of range", moduleIndex)
+}
+
 func (m *MetadataV13) FindStorageEntryMetadata(module string, fn string) (StorageEntryMetadata, error) {
 	for _, mod := range m.Modules {
 		if !mod.HasStorage {
@@ -47,6 +47,28 @@ func (m *MetadataV14) Decode(decoder scale.Decoder) error {
 	return decoder.Decode(&m.Type)
 }
 
+// Encode implementation for MetadataV14
+// Note: EfficientLookup is derived from Lookup and
+// therefore not encoded.
+func (m MetadataV14) Encode(encoder scale.Encoder) error {
+	err := encoder.Encode(m.Lookup)
+	if err != nil {
+		return err
+	}
+
+	err = encoder.Encode(m.Pallets)
+	if err != nil {
+		return err
+	}
+
+	err = encoder.Encode(m.Extrinsic)
+	if err != nil {
+		return err
+	}
+
+	return encoder.Encode(m.Type)
+}
+
 // Build a map of type id to pointer to the PortableTypeV14 itself.
 func (lookup *PortableRegistryV14) toMap() map[int64]*Si1Type {
 	var efficientLookup = make(map[int64]*Si1Type)
@@ -87,26 +109,57 @@ func (m *MetadataV14) FindCallIndex(call string) (CallIndex, error) {
 }
 
 func (m *MetadataV14) FindEventNamesForEventID(eventID EventID) (Text, Text, error) {
-	for _, mod := range m.Pallets {
-		if !mod.HasEvents {
+	mod, variant, err := findEventVariant(m.lookup(), m.Pallets, eventID)
+	if err != nil {
+		return "", "", err
+	}
+	return mod.Name, variant.Name, nil
+}
+
+// findEventVariant returns the pallet emitting the event with the given id, together with the variant of the
+// pallet's event type that describes the event
+func findEventVariant(lookup map[int64]*Si1Type, pallets []PalletMetadataV14, eventID EventID) (*PalletMetadataV14,
+	*Si1Variant, error) {
+	for i, mod := range pallets {
+		if mod.Index != NewU8(eventID[0]) {
 			continue
 		}
-		if mod.Index != NewU8(eventID[0]) {
+		if !mod.HasEvents {
+			return nil, nil, fmt.Errorf("module %v has no events", mod.Name)
+		}
+
+		if typ, ok := lookup[mod.Events.Type.Int64()]; ok {
+			for j, vars := range typ.Def.Variant.Variants {
+				if uint8(vars.Index) == eventID[1] {
+					return &pallets[i], &typ.Def.Variant.Variants[j], nil
+				}
+			}
+		}
+		return nil, nil, fmt.Errorf("event index %v not found within module %v", eventID[1], mod.Name)
+	}
+	return nil, nil, fmt.Errorf("module index %v out of range", eventID[0])
+}
+
+func (m *MetadataV14) FindModuleError(moduleIndex, errorIndex uint8) (ModuleError, error) {
+	for _, mod := range m.Pallets {
+		if mod.Index != NewU8(moduleIndex) {
 			continue
 		}
-		eventType := mod.Events.Type.Int64()
+		if !mod.HasErrors {
+			return ModuleError{}, fmt.Errorf("module %v has no errors", mod.Name)
+		}
 
-		if typ, ok := m.EfficientLookup[eventType]; ok {
-			if len(typ.Def.Variant.Variants) > 0 {
-				for _, vars := range typ.Def.Variant.Variants {
-					if uint8(vars.Index) == eventID[1] {
-						return mod.Name, vars.Name, nil
-					}
+		if typ, ok := m.lookup()[mod.Errors.Type.Int64()]; ok {
+			for _, vars := range typ.Def.Variant.Variants {
+				if uint8(vars.Index) == errorIndex {
+					return ModuleError{Module: mod.Name, Name: vars.Name, Docs: vars.Docs,
+						ModuleIndex: moduleIndex, ErrorIndex: errorIndex}, nil
 				}
 			}
 		}
+		return ModuleError{}, fmt.Errorf("error index %v for module %v out of range", errorIndex, mod.Name)
 	}
-	return "", "", fmt.Errorf("module index %v out of range", eventID[0])
+	return ModuleError{}, fmt.Errorf("module index %v out of range", moduleIndex)
 }
 
 func (m *MetadataV14) FindStorageEntryMetadata(module string, fn string) (StorageEntryMetadata, error) {
@@ -31,6 +31,92 @@ func TestMetadataV14EncodeDecodeRoundtrip(t *testing.T) {
 	assert.EqualValues(t, metadata, decodedMetadata)
 }
 
+// Verify that the parts of the metadata encode to the exact bytes they have been decoded from
+func TestMetadataV14EncodeParts(t *testing.T) {
+	var metadata Metadata
+	err := DecodeFromHexString(MetadataV14Data, &metadata)
+	assert.NoError(t, err)
+	meta := metadata.AsMetadataV14
+
+	var parts []byte
+	encoded, err := EncodeToBytes(meta.Lookup)
+	assert.NoError(t, err)
+	parts = append(parts, encoded...)
+
+	encoded, err = EncodeToBytes(NewUCompactFromUInt(uint64(len(meta.Pallets))))
+	assert.NoError(t, err)
+	parts = append(parts, encoded...)
+	for _, pallet := range meta.Pallets {
+		encoded, err = EncodeToBytes(pallet)
+		assert.NoError(t, err)
+		parts = append(parts, encoded...)
+
+		var decoded PalletMetadataV14
+		err = DecodeFromBytes(encoded, &decoded)
+		assert.NoError(t, err)
+		assert.Equal(t, pallet, decoded)
+	}
+
+	encoded, err = EncodeToBytes(meta.Extrinsic)
+	assert.NoError(t, err)
+	parts = append(parts, encoded...)
+
+	encoded, err = EncodeToBytes(meta.Type)
+	assert.NoError(t, err)
+	parts = append(parts, encoded...)
+
+	// skip the magic number and the version
+	assert.Equal(t, MustHexDecodeString(MetadataV14Data)[5:], parts)
+
+	encoded, err = EncodeToBytes(meta)
+	assert.NoError(t, err)
+	assert.Equal(t, parts, encoded)
+}
+
+// Verify that every type of the portable registry roundtrips on its own
+func TestMetadataV14EncodeDecodePortableTypes(t *testing.T) {
+	var metadata Metadata
+	err := DecodeFromHexString(MetadataV14Data, &metadata)
+	assert.NoError(t, err)
+
+	for _, typ := range metadata.AsMetadataV14.Lookup.Types {
+		encoded, err := EncodeToBytes(typ)
+		assert.NoError(t, err)
+
+		var decoded PortableTypeV14
+		err = DecodeFromBytes(encoded, &decoded)
+		assert.NoError(t, err)
+		assert.Equal(t, typ, decoded)
+	}
+}
+
+// Verify that metadata created in code, without an EfficientLookup, encodes like decoded metadata
+func TestMetadataV14EncodeWithoutEfficientLookup(t *testing.T) {
+	var metadata Metadata
+	err := DecodeFromHexString(MetadataV14Data, &metadata)
+	assert.NoError(t, err)
+
+	created := Metadata{
+		MagicNumber: metadata.MagicNumber,
+		Version:     14,
+		AsMetadataV14: MetadataV14{
+			Lookup:    metadata.AsMetadataV14.Lookup,
+			Pallets:   metadata.AsMetadataV14.Pallets,
+			Extrinsic: metadata.AsMetadataV14.Extrinsic,
+			Type:      metadata.AsMetadataV14.Type,
+		},
+	}
+	encoded, err := EncodeToHexString(created)
+	assert.NoError(t, err)
+	assert.Equal(t, MetadataV14Data, encoded)
+
+	var decoded Metadata
+	err = DecodeFromHexString(encoded, &decoded)
+	assert.NoError(t, err)
+	assert.NotNil(t, decoded.AsMetadataV14.EfficientLookup)
+	assert.Equal(t, metadata, decoded)
+}
+
 /* Test Metadata interface functions for v14 */
 
 func TestMetadataV14_TestFindCallIndexWithUnknownFunction(t *testing.T) {
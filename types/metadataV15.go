@@ -0,0 +1,396 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/scale"
+)
+
+// nolint:lll
+// Based on https://github.com/paritytech/frame-metadata/blob/v16.0.0/frame-metadata/src/v15.rs
+type MetadataV15 struct {
+	Lookup     PortableRegistryV14
+	Pallets    []PalletMetadataV15
+	Extrinsic  ExtrinsicV15
+	Type       Si1LookupTypeID
+	APIs       []RuntimeAPIMetadataV15
+	OuterEnums OuterEnumsV15
+	Custom     CustomMetadataV15
+
+	// Custom field to help us lookup a type from the registry
+	// more efficiently. This field is built while decoding and
+	// it is not to be encoded.
+	EfficientLookup map[int64]*Si1Type `scale:"-"`
+}
+
+// Decode implementation for MetadataV15
+// Note: We opt for a custom impl build `EfficientLookup`
+// on the fly.
+func (m *MetadataV15) Decode(decoder scale.Decoder) error {
+	err := decoder.Decode(&m.Lookup)
+	if err != nil {
+		return err
+	}
+
+	m.EfficientLookup = m.Lookup.toMap()
+
+	err = decoder.Decode(&m.Pallets)
+	if err != nil {
+		return err
+	}
+
+	err = decoder.Decode(&m.Extrinsic)
+	if err != nil {
+		return err
+	}
+
+	err = decoder.Decode(&m.Type)
+	if err != nil {
+		return err
+	}
+
+	err = decoder.Decode(&m.APIs)
+	if err != nil {
+		return err
+	}
+
+	err = decoder.Decode(&m.OuterEnums)
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(&m.Custom)
+}
+
+// Encode implementation for MetadataV15
+// Note: EfficientLookup is derived from Lookup and
+// therefore not encoded.
+func (m MetadataV15) Encode(encoder scale.Encoder) error {
+	err := encoder.Encode(m.Lookup)
+	if err != nil {
+		return err
+	}
+
+	err = encoder.Encode(m.Pallets)
+	if err != nil {
+		return err
+	}
+
+	err = encoder.Encode(m.Extrinsic)
+	if err != nil {
+		return err
+	}
+
+	err = encoder.Encode(m.Type)
+	if err != nil {
+		return err
+	}
+
+	err = encoder.Encode(m.APIs)
+	if err != nil {
+		return err
+	}
+
+	err = encoder.Encode(m.OuterEnums)
+	if err != nil {
+		return err
+	}
+
+	return encoder.Encode(m.Custom)
+}
+
+// lookup returns EfficientLookup, which is built while decoding. Metadata that has been created in code gets a new
+// lookup on each call, as storing it would race with concurrent readers of the metadata.
+func (m *MetadataV15) lookup() map[int64]*Si1Type {
+	if m.EfficientLookup == nil {
+		return m.Lookup.toMap()
+	}
+	return m.EfficientLookup
+}
+
+// palletsV14 returns the pallets in the layout of V14, which lacks only the docs of V15, so that the functions that
+// resolve calls, events and storage items with the portable registry are shared by both versions
+func (m *MetadataV15) palletsV14() []PalletMetadataV14 {
+	pallets := make([]PalletMetadataV14, len(m.Pallets))
+	for i, p := range m.Pallets {
+		pallets[i] = PalletMetadataV14{
+			Name:       p.Name,
+			HasStorage: p.HasStorage,
+			Storage:    p.Storage,
+			HasCalls:   p.HasCalls,
+			Calls:      p.Calls,
+			HasEvents:  p.HasEvents,
+			Events:     p.Events,
+			Constants:  p.Constants,
+			HasErrors:  p.HasErrors,
+			Errors:     p.Errors,
+			Index:      p.Index,
+		}
+	}
+	return pallets
+}
+
+/* Metadata interface functions implementation */
+
+func (m *MetadataV15) FindCallIndex(call string) (CallIndex, error) {
+	s := strings.Split(call, ".")
+	for _, mod := range m.Pallets {
+		if !mod.HasCalls {
+			continue
+		}
+		if string(mod.Name) != s[0] {
+			continue
+		}
+		callType := mod.Calls.Type.Int64()
+
+		if typ, ok := m.lookup()[callType]; ok {
+			for _, vars := range typ.Def.Variant.Variants {
+				if len(s) > 1 && string(vars.Name) == s[1] {
+					return CallIndex{uint8(mod.Index), uint8(vars.Index)}, nil
+				}
+			}
+		}
+	}
+	return CallIndex{}, fmt.Errorf("module %v not found in metadata for call %v", s[0], call)
+}
+
+func (m *MetadataV15) FindEventNamesForEventID(eventID EventID) (Text, Text, error) {
+	mod, variant, err := findEventVariant(m.lookup(), m.palletsV14(), eventID)
+	if err != nil {
+		return "", "", err
+	}
+	return mod.Name, variant.Name, nil
+}
+
+func (m *MetadataV15) FindModuleError(moduleIndex, errorIndex uint8) (ModuleError, error) {
+	for _, mod := range m.Pallets {
+		if mod.Index != NewU8(moduleIndex) {
+			continue
+		}
+		if !mod.HasErrors {
+			return ModuleError{}, fmt.Errorf("module %v has no errors", mod.Name)
+		}
+
+		if typ, ok := m.lookup()[mod.Errors.Type.Int64()]; ok {
+			for _, vars := range typ.Def.Variant.Variants {
+				if uint8(vars.Index) == errorIndex {
+					return ModuleError{Module: mod.Name, Name: vars.Name, Docs: vars.Docs,
+						ModuleIndex: moduleIndex, ErrorIndex: errorIndex}, nil
+				}
+			}
+		}
+		return ModuleError{}, fmt.Errorf("error index %v for module %v out of range", errorIndex, mod.Name)
+	}
+	return ModuleError{}, fmt.Errorf("module index %v out of range", moduleIndex)
+}
+
+func (m *MetadataV15) FindStorageEntryMetadata(module string, fn string) (StorageEntryMetadata, error) {
+	for _, mod := range m.Pallets {
+		if !mod.HasStorage {
+			continue
+		}
+		if string(mod.Storage.Prefix) != module {
+			continue
+		}
+		for _, s := range mod.Storage.Items {
+			if string(s.Name) == fn {
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("storage %v not found within module %v", fn, module)
+	}
+	return nil, fmt.Errorf("module %v not found in metadata", module)
+}
+
+func (m *MetadataV15) FindConstantValue(module Text, constant Text) ([]byte, error) {
+	for _, mod := range m.Pallets {
+		if mod.Name == module {
+			value, err := mod.FindConstantValue(constant)
+			if err == nil {
+				return value, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("could not find constant %s.%s", module, constant)
+}
+
+func (m *MetadataV15) ExistsModuleMetadata(module string) bool {
+	for _, mod := range m.Pallets {
+		if string(mod.Name) == module {
+			return true
+		}
+	}
+	return false
+}
+
+// FindRuntimeAPIMethod returns the method of the runtime API with the given names, e.g. Core and version
+func (m *MetadataV15) FindRuntimeAPIMethod(api, method string) (RuntimeAPIMethodMetadataV15, error) {
+	for _, a := range m.APIs {
+		if string(a.Name) != api {
+			continue
+		}
+		for _, me := range a.Methods {
+			if string(me.Name) == method {
+				return me, nil
+			}
+		}
+		return RuntimeAPIMethodMetadataV15{}, fmt.Errorf("method %v not found within runtime api %v", method, api)
+	}
+	return RuntimeAPIMethodMetadataV15{}, fmt.Errorf("runtime api %v not found in metadata", api)
+}
+
+/* Supporting types */
+
+// ExtrinsicV15 describes the extrinsic format. Contrary to V14, it holds the types of the extrinsic's parts instead
+// of the type of the whole extrinsic.
+type ExtrinsicV15 struct {
+	Version          U8
+	AddressType      Si1LookupTypeID
+	CallType         Si1LookupTypeID
+	SignatureType    Si1LookupTypeID
+	ExtraType        Si1LookupTypeID
+	SignedExtensions []SignedExtensionMetadataV14
+}
+
+// PalletMetadataV15 is PalletMetadataV14 with the documentation of the pallet
+type PalletMetadataV15 struct {
+	Name       Text
+	HasStorage bool
+	Storage    StorageMetadataV14
+	HasCalls   bool
+	Calls      FunctionMetadataV14
+	HasEvents  bool
+	Events     EventMetadataV14
+	Constants  []ConstantMetadataV14
+	HasErrors  bool
+	Errors     ErrorMetadataV14
+	Index      U8
+	Docs       []Text
+}
+
+func (m *PalletMetadataV15) Decode(decoder scale.Decoder) error {
+	err := decoder.Decode(&m.Name)
+	if err != nil {
+		return err
+	}
+
+	err = decoder.DecodeOption(&m.HasStorage, &m.Storage)
+	if err != nil {
+		return err
+	}
+
+	err = decoder.DecodeOption(&m.HasCalls, &m.Calls)
+	if err != nil {
+		return err
+	}
+
+	err = decoder.DecodeOption(&m.HasEvents, &m.Events)
+	if err != nil {
+		return err
+	}
+
+	err = decoder.Decode(&m.Constants)
+	if err != nil {
+		return err
+	}
+
+	err = decoder.DecodeOption(&m.HasErrors, &m.Errors)
+	if err != nil {
+		return err
+	}
+
+	err = decoder.Decode(&m.Index)
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(&m.Docs)
+}
+
+func (m PalletMetadataV15) Encode(encoder scale.Encoder) error {
+	err := encoder.Encode(m.Name)
+	if err != nil {
+		return err
+	}
+
+	err = encoder.EncodeOption(m.HasStorage, m.Storage)
+	if err != nil {
+		return err
+	}
+
+	err = encoder.EncodeOption(m.HasCalls, m.Calls)
+	if err != nil {
+		return err
+	}
+
+	err = encoder.EncodeOption(m.HasEvents, m.Events)
+	if err != nil {
+		return err
+	}
+
+	err = encoder.Encode(m.Constants)
+	if err != nil {
+		return err
+	}
+
+	err = encoder.EncodeOption(m.HasErrors, m.Errors)
+	if err != nil {
+		return err
+	}
+
+	err = encoder.Encode(m.Index)
+	if err != nil {
+		return err
+	}
+
+	return encoder.Encode(m.Docs)
+}
+
+func (m *PalletMetadataV15) FindConstantValue(constant Text) ([]byte, error) {
+	for _, cons := range m.Constants {
+		if cons.Name == constant {
+			return cons.Value, nil
+		}
+	}
+	return nil, fmt.Errorf("could not find constant %s", constant)
+}
+
+// RuntimeAPIMetadataV15 describes a runtime API, a trait of the runtime that can be called through state_call
+type RuntimeAPIMetadataV15 struct {
+	Name    Text
+	Methods []RuntimeAPIMethodMetadataV15
+	Docs    []Text
+}
+
+// RuntimeAPIMethodMetadataV15 describes a method of a runtime API. It is called as <api>_<method>, e.g.
+// Metadata_metadata_at_version, with the encoded inputs.
+type RuntimeAPIMethodMetadataV15 struct {
+	Name   Text
+	Inputs []RuntimeAPIMethodParamMetadataV15
+	Output Si1LookupTypeID
+	Docs   []Text
+}
+
+type RuntimeAPIMethodParamMetadataV15 struct {
+	Name Text
+	Type Si1LookupTypeID
+}
+
+// OuterEnumsV15 holds the enums of the runtime that wrap the calls, events and errors of all pallets
+type OuterEnumsV15 struct {
+	CallType  Si1LookupTypeID
+	EventType Si1LookupTypeID
+	ErrorType Si1LookupTypeID
+}
+
+// CustomMetadataV15 holds custom values the runtime exposes, encoded as a map sorted by name
+type CustomMetadataV15 struct {
+	Map []CustomValueMetadataV15
+}
+
+type CustomValueMetadataV15 struct {
+	Name  Text
+	Type  Si1LookupTypeID
+	Value Bytes
+}
@@ -0,0 +1,195 @@
+package types_test
+
+import (
+	"testing"
+
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// A minimal V15 metadata, with one pallet, one runtime API and one custom value
+var metadataV15Minimal = "0x6d657461" + "0f" +
+	// lookup: u32 with id 0
+	"04" + "00" + "00" + "00" + "0505" + "00" +
+	// pallets
+	"04" + "1054657374" + "00" + "00" + "00" +
+	"04" + "18416e73776572" + "00" + "102a000000" + "00" + // constants
+	"00" + "07" + "042c546573742070616c6c6574" +
+	// extrinsic
+	"04" + "00" + "00" + "00" + "00" + "04" + "28436865636b4e6f6e6365" + "00" + "00" +
+	// type
+	"00" +
+	// runtime APIs
+	"04" + "204d65746164617461" +
+	"04" + "4c6d657461646174615f61745f76657273696f6e" + "04" + "1c76657273696f6e" + "00" + "00" + "00" +
+	"00" +
+	// outer enums
+	"00" + "00" + "00" +
+	// custom
+	"04" + "0c666f6f" + "00" + "042a"
+
+func TestMetadataV15_Decode(t *testing.T) {
+	var metadata Metadata
+	err := DecodeFromHexString(metadataV15Minimal, &metadata)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 15, metadata.Version)
+
+	meta := metadata.AsMetadataV15
+	assert.Len(t, meta.Lookup.Types, 1)
+	assert.NotNil(t, meta.EfficientLookup)
+	assert.Equal(t, []PalletMetadataV15{{
+		Name:      "Test",
+		Constants: []ConstantMetadataV14{{Name: "Answer", Value: Bytes{42, 0, 0, 0}}},
+		Index:     7,
+		Docs:      []Text{"Test pallet"},
+	}}, meta.Pallets)
+	assert.Equal(t, ExtrinsicV15{
+		Version:          4,
+		SignedExtensions: []SignedExtensionMetadataV14{{Identifier: "CheckNonce"}},
+	}, meta.Extrinsic)
+	assert.Equal(t, []RuntimeAPIMetadataV15{{
+		Name: "Metadata",
+		Methods: []RuntimeAPIMethodMetadataV15{{
+			Name:   "metadata_at_version",
+			Inputs: []RuntimeAPIMethodParamMetadataV15{{Name: "version"}},
+		}},
+	}}, meta.APIs)
+	assert.Equal(t, OuterEnumsV15{}, meta.OuterEnums)
+	assert.Equal(t, CustomMetadataV15{Map: []CustomValueMetadataV15{{Name: "foo", Value: Bytes{42}}}}, meta.Custom)
+
+	encoded, err := EncodeToHexString(metadata)
+	assert.NoError(t, err)
+	assert.Equal(t, metadataV15Minimal, encoded)
+}
+
+func TestMetadataV15_FindFunctions(t *testing.T) {
+	metadata := metadataV15FromV14(t)
+
+	callIndex, err := metadata.FindCallIndex("Balances.transfer")
+	assert.NoError(t, err)
+	assert.Equal(t, CallIndex{SectionIndex: 5, MethodIndex: 0}, callIndex)
+
+	_, err = metadata.FindCallIndex("Balances.unknown")
+	assert.Error(t, err)
+
+	module, event, err := metadata.FindEventNamesForEventID(EventID{5, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, NewText("Balances"), module)
+	assert.Equal(t, NewText("Transfer"), event)
+
+	_, _, err = metadata.FindEventNamesForEventID(EventID{200, 0})
+	assert.EqualError(t, err, "module index 200 out of range")
+
+	entry, err := metadata.FindStorageEntryMetadata("System", "Account")
+	assert.NoError(t, err)
+	assert.True(t, entry.IsMap())
+
+	_, err = metadata.FindStorageEntryMetadata("System", "Unknown")
+	assert.EqualError(t, err, "storage Unknown not found within module System")
+
+	value, err := metadata.FindConstantValue("System", "SS58Prefix")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0}, value)
+
+	assert.True(t, metadata.ExistsModuleMetadata("Balances"))
+	assert.False(t, metadata.ExistsModuleMetadata("Unknown"))
+
+	moduleErr, err := metadata.FindModuleError(DispatchError{Error: 3, HasModule: true, Module: 5, ModuleError: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, NewText("InsufficientBalance"), moduleErr.Name)
+
+	// storage keys are created like for V14
+	key, err := CreateStorageKey(metadata, "System", "Account", MustHexDecodeString(AlicePubKey))
+	assert.NoError(t, err)
+	assert.Equal(t, "0x26aa394eea5630e07c48ae0c9558cef7b99d880ec681799c0cf30e8886371da9de1e86a9a8c739864cf3cc5ec2bea59fd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d", key.Hex()) //nolint:lll
+}
+
+func TestMetadataV15_FindRuntimeAPIMethod(t *testing.T) {
+	metadata := metadataV15FromV14(t)
+
+	method, err := metadata.AsMetadataV15.FindRuntimeAPIMethod("Metadata", "metadata_at_version")
+	assert.NoError(t, err)
+	assert.Equal(t, NewText("version"), method.Inputs[0].Name)
+
+	_, err = metadata.AsMetadataV15.FindRuntimeAPIMethod("Metadata", "unknown")
+	assert.EqualError(t, err, "method unknown not found within runtime api Metadata")
+
+	_, err = metadata.AsMetadataV15.FindRuntimeAPIMethod("Unknown", "version")
+	assert.EqualError(t, err, "runtime api Unknown not found in metadata")
+}
+
+func TestMetadataV15EncodeDecodeRoundtrip(t *testing.T) {
+	metadata := metadataV15FromV14(t)
+
+	encoded, err := EncodeToHexString(metadata)
+	assert.NoError(t, err)
+
+	var decoded Metadata
+	err = DecodeFromHexString(encoded, &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, *metadata, decoded)
+}
+
+// metadataV15FromV14 converts the V14 example metadata to V15, as the runtime of the example would have returned it
+func metadataV15FromV14(t *testing.T) *Metadata {
+	var v14 Metadata
+	err := DecodeFromHexString(MetadataV14Data, &v14)
+	assert.NoError(t, err)
+	meta := v14.AsMetadataV14
+
+	pallets := make([]PalletMetadataV15, len(meta.Pallets))
+	for i, p := range meta.Pallets {
+		pallets[i] = PalletMetadataV15{
+			Name:       p.Name,
+			HasStorage: p.HasStorage,
+			Storage:    p.Storage,
+			HasCalls:   p.HasCalls,
+			Calls:      p.Calls,
+			HasEvents:  p.HasEvents,
+			Events:     p.Events,
+			Constants:  p.Constants,
+			HasErrors:  p.HasErrors,
+			Errors:     p.Errors,
+			Index:      p.Index,
+			Docs:       []Text{"Pallet " + p.Name},
+		}
+	}
+
+	u32 := NewSi1LookupTypeIDFromUInt(4)
+	return &Metadata{
+		MagicNumber: MagicNumber,
+		Version:     15,
+		AsMetadataV15: MetadataV15{
+			Lookup:  meta.Lookup,
+			Pallets: pallets,
+			Extrinsic: ExtrinsicV15{
+				Version:          4,
+				AddressType:      NewSi1LookupTypeIDFromUInt(147),
+				CallType:         NewSi1LookupTypeIDFromUInt(130),
+				SignatureType:    NewSi1LookupTypeIDFromUInt(355),
+				ExtraType:        NewSi1LookupTypeIDFromUInt(576),
+				SignedExtensions: meta.Extrinsic.SignedExtensions,
+			},
+			Type: meta.Type,
+			APIs: []RuntimeAPIMetadataV15{{
+				Name: "Metadata",
+				Methods: []RuntimeAPIMethodMetadataV15{{
+					Name:   "metadata_at_version",
+					Inputs: []RuntimeAPIMethodParamMetadataV15{{Name: "version", Type: u32}},
+					Output: NewSi1LookupTypeIDFromUInt(10),
+					Docs:   []Text{"Returns the metadata at a given version."},
+				}},
+				Docs: []Text{"The `Metadata` api trait that returns metadata for the runtime."},
+			}},
+			OuterEnums: OuterEnumsV15{
+				CallType:  NewSi1LookupTypeIDFromUInt(130),
+				EventType: NewSi1LookupTypeIDFromUInt(20),
+				ErrorType: NewSi1LookupTypeIDFromUInt(25),
+			},
+			Custom: CustomMetadataV15{Map: []CustomValueMetadataV15{
+				{Name: "answer", Type: u32, Value: Bytes{42, 0, 0, 0}},
+			}},
+			EfficientLookup: meta.EfficientLookup,
+		},
+	}
+}
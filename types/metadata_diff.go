@@ -0,0 +1,605 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MetadataChange is the kind of change of a pallet or of an item of a pallet between two metadata versions
+type MetadataChange string
+
+const (
+	MetadataAdded   MetadataChange = "added"
+	MetadataRemoved MetadataChange = "removed"
+	MetadataChanged MetadataChange = "changed"
+)
+
+// MetadataDiff lists the pallets that differ between two metadata versions. It can be rendered as text with String
+// or marshalled to JSON.
+type MetadataDiff struct {
+	Pallets []PalletDiff `json:"pallets"`
+}
+
+// PalletDiff describes how a pallet changed. Added and removed pallets don't list their items.
+type PalletDiff struct {
+	Name   string         `json:"name"`
+	Change MetadataChange `json:"change"`
+	// Details describe changes of the pallet itself, such as its index
+	Details   []string   `json:"details,omitempty"`
+	Calls     []ItemDiff `json:"calls,omitempty"`
+	Events    []ItemDiff `json:"events,omitempty"`
+	Storage   []ItemDiff `json:"storage,omitempty"`
+	Constants []ItemDiff `json:"constants,omitempty"`
+	Errors    []ItemDiff `json:"errors,omitempty"`
+}
+
+// ItemDiff describes how a call, event, storage item, constant or error of a pallet changed
+type ItemDiff struct {
+	Name   string         `json:"name"`
+	Change MetadataChange `json:"change"`
+	// Details describe the changes of a changed item, e.g. the old and the new type of a call argument
+	Details []string `json:"details,omitempty"`
+}
+
+// IsEmpty returns true if there are no differences
+func (d MetadataDiff) IsEmpty() bool {
+	return len(d.Pallets) == 0
+}
+
+// String renders the diff as text, with one line per pallet and changed item, prefixed by + for additions, - for
+// removals and ~ for changes
+func (d MetadataDiff) String() string {
+	var sb strings.Builder
+	for _, p := range d.Pallets {
+		fmt.Fprintf(&sb, "%v %v\n", metadataChangeSymbol(p.Change), p.Name)
+		for _, detail := range p.Details {
+			fmt.Fprintf(&sb, "    %v\n", detail)
+		}
+
+		for _, items := range []struct {
+			kind  string
+			diffs []ItemDiff
+		}{
+			{"call", p.Calls},
+			{"event", p.Events},
+			{"storage", p.Storage},
+			{"constant", p.Constants},
+			{"error", p.Errors},
+		} {
+			for _, item := range items.diffs {
+				fmt.Fprintf(&sb, "  %v %v %v\n", metadataChangeSymbol(item.Change), items.kind, item.Name)
+				for _, detail := range item.Details {
+					fmt.Fprintf(&sb, "      %v\n", detail)
+				}
+			}
+		}
+	}
+	return sb.String()
+}
+
+func metadataChangeSymbol(c MetadataChange) string {
+	switch c {
+	case MetadataAdded:
+		return "+"
+	case MetadataRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+// DiffMetadata compares the metadata from with the metadata to and reports added, removed and changed pallets,
+// calls, events, storage items, constants and errors. Metadata of versions 13 to 15 is supported, including the
+// comparison of V13 with later versions. In that case types are compared by the names declared in the runtime, as
+// the V13 metadata does not resolve them, and types that V14 declares no name for, such as the types of storage items
+// and constants, are not compared. Otherwise types are compared by their resolved names and their layouts, so that
+// changes of a type that keeps its name are reported too.
+func DiffMetadata(from, to *Metadata) (MetadataDiff, error) {
+	fromSummary, err := summarizeMetadata(from)
+	if err != nil {
+		return MetadataDiff{}, err
+	}
+	toSummary, err := summarizeMetadata(to)
+	if err != nil {
+		return MetadataDiff{}, err
+	}
+	resolved := fromSummary.resolved && toSummary.resolved
+
+	var diff MetadataDiff
+	toPallets := make(map[string]*palletSummary, len(toSummary.pallets))
+	for i := range toSummary.pallets {
+		toPallets[toSummary.pallets[i].name] = &toSummary.pallets[i]
+	}
+	fromPallets := make(map[string]bool, len(fromSummary.pallets))
+
+	for _, fp := range fromSummary.pallets {
+		fromPallets[fp.name] = true
+		tp, ok := toPallets[fp.name]
+		if !ok {
+			diff.Pallets = append(diff.Pallets, PalletDiff{Name: fp.name, Change: MetadataRemoved})
+			continue
+		}
+
+		p := PalletDiff{
+			Name:      fp.name,
+			Change:    MetadataChanged,
+			Details:   diffMetadataAttrs(fp.attrs, tp.attrs, resolved),
+			Calls:     diffMetadataItems(fp.calls, tp.calls, resolved),
+			Events:    diffMetadataItems(fp.events, tp.events, resolved),
+			Storage:   diffMetadataItems(fp.storage, tp.storage, resolved),
+			Constants: diffMetadataItems(fp.constants, tp.constants, resolved),
+			Errors:    diffMetadataItems(fp.errors, tp.errors, resolved),
+		}
+		if len(p.Details) > 0 || len(p.Calls) > 0 || len(p.Events) > 0 || len(p.Storage) > 0 ||
+			len(p.Constants) > 0 || len(p.Errors) > 0 {
+			diff.Pallets = append(diff.Pallets, p)
+		}
+	}
+
+	for _, tp := range toSummary.pallets {
+		if !fromPallets[tp.name] {
+			diff.Pallets = append(diff.Pallets, PalletDiff{Name: tp.name, Change: MetadataAdded})
+		}
+	}
+
+	return diff, nil
+}
+
+func diffMetadataItems(from, to []metadataItem, resolved bool) []ItemDiff {
+	var diffs []ItemDiff
+	toItems := make(map[string]*metadataItem, len(to))
+	for i := range to {
+		toItems[to[i].name] = &to[i]
+	}
+	fromItems := make(map[string]bool, len(from))
+
+	for _, fi := range from {
+		fromItems[fi.name] = true
+		ti, ok := toItems[fi.name]
+		if !ok {
+			diffs = append(diffs, ItemDiff{Name: fi.name, Change: MetadataRemoved})
+			continue
+		}
+		details := diffMetadataAttrs(fi.attrs, ti.attrs, resolved)
+		if len(details) > 0 {
+			diffs = append(diffs, ItemDiff{Name: fi.name, Change: MetadataChanged, Details: details})
+		}
+	}
+
+	for _, ti := range to {
+		if !fromItems[ti.name] {
+			diffs = append(diffs, ItemDiff{Name: ti.name, Change: MetadataAdded, Details: describeMetadataAttrs(ti.attrs)})
+		}
+	}
+	return diffs
+}
+
+// diffMetadataAttrs describes the attributes that have been added, removed or changed
+func diffMetadataAttrs(from, to []metadataAttr, resolved bool) []string {
+	var details []string
+	// V13 event fields have no names, fields are matched by position if either side doesn't name them
+	if hasUnnamedFields(from) || hasUnnamedFields(to) {
+		from, to = keyFieldsByPosition(from), keyFieldsByPosition(to)
+	}
+	toAttrs := make(map[string]metadataAttr, len(to))
+	for _, a := range to {
+		toAttrs[a.key] = a
+	}
+	fromAttrs := make(map[string]bool, len(from))
+
+	for _, fa := range from {
+		fromAttrs[fa.key] = true
+		ta, ok := toAttrs[fa.key]
+		if !ok {
+			details = append(details, fmt.Sprintf("%v removed", fa.key))
+			continue
+		}
+		if !resolved && (fa.declared == "" || ta.declared == "") {
+			// types without a declared name can't be compared with the types of unresolved metadata
+			continue
+		}
+		if fa.value(resolved) != ta.value(resolved) {
+			details = append(details, fmt.Sprintf("%v: %v -> %v", fa.key, fa.value(resolved), ta.value(resolved)))
+		} else if resolved && fa.layout != ta.layout {
+			details = append(details, fmt.Sprintf("%v: %v layout changed", fa.key, fa.value(resolved)))
+		}
+	}
+
+	for _, ta := range to {
+		if !fromAttrs[ta.key] {
+			details = append(details, fmt.Sprintf("%v added: %v", ta.key, ta.value(false)))
+		}
+	}
+	return details
+}
+
+func hasUnnamedFields(attrs []metadataAttr) bool {
+	for _, a := range attrs {
+		if a.position != "" && a.key == a.position {
+			return true
+		}
+	}
+	return false
+}
+
+func keyFieldsByPosition(attrs []metadataAttr) []metadataAttr {
+	keyed := make([]metadataAttr, len(attrs))
+	for i, a := range attrs {
+		if a.position != "" {
+			a.key = a.position
+		}
+		keyed[i] = a
+	}
+	return keyed
+}
+
+func describeMetadataAttrs(attrs []metadataAttr) []string {
+	var details []string
+	for _, a := range attrs {
+		details = append(details, fmt.Sprintf("%v: %v", a.key, a.value(true)))
+	}
+	return details
+}
+
+// metadataSummary is the part of the metadata that is compared by DiffMetadata, in a form that is independent of the
+// metadata version
+type metadataSummary struct {
+	// resolved is true if the types of the metadata have been resolved through a portable registry
+	resolved bool
+	pallets  []palletSummary
+}
+
+type palletSummary struct {
+	name      string
+	attrs     []metadataAttr
+	calls     []metadataItem
+	events    []metadataItem
+	storage   []metadataItem
+	constants []metadataItem
+	errors    []metadataItem
+}
+
+type metadataItem struct {
+	name  string
+	attrs []metadataAttr
+}
+
+// metadataAttr is an attribute of a pallet or an item, such as its index or the type of an argument. Types have the
+// name declared in the runtime, e.g. T::Balance, if the metadata declares one, and for V14 and above the resolved
+// type, e.g. u128, and its layout.
+type metadataAttr struct {
+	key      string
+	declared string
+	resolved string
+	// layout is the structure of a resolved type, which changes if the encoding of the type changes but not its name
+	layout string
+	// position is the key of a variant field by its position, e.g. field 0, the key of unnamed fields
+	position string
+}
+
+func (a metadataAttr) value(resolved bool) string {
+	if (resolved || a.declared == "") && a.resolved != "" {
+		return a.resolved
+	}
+	return a.declared
+}
+
+func newMetadataAttr(key, value string) metadataAttr {
+	return metadataAttr{key: key, declared: value, resolved: value}
+}
+
+// newTypeAttr returns the attribute of a resolved type, which has no declared name
+func newTypeAttr(layouts *typeLayouts, key string, typeID Si1LookupTypeID) metadataAttr {
+	return metadataAttr{key: key, resolved: layouts.names.typeString(typeID), layout: layouts.layout(typeID)}
+}
+
+func summarizeMetadata(m *Metadata) (metadataSummary, error) {
+	switch m.Version {
+	case 13:
+		return summarizeMetadataV13(&m.AsMetadataV13), nil
+	case 14:
+		meta := &m.AsMetadataV14
+		pallets := make([]palletSummary, len(meta.Pallets))
+		layouts := newTypeLayouts(meta.lookup())
+		for i, p := range meta.Pallets {
+			pallets[i] = summarizePalletV14(layouts, p)
+		}
+		return metadataSummary{resolved: true, pallets: pallets}, nil
+	case 15:
+		meta := &m.AsMetadataV15
+		pallets := make([]palletSummary, len(meta.Pallets))
+		layouts := newTypeLayouts(meta.lookup())
+		for i, p := range meta.palletsV14() {
+			pallets[i] = summarizePalletV14(layouts, p)
+		}
+		return metadataSummary{resolved: true, pallets: pallets}, nil
+	default:
+		return metadataSummary{}, fmt.Errorf("diffing metadata version %v is not supported", m.Version)
+	}
+}
+
+func summarizeMetadataV13(m *MetadataV13) metadataSummary {
+	pallets := make([]palletSummary, len(m.Modules))
+	for i, mod := range m.Modules {
+		p := palletSummary{
+			name:  string(mod.Name),
+			attrs: []metadataAttr{newMetadataAttr("index", fmt.Sprint(mod.Index))},
+		}
+
+		for ci, c := range mod.Calls {
+			item := metadataItem{name: string(c.Name), attrs: []metadataAttr{newMetadataAttr("index", fmt.Sprint(ci))}}
+			for _, a := range c.Args {
+				item.attrs = append(item.attrs, metadataAttr{key: "argument " + string(a.Name), declared: string(a.Type)})
+			}
+			p.calls = append(p.calls, item)
+		}
+
+		for ei, e := range mod.Events {
+			item := metadataItem{name: string(e.Name), attrs: []metadataAttr{newMetadataAttr("index", fmt.Sprint(ei))}}
+			for fi, a := range e.Args {
+				key := fmt.Sprintf("field %v", fi)
+				item.attrs = append(item.attrs, metadataAttr{key: key, declared: string(a), position: key})
+			}
+			p.events = append(p.events, item)
+		}
+
+		for _, s := range mod.Storage.Items {
+			item := metadataItem{name: string(s.Name), attrs: []metadataAttr{
+				newMetadataAttr("modifier", storageModifierName(s.Modifier)),
+			}}
+			typ := s.Type
+			switch {
+			case typ.IsType:
+				item.attrs = append(item.attrs, metadataAttr{key: "value", declared: string(typ.AsType)})
+			case typ.IsMap:
+				item.attrs = append(item.attrs,
+					newMetadataAttr("hashers", storageHasherNames(typ.AsMap.Hasher)),
+					metadataAttr{key: "key", declared: string(typ.AsMap.Key)},
+					metadataAttr{key: "value", declared: string(typ.AsMap.Value)})
+			case typ.IsDoubleMap:
+				item.attrs = append(item.attrs,
+					newMetadataAttr("hashers", storageHasherNames(typ.AsDoubleMap.Hasher, typ.AsDoubleMap.Key2Hasher)),
+					metadataAttr{key: "key", declared: fmt.Sprintf("(%v, %v)", typ.AsDoubleMap.Key1, typ.AsDoubleMap.Key2)},
+					metadataAttr{key: "value", declared: string(typ.AsDoubleMap.Value)})
+			case typ.IsNMap:
+				keys := make([]string, len(typ.AsNMap.Keys))
+				for ki, k := range typ.AsNMap.Keys {
+					keys[ki] = string(k)
+				}
+				item.attrs = append(item.attrs,
+					newMetadataAttr("hashers", storageHasherNames(typ.AsNMap.Hashers...)),
+					metadataAttr{key: "key", declared: "(" + strings.Join(keys, ", ") + ")"},
+					metadataAttr{key: "value", declared: string(typ.AsNMap.Value)})
+			}
+			p.storage = append(p.storage, item)
+		}
+
+		for _, c := range mod.Constants {
+			p.constants = append(p.constants, metadataItem{name: string(c.Name), attrs: []metadataAttr{
+				{key: "type", declared: string(c.Type)},
+				newMetadataAttr("value", HexEncodeToString(c.Value)),
+			}})
+		}
+
+		for ei, e := range mod.Errors {
+			p.errors = append(p.errors, metadataItem{name: string(e.Name), attrs: []metadataAttr{
+				newMetadataAttr("index", fmt.Sprint(ei)),
+			}})
+		}
+
+		pallets[i] = p
+	}
+	return metadataSummary{pallets: pallets}
+}
+
+func summarizePalletV14(layouts *typeLayouts, mod PalletMetadataV14) palletSummary {
+	p := palletSummary{
+		name:  string(mod.Name),
+		attrs: []metadataAttr{newMetadataAttr("index", fmt.Sprint(mod.Index))},
+	}
+
+	if mod.HasCalls {
+		p.calls = summarizeVariantsV14(layouts, mod.Calls.Type, "argument ")
+	}
+	if mod.HasEvents {
+		p.events = summarizeVariantsV14(layouts, mod.Events.Type, "field ")
+	}
+	if mod.HasErrors {
+		p.errors = summarizeVariantsV14(layouts, mod.Errors.Type, "field ")
+	}
+
+	if mod.HasStorage {
+		for _, s := range mod.Storage.Items {
+			item := metadataItem{name: string(s.Name), attrs: []metadataAttr{
+				newMetadataAttr("modifier", storageModifierName(s.Modifier)),
+			}}
+			if s.Type.IsPlainType {
+				item.attrs = append(item.attrs, newTypeAttr(layouts, "value", s.Type.AsPlainType))
+			} else {
+				item.attrs = append(item.attrs,
+					newMetadataAttr("hashers", storageHasherNames(s.Type.AsMap.Hashers...)),
+					newTypeAttr(layouts, "key", s.Type.AsMap.Key),
+					newTypeAttr(layouts, "value", s.Type.AsMap.Value))
+			}
+			p.storage = append(p.storage, item)
+		}
+	}
+
+	for _, c := range mod.Constants {
+		p.constants = append(p.constants, metadataItem{name: string(c.Name), attrs: []metadataAttr{
+			newTypeAttr(layouts, "type", c.Type),
+			newMetadataAttr("value", HexEncodeToString(c.Value)),
+		}})
+	}
+
+	return p
+}
+
+// summarizeVariantsV14 summarizes the variants of the enum typeID, such as the calls of a pallet. Named fields are
+// keyed by their name, unnamed fields by their position. The positions of event and error fields are kept to compare
+// them with the unnamed fields of V13 events.
+func summarizeVariantsV14(layouts *typeLayouts, typeID Si1LookupTypeID, fieldKey string) []metadataItem {
+	typ, ok := layouts.lookup[typeID.Int64()]
+	if !ok || !typ.Def.IsVariant {
+		return nil
+	}
+
+	items := make([]metadataItem, len(typ.Def.Variant.Variants))
+	for i, v := range typ.Def.Variant.Variants {
+		item := metadataItem{name: string(v.Name), attrs: []metadataAttr{newMetadataAttr("index", fmt.Sprint(v.Index))}}
+		for fi, f := range v.Fields {
+			position := fmt.Sprintf("field %v", fi)
+			key := position
+			if f.HasName {
+				key = fieldKey + string(f.Name)
+			}
+			attr := newTypeAttr(layouts, key, f.Type)
+			if fieldKey == "field " {
+				attr.position = position
+			}
+			if f.HasTypeName {
+				attr.declared = string(f.TypeName)
+				// the declared name of compact fields omits the compact encoding, which V13 metadata includes
+				if ft, ok := layouts.lookup[f.Type.Int64()]; ok && ft.Def.IsCompact {
+					attr.declared = fmt.Sprintf("Compact<%v>", f.TypeName)
+				}
+			}
+			item.attrs = append(item.attrs, attr)
+		}
+		items[i] = item
+	}
+	return items
+}
+
+// typeLayouts renders the layouts of registry types, their structure without their names, so that DiffMetadata detects
+// changes of types whose names are unchanged. A type that contains itself is rendered by name where it recurses.
+type typeLayouts struct {
+	lookup map[int64]*Si1Type
+	names  *typeStringer
+	// depths are the depths of the types that are being rendered
+	depths map[int64]int
+	// layouts are the rendered types whose layouts don't depend on the types that are being rendered
+	layouts map[int64]string
+}
+
+func newTypeLayouts(lookup map[int64]*Si1Type) *typeLayouts {
+	return &typeLayouts{
+		lookup:  lookup,
+		names:   newTypeStringer(lookup, true),
+		depths:  map[int64]int{},
+		layouts: map[int64]string{},
+	}
+}
+
+func (l *typeLayouts) layout(typeID Si1LookupTypeID) string {
+	layout, _ := l.render(typeID)
+	return layout
+}
+
+// render returns the layout of typeID and the smallest depth of the types being rendered that it refers to by name
+func (l *typeLayouts) render(typeID Si1LookupTypeID) (string, int) {
+	id := typeID.Int64()
+	if layout, ok := l.layouts[id]; ok {
+		return layout, len(l.depths)
+	}
+	if depth, ok := l.depths[id]; ok {
+		return l.names.typeString(typeID), depth
+	}
+	typ, ok := l.lookup[id]
+	if !ok {
+		return fmt.Sprintf("type %v", id), len(l.depths)
+	}
+
+	depth := len(l.depths)
+	l.depths[id] = depth
+	defer delete(l.depths, id)
+
+	refDepth := depth + 1
+	render := func(typeID Si1LookupTypeID) string {
+		layout, d := l.render(typeID)
+		if d < refDepth {
+			refDepth = d
+		}
+		return layout
+	}
+	renderFields := func(fields []Si1Field) string {
+		rendered := make([]string, len(fields))
+		for i, f := range fields {
+			rendered[i] = render(f.Type)
+			if f.HasName {
+				rendered[i] = fmt.Sprintf("%v: %v", f.Name, rendered[i])
+			}
+		}
+		return "(" + strings.Join(rendered, ", ") + ")"
+	}
+
+	var layout string
+	def := typ.Def
+	switch {
+	case def.IsComposite:
+		layout = renderFields(def.Composite.Fields)
+	case def.IsVariant:
+		variants := make([]string, len(def.Variant.Variants))
+		for i, v := range def.Variant.Variants {
+			variants[i] = fmt.Sprintf("%v %v%v", v.Index, v.Name, renderFields(v.Fields))
+		}
+		layout = "enum { " + strings.Join(variants, " | ") + " }"
+	case def.IsSequence:
+		layout = fmt.Sprintf("Vec<%v>", render(def.Sequence.Type))
+	case def.IsArray:
+		layout = fmt.Sprintf("[%v; %v]", render(def.Array.Type), def.Array.Len)
+	case def.IsTuple:
+		rendered := make([]string, len(def.Tuple))
+		for i, t := range def.Tuple {
+			rendered[i] = render(t)
+		}
+		layout = "(" + strings.Join(rendered, ", ") + ")"
+	case def.IsCompact:
+		layout = fmt.Sprintf("Compact<%v>", render(def.Compact.Type))
+	case def.IsBitSequence:
+		layout = fmt.Sprintf("BitVec<%v, %v>", l.names.typeString(def.BitSequence.BitStoreType),
+			l.names.typeString(def.BitSequence.BitOrderType))
+	default:
+		layout = l.names.typeString(typeID)
+	}
+
+	// layouts that only refer to the type itself by name are the same wherever the type is rendered
+	if refDepth >= depth {
+		l.layouts[id] = layout
+	}
+	return layout, refDepth
+}
+
+func storageModifierName(m StorageFunctionModifierV0) string {
+	switch {
+	case m.IsOptional:
+		return "Optional"
+	case m.IsDefault:
+		return "Default"
+	default:
+		return "Required"
+	}
+}
+
+func storageHasherNames(hashers ...StorageHasherV10) string {
+	names := make([]string, len(hashers))
+	for i, h := range hashers {
+		switch {
+		case h.IsBlake2_128:
+			names[i] = "Blake2_128"
+		case h.IsBlake2_256:
+			names[i] = "Blake2_256"
+		case h.IsBlake2_128Concat:
+			names[i] = "Blake2_128Concat"
+		case h.IsTwox128:
+			names[i] = "Twox128"
+		case h.IsTwox256:
+			names[i] = "Twox256"
+		case h.IsTwox64Concat:
+			names[i] = "Twox64Concat"
+		default:
+			names[i] = "Identity"
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,265 @@
+package types_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffMetadata_Identical(t *testing.T) {
+	diff, err := DiffMetadata(DecodedMetadataV14Example(), DecodedMetadataV14Example())
+	assert.NoError(t, err)
+	assert.True(t, diff.IsEmpty())
+	assert.Equal(t, "", diff.String())
+
+	diff, err = DiffMetadata(ExamplaryMetadataV13, ExamplaryMetadataV13)
+	assert.NoError(t, err)
+	assert.True(t, diff.IsEmpty())
+
+	// the same runtime in V14 and V15
+	diff, err = DiffMetadata(DecodedMetadataV14Example(), metadataV15FromV14(t))
+	assert.NoError(t, err)
+	assert.True(t, diff.IsEmpty())
+}
+
+func TestDiffMetadata(t *testing.T) {
+	from := DecodedMetadataV14Example()
+	to := DecodedMetadataV14Example()
+	meta := &to.AsMetadataV14
+
+	var pallets []PalletMetadataV14
+	for _, p := range meta.Pallets {
+		switch p.Name {
+		case "System":
+			p.Constants[len(p.Constants)-1].Value = Bytes{42, 0}
+			p.Storage.Items[0].Type.AsMap.Hashers = []StorageHasherV10{{IsTwox64Concat: true}}
+		case "Utility":
+			continue
+		case "Timestamp":
+			p.Index = 100
+		}
+		pallets = append(pallets, p)
+	}
+	pallets = append(pallets, PalletMetadataV14{Name: "Custom", Index: 200})
+	meta.Pallets = pallets
+
+	for i, typ := range meta.Lookup.Types {
+		switch typ.ID.Int64() {
+		case 109:
+			// System.set_heap_pages takes a u32 instead of a u64
+			for j, v := range typ.Type.Def.Variant.Variants {
+				if v.Name == "set_heap_pages" {
+					meta.Lookup.Types[i].Type.Def.Variant.Variants[j].Fields[0].Type = NewSi1LookupTypeIDFromUInt(4)
+				}
+			}
+		case 383:
+			// Balances.InsufficientBalance is renamed
+			for j, v := range typ.Type.Def.Variant.Variants {
+				if v.Name == "InsufficientBalance" {
+					meta.Lookup.Types[i].Type.Def.Variant.Variants[j].Name = "BalanceTooLow"
+				}
+			}
+		}
+	}
+	meta.EfficientLookup = nil
+
+	diff, err := DiffMetadata(from, to)
+	assert.NoError(t, err)
+	assert.False(t, diff.IsEmpty())
+
+	// changing a call changes the layout of the runtime call, which is taken as argument by calls such as Proxy.proxy
+	var palletDiffs []PalletDiff
+	for _, p := range diff.Pallets {
+		switch p.Name {
+		case "Scheduler", "Council", "TechnicalCommittee", "Multisig":
+			assert.Equal(t, MetadataChanged, p.Change)
+		case "Proxy":
+			assert.Equal(t, []ItemDiff{
+				{Name: "proxy", Change: MetadataChanged, Details: []string{"argument call: polkadot_runtime::Call layout changed"}},
+				{Name: "proxy_announced", Change: MetadataChanged,
+					Details: []string{"argument call: polkadot_runtime::Call layout changed"}},
+			}, p.Calls)
+		default:
+			palletDiffs = append(palletDiffs, p)
+		}
+	}
+	diff.Pallets = palletDiffs
+
+	expected := MetadataDiff{Pallets: []PalletDiff{
+		{
+			Name:   "System",
+			Change: MetadataChanged,
+			Calls: []ItemDiff{{
+				Name: "set_heap_pages", Change: MetadataChanged, Details: []string{"argument pages: u64 -> u32"},
+			}},
+			Storage: []ItemDiff{{
+				Name: "Account", Change: MetadataChanged, Details: []string{"hashers: Blake2_128Concat -> Twox64Concat"},
+			}},
+			Constants: []ItemDiff{{
+				Name: "SS58Prefix", Change: MetadataChanged, Details: []string{"value: 0x0000 -> 0x2a00"},
+			}},
+		},
+		{Name: "Timestamp", Change: MetadataChanged, Details: []string{"index: 3 -> 100"}},
+		{
+			Name:   "Balances",
+			Change: MetadataChanged,
+			Errors: []ItemDiff{
+				{Name: "InsufficientBalance", Change: MetadataRemoved},
+				{Name: "BalanceTooLow", Change: MetadataAdded, Details: []string{"index: 2"}},
+			},
+		},
+		{Name: "Utility", Change: MetadataRemoved},
+		{Name: "Custom", Change: MetadataAdded},
+	}}
+	assert.Equal(t, expected, diff)
+
+	assert.Equal(t, `~ System
+  ~ call set_heap_pages
+      argument pages: u64 -> u32
+  ~ storage Account
+      hashers: Blake2_128Concat -> Twox64Concat
+  ~ constant SS58Prefix
+      value: 0x0000 -> 0x2a00
+~ Timestamp
+    index: 3 -> 100
+~ Balances
+  - error InsufficientBalance
+  + error BalanceTooLow
+      index: 2
+- Utility
++ Custom
+`, diff.String())
+
+	enc, err := json.Marshal(MetadataDiff{Pallets: diff.Pallets[3:]})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"pallets":[{"name":"Utility","change":"removed"},{"name":"Custom","change":"added"}]}`,
+		string(enc))
+}
+
+func TestDiffMetadata_V13ToV14(t *testing.T) {
+	diff, err := DiffMetadata(ExamplaryMetadataV13, DecodedMetadataV14Example())
+	assert.NoError(t, err)
+
+	var balances *PalletDiff
+	for i, p := range diff.Pallets {
+		if p.Name == "Balances" {
+			balances = &diff.Pallets[i]
+		}
+	}
+	assert.NotNil(t, balances)
+	assert.Equal(t, MetadataChanged, balances.Change)
+	assert.Contains(t, balances.Details, "index: 6 -> 5")
+
+	// V13 types are only known by their declared names, which are compared with the names declared in V14
+	for _, c := range balances.Calls {
+		assert.NotEqual(t, "transfer", c.Name)
+	}
+
+	// V14 storage items and constants have no declared names, and the resolved names are not compared with V13 names
+	for _, s := range balances.Storage {
+		assert.NotEqual(t, "Account", s.Name)
+	}
+	// the type of the constant is unchanged, only its value differs between the two chains
+	for _, c := range balances.Constants {
+		if c.Name == "ExistentialDeposit" {
+			assert.Equal(t, []string{"value: 0x00407a10f35a00000000000000000000 -> 0x00e40b54020000000000000000000000"},
+				c.Details)
+		}
+	}
+	for _, p := range diff.Pallets {
+		if p.Name == "System" {
+			for _, s := range p.Storage {
+				assert.NotEqual(t, "Account", s.Name)
+			}
+		}
+	}
+}
+
+func TestDiffMetadata_V13ToV14NamedFields(t *testing.T) {
+	to := DecodedMetadataV14Example()
+	meta := &to.AsMetadataV14
+
+	// later runtimes name the fields of Balances.Transfer, which V13 metadata can't
+	for _, p := range meta.Pallets {
+		if p.Name != "Balances" {
+			continue
+		}
+		for i, typ := range meta.Lookup.Types {
+			if typ.ID.Int64() != p.Events.Type.Int64() {
+				continue
+			}
+			for j, v := range typ.Type.Def.Variant.Variants {
+				if v.Name != "Transfer" {
+					continue
+				}
+				fields := meta.Lookup.Types[i].Type.Def.Variant.Variants[j].Fields
+				for k, name := range []Text{"from", "to", "amount"} {
+					fields[k].HasName = true
+					fields[k].Name = name
+				}
+			}
+		}
+	}
+	meta.EfficientLookup = nil
+
+	diff, err := DiffMetadata(ExamplaryMetadataV13, to)
+	assert.NoError(t, err)
+
+	var transfer *ItemDiff
+	for _, p := range diff.Pallets {
+		if p.Name != "Balances" {
+			continue
+		}
+		for i, e := range p.Events {
+			if e.Name == "Transfer" {
+				transfer = &p.Events[i]
+			}
+		}
+	}
+	assert.NotNil(t, transfer)
+	assert.Equal(t, []string{
+		"field 0: AccountId -> T::AccountId",
+		"field 1: AccountId -> T::AccountId",
+		"field 2: Balance -> T::Balance",
+	}, transfer.Details)
+
+	// fields named on both sides are still matched by name
+	diff, err = DiffMetadata(to, to)
+	assert.NoError(t, err)
+	assert.True(t, diff.IsEmpty())
+}
+
+func TestDiffMetadata_Layout(t *testing.T) {
+	from := DecodedMetadataV14Example()
+	to := DecodedMetadataV14Example()
+	meta := &to.AsMetadataV14
+
+	for i, typ := range meta.Lookup.Types {
+		if typ.ID.Int64() == 5 {
+			// pallet_balances::AccountData loses its fee_frozen field, its name stays the same
+			fields := typ.Type.Def.Composite.Fields
+			meta.Lookup.Types[i].Type.Def.Composite.Fields = fields[:len(fields)-1]
+		}
+	}
+	meta.EfficientLookup = nil
+
+	diff, err := DiffMetadata(from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, `~ System
+  ~ storage Account
+      value: frame_system::AccountInfo<u32, pallet_balances::AccountData<u128>> layout changed
+~ Balances
+  ~ storage Account
+      value: pallet_balances::AccountData<u128> layout changed
+`, diff.String())
+}
+
+func TestDiffMetadata_Unsupported(t *testing.T) {
+	_, err := DiffMetadata(ExamplaryMetadataV10, DecodedMetadataV14Example())
+	assert.EqualError(t, err, "diffing metadata version 10 is not supported")
+
+	_, err = DiffMetadata(DecodedMetadataV14Example(), &Metadata{Version: 12})
+	assert.EqualError(t, err, "diffing metadata version 12 is not supported")
+}
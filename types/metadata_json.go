@@ -0,0 +1,782 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MarshalJSON returns the metadata in the JSON format of polkadot-js, as returned by metadata.toJSON(). Enums with
+// data are objects with a single camelCase key, enums without data are strings, options are null if not set and
+// bytes are hex encoded.
+func (m Metadata) MarshalJSON() ([]byte, error) {
+	var v interface{}
+	switch m.Version {
+	case 9:
+		v = metadataV9JSON{Modules: modulesV8JSON(m.AsMetadataV9.Modules)}
+	case 10:
+		v = metadataV10JSON{Modules: modulesV10JSON(m.AsMetadataV10.Modules)}
+	case 11:
+		v = metadataV11JSON{
+			Modules:   modulesV10JSON(m.AsMetadataV11.Modules),
+			Extrinsic: extrinsicV11ToJSON(m.AsMetadataV11.Extrinsic),
+		}
+	case 12:
+		modules := make([]moduleJSON, len(m.AsMetadataV12.Modules))
+		for i, mod := range m.AsMetadataV12.Modules {
+			index := mod.Index
+			modules[i] = moduleV10ToJSON(mod.ModuleMetadataV10)
+			modules[i].Index = &index
+		}
+		v = metadataV11JSON{Modules: modules, Extrinsic: extrinsicV11ToJSON(m.AsMetadataV12.Extrinsic)}
+	case 13:
+		v = metadataV11JSON{
+			Modules:   modulesV13JSON(m.AsMetadataV13.Modules),
+			Extrinsic: extrinsicV11ToJSON(m.AsMetadataV13.Extrinsic),
+		}
+	case 14:
+		v = metadataV14ToJSON(&m.AsMetadataV14)
+	case 15:
+		v = metadataV15ToJSON(&m.AsMetadataV15)
+	default:
+		return nil, fmt.Errorf("JSON encoding of metadata version %v is not supported", m.Version)
+	}
+
+	return json.Marshal(metadataJSON{
+		MagicNumber: m.MagicNumber,
+		Metadata:    map[string]interface{}{fmt.Sprintf("v%v", m.Version): v},
+	})
+}
+
+type metadataJSON struct {
+	MagicNumber uint32                 `json:"magicNumber"`
+	Metadata    map[string]interface{} `json:"metadata"`
+}
+
+/* V9 - V13 */
+
+type metadataV9JSON struct {
+	Modules []moduleJSON `json:"modules"`
+}
+
+type metadataV10JSON metadataV9JSON
+
+type metadataV11JSON struct {
+	Modules   []moduleJSON  `json:"modules"`
+	Extrinsic extrinsicJSON `json:"extrinsic"`
+}
+
+type moduleJSON struct {
+	Name      string         `json:"name"`
+	Storage   *storageJSON   `json:"storage"`
+	Calls     []functionJSON `json:"calls"`
+	Events    []eventJSON    `json:"events"`
+	Constants []constantJSON `json:"constants"`
+	Errors    []errorJSON    `json:"errors"`
+	Index     *uint8         `json:"index,omitempty"`
+}
+
+type storageJSON struct {
+	Prefix string            `json:"prefix"`
+	Items  []storageItemJSON `json:"items"`
+}
+
+type storageItemJSON struct {
+	Name     string      `json:"name"`
+	Modifier string      `json:"modifier"`
+	Type     interface{} `json:"type"`
+	Fallback string      `json:"fallback"`
+	Docs     []string    `json:"docs"`
+}
+
+type mapTypeJSON struct {
+	Hasher string `json:"hasher"`
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Linked bool   `json:"linked"`
+}
+
+type doubleMapTypeJSON struct {
+	Hasher     string `json:"hasher"`
+	Key1       string `json:"key1"`
+	Key2       string `json:"key2"`
+	Value      string `json:"value"`
+	Key2Hasher string `json:"key2Hasher"`
+}
+
+type nMapTypeJSON struct {
+	KeyVec  []string `json:"keyVec"`
+	Hashers []string `json:"hashers"`
+	Value   string   `json:"value"`
+}
+
+type functionJSON struct {
+	Name string             `json:"name"`
+	Args []functionArgsJSON `json:"args"`
+	Docs []string           `json:"docs"`
+}
+
+type functionArgsJSON struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type eventJSON struct {
+	Name string   `json:"name"`
+	Args []string `json:"args"`
+	Docs []string `json:"docs"`
+}
+
+type constantJSON struct {
+	Name  string   `json:"name"`
+	Type  string   `json:"type"`
+	Value string   `json:"value"`
+	Docs  []string `json:"docs"`
+}
+
+type errorJSON struct {
+	Name string   `json:"name"`
+	Docs []string `json:"docs"`
+}
+
+type extrinsicJSON struct {
+	Version          uint8    `json:"version"`
+	SignedExtensions []string `json:"signedExtensions"`
+}
+
+func modulesV8JSON(modules []ModuleMetadataV8) []moduleJSON {
+	res := make([]moduleJSON, len(modules))
+	for i, m := range modules {
+		res[i] = moduleToJSON(m.Name, m.HasCalls, m.Calls, m.HasEvents, m.Events, m.Constants, m.Errors)
+		if m.HasStorage {
+			items := make([]storageItemJSON, len(m.Storage.Items))
+			for j, item := range m.Storage.Items {
+				items[j] = storageItemToJSON(item.Name, item.Modifier, storageTypeV5ToJSON(item.Type), item.Fallback,
+					item.Documentation)
+			}
+			res[i].Storage = &storageJSON{Prefix: string(m.Storage.Prefix), Items: items}
+		}
+	}
+	return res
+}
+
+func modulesV10JSON(modules []ModuleMetadataV10) []moduleJSON {
+	res := make([]moduleJSON, len(modules))
+	for i, m := range modules {
+		res[i] = moduleV10ToJSON(m)
+	}
+	return res
+}
+
+func moduleV10ToJSON(m ModuleMetadataV10) moduleJSON {
+	res := moduleToJSON(m.Name, m.HasCalls, m.Calls, m.HasEvents, m.Events, m.Constants, m.Errors)
+	if m.HasStorage {
+		items := make([]storageItemJSON, len(m.Storage.Items))
+		for i, item := range m.Storage.Items {
+			items[i] = storageItemToJSON(item.Name, item.Modifier, storageTypeV10ToJSON(item.Type), item.Fallback,
+				item.Documentation)
+		}
+		res.Storage = &storageJSON{Prefix: string(m.Storage.Prefix), Items: items}
+	}
+	return res
+}
+
+func modulesV13JSON(modules []ModuleMetadataV13) []moduleJSON {
+	res := make([]moduleJSON, len(modules))
+	for i, m := range modules {
+		index := m.Index
+		res[i] = moduleToJSON(m.Name, m.HasCalls, m.Calls, m.HasEvents, m.Events, m.Constants, m.Errors)
+		res[i].Index = &index
+		if m.HasStorage {
+			items := make([]storageItemJSON, len(m.Storage.Items))
+			for j, item := range m.Storage.Items {
+				items[j] = storageItemToJSON(item.Name, item.Modifier, storageTypeV13ToJSON(item.Type), item.Fallback,
+					item.Documentation)
+			}
+			res[i].Storage = &storageJSON{Prefix: string(m.Storage.Prefix), Items: items}
+		}
+	}
+	return res
+}
+
+// moduleToJSON converts the parts all module versions share, calls and events are null if the module has none
+func moduleToJSON(name Text, hasCalls bool, calls []FunctionMetadataV4, hasEvents bool, events []EventMetadataV4,
+	constants []ModuleConstantMetadataV6, errs []ErrorMetadataV8) moduleJSON {
+	res := moduleJSON{
+		Name:      string(name),
+		Constants: make([]constantJSON, len(constants)),
+		Errors:    make([]errorJSON, len(errs)),
+	}
+
+	if hasCalls {
+		res.Calls = make([]functionJSON, len(calls))
+		for i, c := range calls {
+			args := make([]functionArgsJSON, len(c.Args))
+			for j, a := range c.Args {
+				args[j] = functionArgsJSON{Name: string(a.Name), Type: string(a.Type)}
+			}
+			res.Calls[i] = functionJSON{Name: string(c.Name), Args: args, Docs: textsToJSON(c.Documentation)}
+		}
+	}
+	if hasEvents {
+		res.Events = make([]eventJSON, len(events))
+		for i, e := range events {
+			args := make([]string, len(e.Args))
+			for j, a := range e.Args {
+				args[j] = string(a)
+			}
+			res.Events[i] = eventJSON{Name: string(e.Name), Args: args, Docs: textsToJSON(e.Documentation)}
+		}
+	}
+	for i, c := range constants {
+		res.Constants[i] = constantJSON{
+			Name:  string(c.Name),
+			Type:  string(c.Type),
+			Value: HexEncodeToString(c.Value),
+			Docs:  textsToJSON(c.Documentation),
+		}
+	}
+	for i, e := range errs {
+		res.Errors[i] = errorJSON{Name: string(e.Name), Docs: textsToJSON(e.Documentation)}
+	}
+	return res
+}
+
+func storageItemToJSON(name Text, modifier StorageFunctionModifierV0, typ interface{}, fallback Bytes,
+	docs []Text) storageItemJSON {
+	return storageItemJSON{
+		Name:     string(name),
+		Modifier: modifierToJSON(modifier),
+		Type:     typ,
+		Fallback: HexEncodeToString(fallback),
+		Docs:     textsToJSON(docs),
+	}
+}
+
+func storageTypeV5ToJSON(t StorageFunctionTypeV5) interface{} {
+	switch {
+	case t.IsMap:
+		return map[string]interface{}{"map": mapTypeJSON{
+			Hasher: hasherToJSON(t.AsMap.Hasher),
+			Key:    string(t.AsMap.Key),
+			Value:  string(t.AsMap.Value),
+			Linked: t.AsMap.Linked,
+		}}
+	case t.IsDoubleMap:
+		return map[string]interface{}{"doubleMap": doubleMapTypeJSON{
+			Hasher:     hasherToJSON(t.AsDoubleMap.Hasher),
+			Key1:       string(t.AsDoubleMap.Key1),
+			Key2:       string(t.AsDoubleMap.Key2),
+			Value:      string(t.AsDoubleMap.Value),
+			Key2Hasher: hasherToJSON(t.AsDoubleMap.Key2Hasher),
+		}}
+	default:
+		return map[string]interface{}{"plain": string(t.AsType)}
+	}
+}
+
+func storageTypeV10ToJSON(t StorageFunctionTypeV10) interface{} {
+	return storageTypeV13ToJSON(StorageFunctionTypeV13{
+		IsType:      t.IsType,
+		AsType:      t.AsType,
+		IsMap:       t.IsMap,
+		AsMap:       t.AsMap,
+		IsDoubleMap: t.IsDoubleMap,
+		AsDoubleMap: t.AsDoubleMap,
+	})
+}
+
+func storageTypeV13ToJSON(t StorageFunctionTypeV13) interface{} {
+	switch {
+	case t.IsMap:
+		return map[string]interface{}{"map": mapTypeJSON{
+			Hasher: hasherV10ToJSON(t.AsMap.Hasher),
+			Key:    string(t.AsMap.Key),
+			Value:  string(t.AsMap.Value),
+			Linked: t.AsMap.Linked,
+		}}
+	case t.IsDoubleMap:
+		return map[string]interface{}{"doubleMap": doubleMapTypeJSON{
+			Hasher:     hasherV10ToJSON(t.AsDoubleMap.Hasher),
+			Key1:       string(t.AsDoubleMap.Key1),
+			Key2:       string(t.AsDoubleMap.Key2),
+			Value:      string(t.AsDoubleMap.Value),
+			Key2Hasher: hasherV10ToJSON(t.AsDoubleMap.Key2Hasher),
+		}}
+	case t.IsNMap:
+		keys := make([]string, len(t.AsNMap.Keys))
+		for i, k := range t.AsNMap.Keys {
+			keys[i] = string(k)
+		}
+		return map[string]interface{}{"nMap": nMapTypeJSON{
+			KeyVec:  keys,
+			Hashers: hashersV10ToJSON(t.AsNMap.Hashers),
+			Value:   string(t.AsNMap.Value),
+		}}
+	default:
+		return map[string]interface{}{"plain": string(t.AsType)}
+	}
+}
+
+func extrinsicV11ToJSON(e ExtrinsicV11) extrinsicJSON {
+	exts := make([]string, len(e.SignedExtensions))
+	copy(exts, e.SignedExtensions)
+	return extrinsicJSON{Version: e.Version, SignedExtensions: exts}
+}
+
+func modifierToJSON(m StorageFunctionModifierV0) string {
+	switch {
+	case m.IsOptional:
+		return "Optional"
+	case m.IsDefault:
+		return "Default"
+	default:
+		return "Required"
+	}
+}
+
+func hasherToJSON(h StorageHasher) string {
+	switch {
+	case h.IsBlake2_128:
+		return "Blake2_128"
+	case h.IsBlake2_256:
+		return "Blake2_256"
+	case h.IsTwox128:
+		return "Twox128"
+	case h.IsTwox256:
+		return "Twox256"
+	default:
+		return "Twox64Concat"
+	}
+}
+
+func hasherV10ToJSON(h StorageHasherV10) string {
+	switch {
+	case h.IsBlake2_128:
+		return "Blake2_128"
+	case h.IsBlake2_256:
+		return "Blake2_256"
+	case h.IsBlake2_128Concat:
+		return "Blake2_128Concat"
+	case h.IsTwox128:
+		return "Twox128"
+	case h.IsTwox256:
+		return "Twox256"
+	case h.IsTwox64Concat:
+		return "Twox64Concat"
+	default:
+		return "Identity"
+	}
+}
+
+func hashersV10ToJSON(hashers []StorageHasherV10) []string {
+	res := make([]string, len(hashers))
+	for i, h := range hashers {
+		res[i] = hasherV10ToJSON(h)
+	}
+	return res
+}
+
+/* V14 - V15 */
+
+type metadataV14JSON struct {
+	Lookup    lookupJSON       `json:"lookup"`
+	Pallets   []palletV14JSON  `json:"pallets"`
+	Extrinsic extrinsicV14JSON `json:"extrinsic"`
+	Type      int64            `json:"type"`
+}
+
+type metadataV15JSON struct {
+	Lookup     lookupJSON         `json:"lookup"`
+	Pallets    []palletV15JSON    `json:"pallets"`
+	Extrinsic  extrinsicV15JSON   `json:"extrinsic"`
+	Type       int64              `json:"type"`
+	APIs       []runtimeAPIJSON   `json:"apis"`
+	OuterEnums outerEnumsJSON     `json:"outerEnums"`
+	Custom     customMetadataJSON `json:"custom"`
+}
+
+type lookupJSON struct {
+	Types []portableTypeJSON `json:"types"`
+}
+
+type portableTypeJSON struct {
+	ID   int64       `json:"id"`
+	Type si1TypeJSON `json:"type"`
+}
+
+type si1TypeJSON struct {
+	Path   []string               `json:"path"`
+	Params []si1TypeParamJSON     `json:"params"`
+	Def    map[string]interface{} `json:"def"`
+	Docs   []string               `json:"docs"`
+}
+
+type si1TypeParamJSON struct {
+	Name string `json:"name"`
+	Type *int64 `json:"type"`
+}
+
+type si1FieldJSON struct {
+	Name     *string  `json:"name"`
+	Type     int64    `json:"type"`
+	TypeName *string  `json:"typeName"`
+	Docs     []string `json:"docs"`
+}
+
+type si1VariantJSON struct {
+	Name   string         `json:"name"`
+	Fields []si1FieldJSON `json:"fields"`
+	Index  uint8          `json:"index"`
+	Docs   []string       `json:"docs"`
+}
+
+type palletV14JSON struct {
+	Name      string            `json:"name"`
+	Storage   *storageV14JSON   `json:"storage"`
+	Calls     *typeRefJSON      `json:"calls"`
+	Events    *typeRefJSON      `json:"events"`
+	Constants []constantV14JSON `json:"constants"`
+	Errors    *typeRefJSON      `json:"errors"`
+	Index     uint8             `json:"index"`
+}
+
+type palletV15JSON struct {
+	palletV14JSON
+	Docs []string `json:"docs"`
+}
+
+type typeRefJSON struct {
+	Type int64 `json:"type"`
+}
+
+type storageV14JSON struct {
+	Prefix string               `json:"prefix"`
+	Items  []storageItemV14JSON `json:"items"`
+}
+
+type storageItemV14JSON struct {
+	Name     string                 `json:"name"`
+	Modifier string                 `json:"modifier"`
+	Type     map[string]interface{} `json:"type"`
+	Fallback string                 `json:"fallback"`
+	Docs     []string               `json:"docs"`
+}
+
+type mapTypeV14JSON struct {
+	Hashers []string `json:"hashers"`
+	Key     int64    `json:"key"`
+	Value   int64    `json:"value"`
+}
+
+type constantV14JSON struct {
+	Name  string   `json:"name"`
+	Type  int64    `json:"type"`
+	Value string   `json:"value"`
+	Docs  []string `json:"docs"`
+}
+
+type extrinsicV14JSON struct {
+	Type             int64                 `json:"type"`
+	Version          uint8                 `json:"version"`
+	SignedExtensions []signedExtensionJSON `json:"signedExtensions"`
+}
+
+type extrinsicV15JSON struct {
+	Version          uint8                 `json:"version"`
+	AddressType      int64                 `json:"addressType"`
+	CallType         int64                 `json:"callType"`
+	SignatureType    int64                 `json:"signatureType"`
+	ExtraType        int64                 `json:"extraType"`
+	SignedExtensions []signedExtensionJSON `json:"signedExtensions"`
+}
+
+type signedExtensionJSON struct {
+	Identifier       string `json:"identifier"`
+	Type             int64  `json:"type"`
+	AdditionalSigned int64  `json:"additionalSigned"`
+}
+
+type runtimeAPIJSON struct {
+	Name    string                 `json:"name"`
+	Methods []runtimeAPIMethodJSON `json:"methods"`
+	Docs    []string               `json:"docs"`
+}
+
+type runtimeAPIMethodJSON struct {
+	Name   string                      `json:"name"`
+	Inputs []runtimeAPIMethodParamJSON `json:"inputs"`
+	Output int64                       `json:"output"`
+	Docs   []string                    `json:"docs"`
+}
+
+type runtimeAPIMethodParamJSON struct {
+	Name string `json:"name"`
+	Type int64  `json:"type"`
+}
+
+type outerEnumsJSON struct {
+	CallType  int64 `json:"callType"`
+	EventType int64 `json:"eventType"`
+	ErrorType int64 `json:"errorType"`
+}
+
+type customMetadataJSON struct {
+	Map map[string]customValueJSON `json:"map"`
+}
+
+type customValueJSON struct {
+	Type  int64  `json:"type"`
+	Value string `json:"value"`
+}
+
+func metadataV14ToJSON(m *MetadataV14) metadataV14JSON {
+	pallets := make([]palletV14JSON, len(m.Pallets))
+	for i, p := range m.Pallets {
+		pallets[i] = palletV14ToJSON(p)
+	}
+	return metadataV14JSON{
+		Lookup:  lookupToJSON(m.Lookup),
+		Pallets: pallets,
+		Extrinsic: extrinsicV14JSON{
+			Type:             typeIDToJSON(m.Extrinsic.Type),
+			Version:          uint8(m.Extrinsic.Version),
+			SignedExtensions: signedExtensionsToJSON(m.Extrinsic.SignedExtensions),
+		},
+		Type: typeIDToJSON(m.Type),
+	}
+}
+
+func metadataV15ToJSON(m *MetadataV15) metadataV15JSON {
+	pallets := make([]palletV15JSON, len(m.Pallets))
+	for i, p := range m.Pallets {
+		pallets[i] = palletV15JSON{
+			palletV14JSON: palletV14ToJSON(PalletMetadataV14{
+				Name:       p.Name,
+				HasStorage: p.HasStorage,
+				Storage:    p.Storage,
+				HasCalls:   p.HasCalls,
+				Calls:      p.Calls,
+				HasEvents:  p.HasEvents,
+				Events:     p.Events,
+				Constants:  p.Constants,
+				HasErrors:  p.HasErrors,
+				Errors:     p.Errors,
+				Index:      p.Index,
+			}),
+			Docs: textsToJSON(p.Docs),
+		}
+	}
+
+	apis := make([]runtimeAPIJSON, len(m.APIs))
+	for i, api := range m.APIs {
+		methods := make([]runtimeAPIMethodJSON, len(api.Methods))
+		for j, method := range api.Methods {
+			inputs := make([]runtimeAPIMethodParamJSON, len(method.Inputs))
+			for k, in := range method.Inputs {
+				inputs[k] = runtimeAPIMethodParamJSON{Name: string(in.Name), Type: typeIDToJSON(in.Type)}
+			}
+			methods[j] = runtimeAPIMethodJSON{
+				Name:   string(method.Name),
+				Inputs: inputs,
+				Output: typeIDToJSON(method.Output),
+				Docs:   textsToJSON(method.Docs),
+			}
+		}
+		apis[i] = runtimeAPIJSON{Name: string(api.Name), Methods: methods, Docs: textsToJSON(api.Docs)}
+	}
+
+	custom := make(map[string]customValueJSON, len(m.Custom.Map))
+	for _, c := range m.Custom.Map {
+		custom[string(c.Name)] = customValueJSON{Type: typeIDToJSON(c.Type), Value: HexEncodeToString(c.Value)}
+	}
+
+	return metadataV15JSON{
+		Lookup:  lookupToJSON(m.Lookup),
+		Pallets: pallets,
+		Extrinsic: extrinsicV15JSON{
+			Version:          uint8(m.Extrinsic.Version),
+			AddressType:      typeIDToJSON(m.Extrinsic.AddressType),
+			CallType:         typeIDToJSON(m.Extrinsic.CallType),
+			SignatureType:    typeIDToJSON(m.Extrinsic.SignatureType),
+			ExtraType:        typeIDToJSON(m.Extrinsic.ExtraType),
+			SignedExtensions: signedExtensionsToJSON(m.Extrinsic.SignedExtensions),
+		},
+		Type: typeIDToJSON(m.Type),
+		APIs: apis,
+		OuterEnums: outerEnumsJSON{
+			CallType:  typeIDToJSON(m.OuterEnums.CallType),
+			EventType: typeIDToJSON(m.OuterEnums.EventType),
+			ErrorType: typeIDToJSON(m.OuterEnums.ErrorType),
+		},
+		Custom: customMetadataJSON{Map: custom},
+	}
+}
+
+func palletV14ToJSON(p PalletMetadataV14) palletV14JSON {
+	res := palletV14JSON{
+		Name:      string(p.Name),
+		Constants: make([]constantV14JSON, len(p.Constants)),
+		Index:     uint8(p.Index),
+	}
+
+	if p.HasStorage {
+		items := make([]storageItemV14JSON, len(p.Storage.Items))
+		for i, item := range p.Storage.Items {
+			var typ map[string]interface{}
+			if item.Type.IsMap {
+				typ = map[string]interface{}{"map": mapTypeV14JSON{
+					Hashers: hashersV10ToJSON(item.Type.AsMap.Hashers),
+					Key:     typeIDToJSON(item.Type.AsMap.Key),
+					Value:   typeIDToJSON(item.Type.AsMap.Value),
+				}}
+			} else {
+				typ = map[string]interface{}{"plain": typeIDToJSON(item.Type.AsPlainType)}
+			}
+			items[i] = storageItemV14JSON{
+				Name:     string(item.Name),
+				Modifier: modifierToJSON(item.Modifier),
+				Type:     typ,
+				Fallback: HexEncodeToString(item.Fallback),
+				Docs:     textsToJSON(item.Documentation),
+			}
+		}
+		res.Storage = &storageV14JSON{Prefix: string(p.Storage.Prefix), Items: items}
+	}
+	if p.HasCalls {
+		res.Calls = &typeRefJSON{Type: typeIDToJSON(p.Calls.Type)}
+	}
+	if p.HasEvents {
+		res.Events = &typeRefJSON{Type: typeIDToJSON(p.Events.Type)}
+	}
+	for i, c := range p.Constants {
+		res.Constants[i] = constantV14JSON{
+			Name:  string(c.Name),
+			Type:  typeIDToJSON(c.Type),
+			Value: HexEncodeToString(c.Value),
+			Docs:  textsToJSON(c.Docs),
+		}
+	}
+	if p.HasErrors {
+		res.Errors = &typeRefJSON{Type: typeIDToJSON(p.Errors.Type)}
+	}
+	return res
+}
+
+func signedExtensionsToJSON(exts []SignedExtensionMetadataV14) []signedExtensionJSON {
+	res := make([]signedExtensionJSON, len(exts))
+	for i, ext := range exts {
+		res[i] = signedExtensionJSON{
+			Identifier:       string(ext.Identifier),
+			Type:             typeIDToJSON(ext.Type),
+			AdditionalSigned: typeIDToJSON(ext.AdditionalSigned),
+		}
+	}
+	return res
+}
+
+func lookupToJSON(lookup PortableRegistryV14) lookupJSON {
+	types := make([]portableTypeJSON, len(lookup.Types))
+	for i, t := range lookup.Types {
+		params := make([]si1TypeParamJSON, len(t.Type.Params))
+		for j, p := range t.Type.Params {
+			params[j] = si1TypeParamJSON{Name: string(p.Name)}
+			if p.HasType {
+				id := typeIDToJSON(p.Type)
+				params[j].Type = &id
+			}
+		}
+		types[i] = portableTypeJSON{
+			ID: typeIDToJSON(t.ID),
+			Type: si1TypeJSON{
+				Path:   textsToJSON(t.Type.Path),
+				Params: params,
+				Def:    typeDefToJSON(t.Type.Def),
+				Docs:   textsToJSON(t.Type.Docs),
+			},
+		}
+	}
+	return lookupJSON{Types: types}
+}
+
+func typeDefToJSON(def Si1TypeDef) map[string]interface{} {
+	switch {
+	case def.IsComposite:
+		return map[string]interface{}{"composite": map[string]interface{}{
+			"fields": fieldsToJSON(def.Composite.Fields),
+		}}
+	case def.IsVariant:
+		variants := make([]si1VariantJSON, len(def.Variant.Variants))
+		for i, v := range def.Variant.Variants {
+			variants[i] = si1VariantJSON{
+				Name:   string(v.Name),
+				Fields: fieldsToJSON(v.Fields),
+				Index:  uint8(v.Index),
+				Docs:   textsToJSON(v.Docs),
+			}
+		}
+		return map[string]interface{}{"variant": map[string]interface{}{"variants": variants}}
+	case def.IsSequence:
+		return map[string]interface{}{"sequence": typeRefJSON{Type: typeIDToJSON(def.Sequence.Type)}}
+	case def.IsArray:
+		return map[string]interface{}{"array": map[string]interface{}{
+			"len":  uint32(def.Array.Len),
+			"type": typeIDToJSON(def.Array.Type),
+		}}
+	case def.IsTuple:
+		ids := make([]int64, len(def.Tuple))
+		for i, id := range def.Tuple {
+			ids[i] = typeIDToJSON(id)
+		}
+		return map[string]interface{}{"tuple": ids}
+	case def.IsPrimitive:
+		return map[string]interface{}{"primitive": primitiveToJSON(def.Primitive.Si0TypeDefPrimitive)}
+	case def.IsCompact:
+		return map[string]interface{}{"compact": typeRefJSON{Type: typeIDToJSON(def.Compact.Type)}}
+	case def.IsBitSequence:
+		return map[string]interface{}{"bitSequence": map[string]interface{}{
+			"bitStoreType": typeIDToJSON(def.BitSequence.BitStoreType),
+			"bitOrderType": typeIDToJSON(def.BitSequence.BitOrderType),
+		}}
+	default:
+		return map[string]interface{}{"historicMetaCompat": string(def.HistoricMetaCompat)}
+	}
+}
+
+func fieldsToJSON(fields []Si1Field) []si1FieldJSON {
+	res := make([]si1FieldJSON, len(fields))
+	for i, f := range fields {
+		res[i] = si1FieldJSON{Type: typeIDToJSON(f.Type), Docs: textsToJSON(f.Docs)}
+		if f.HasName {
+			name := string(f.Name)
+			res[i].Name = &name
+		}
+		if f.HasTypeName {
+			typeName := string(f.TypeName)
+			res[i].TypeName = &typeName
+		}
+	}
+	return res
+}
+
+// primitiveToJSON returns the name of the primitive as in the Rust enum, e.g. U32
+func primitiveToJSON(p Si0TypeDefPrimitive) string {
+	name, ok := primitiveNames[p]
+	if !ok {
+		return fmt.Sprintf("%v", uint8(p))
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func typeIDToJSON(id Si1LookupTypeID) int64 {
+	return id.Int64()
+}
+
+// textsToJSON converts docs and paths, which are encoded as empty arrays instead of null if empty
+func textsToJSON(texts []Text) []string {
+	res := make([]string, len(texts))
+	for i, t := range texts {
+		res[i] = string(t)
+	}
+	return res
+}
@@ -0,0 +1,136 @@
+package types_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// metadataToJSON encodes the metadata to JSON and decodes the content of the versioned metadata field
+func metadataToJSON(t *testing.T, meta *Metadata) map[string]interface{} {
+	bz, err := json.Marshal(meta)
+	assert.NoError(t, err)
+
+	var res struct {
+		MagicNumber uint32                            `json:"magicNumber"`
+		Metadata    map[string]map[string]interface{} `json:"metadata"`
+	}
+	err = json.Unmarshal(bz, &res)
+	assert.NoError(t, err)
+	assert.Equal(t, MagicNumber, res.MagicNumber)
+	assert.Len(t, res.Metadata, 1)
+	for _, v := range res.Metadata {
+		return v
+	}
+	return nil
+}
+
+func TestMetadata_MarshalJSON_V14(t *testing.T) {
+	meta := DecodedMetadataV14Example()
+	bz, err := json.Marshal(meta)
+	assert.NoError(t, err)
+	assert.Contains(t, string(bz), `{"magicNumber":1635018093,"metadata":{"v14":{"lookup":{"types":[{"id":0,"type":`+
+		`{"path":["sp_core","crypto","AccountId32"],"params":[],"def":{"composite":{"fields":[{"name":null,"type":1,`+
+		`"typeName":"[u8; 32]","docs":[]}]}},"docs":[]}},{"id":1,"type":{"path":[],"params":[],`+
+		`"def":{"array":{"len":32,"type":2}},"docs":[]}},{"id":2,"type":{"path":[],"params":[],`+
+		`"def":{"primitive":"U8"},"docs":[]}}`)
+
+	v14 := metadataToJSON(t, meta)
+	assert.Equal(t, float64(575), v14["extrinsic"].(map[string]interface{})["type"])
+
+	pallets := v14["pallets"].([]interface{})
+	system := pallets[0].(map[string]interface{})
+	assert.Equal(t, "System", system["name"])
+	assert.Equal(t, float64(0), system["index"])
+	assert.Equal(t, map[string]interface{}{"type": float64(109)}, system["calls"])
+
+	account := system["storage"].(map[string]interface{})["items"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "Account", account["name"])
+	assert.Equal(t, "Default", account["modifier"])
+	assert.Equal(t, map[string]interface{}{"map": map[string]interface{}{
+		"hashers": []interface{}{"Blake2_128Concat"},
+		"key":     float64(0),
+		"value":   float64(3),
+	}}, account["type"])
+
+	for _, p := range pallets {
+		if p.(map[string]interface{})["name"] == "Timestamp" {
+			// pallets without events or errors
+			assert.Nil(t, p.(map[string]interface{})["events"])
+		}
+	}
+}
+
+func TestMetadata_MarshalJSON_V15(t *testing.T) {
+	v15 := metadataToJSON(t, metadataV15FromV14(t))
+	system := v15["pallets"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "System", system["name"])
+	assert.NotNil(t, system["docs"])
+	assert.Contains(t, v15, "apis")
+	assert.Contains(t, v15, "outerEnums")
+}
+
+func TestMetadata_MarshalJSON_V13(t *testing.T) {
+	v13 := metadataToJSON(t, ExamplaryMetadataV13)
+	assert.Equal(t, map[string]interface{}{"version": float64(4), "signedExtensions": []interface{}{
+		"CheckSpecVersion", "CheckTxVersion", "CheckGenesis", "CheckMortality", "CheckNonce", "CheckWeight",
+		"ChargeTransactionPayment",
+	}}, v13["extrinsic"])
+
+	system := v13["modules"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "System", system["name"])
+	assert.Equal(t, float64(0), system["index"])
+
+	items := system["storage"].(map[string]interface{})["items"].([]interface{})
+	assert.Equal(t, map[string]interface{}{"plain": "u32"}, items[1].(map[string]interface{})["type"])
+	assert.Equal(t, "Optional", items[1].(map[string]interface{})["modifier"])
+
+	call := system["calls"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "fill_block", call["name"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "_ratio", "type": "Perbill"}}, call["args"])
+}
+
+func TestMetadata_MarshalJSON_V12(t *testing.T) {
+	var meta Metadata
+	err := DecodeFromHexString(ExamplaryMetadataV12PolkadotString, &meta)
+	assert.NoError(t, err)
+
+	v12 := metadataToJSON(t, &meta)
+	system := v12["modules"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "System", system["name"])
+	assert.Equal(t, float64(0), system["index"])
+	assert.Contains(t, v12, "extrinsic")
+}
+
+func TestMetadata_MarshalJSON_V9(t *testing.T) {
+	for _, meta := range []*Metadata{ExamplaryMetadataV9, ExamplaryMetadataV10} {
+		v := metadataToJSON(t, meta)
+		assert.NotContains(t, v, "extrinsic")
+
+		system := v["modules"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "System", system["name"])
+		assert.NotContains(t, system, "index")
+
+		nonce := system["storage"].(map[string]interface{})["items"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{
+			"name":     "AccountNonce",
+			"modifier": "Default",
+			"type": map[string]interface{}{"map": map[string]interface{}{
+				"hasher": "Blake2_256",
+				"key":    "T::AccountId",
+				"value":  "T::Index",
+				"linked": false,
+			}},
+			"fallback": "0x00000000",
+			"docs":     []interface{}{" Extrinsics nonce for accounts."},
+		}, nonce)
+	}
+}
+
+func TestMetadata_MarshalJSON_Unsupported(t *testing.T) {
+	_, err := json.Marshal(ExamplaryMetadataV4)
+	assert.EqualError(t, err, "json: error calling MarshalJSON for type *types.Metadata: "+
+		"JSON encoding of metadata version 4 is not supported")
+}
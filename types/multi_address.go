@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/Phala-Network/go-substrate-rpc-client/v3/scale"
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/ss58"
 )
 
 type MultiAddress struct {
@@ -36,6 +37,15 @@ func NewMultiAddressFromHexAccountID(str string) (MultiAddress, error) {
 	return NewMultiAddressFromAccountID(b), nil
 }
 
+// NewMultiAddressFromSS58 creates an Address from the AccountID encoded in the given SS58 address of any network
+func NewMultiAddressFromSS58(address string) (MultiAddress, error) {
+	_, accountID, err := ss58.DecodeAccountID(address)
+	if err != nil {
+		return MultiAddress{}, err
+	}
+	return NewMultiAddressFromAccountID(accountID), nil
+}
+
 func (m MultiAddress) Encode(encoder scale.Encoder) error {
 	var err error
 	switch {
@@ -34,3 +34,17 @@ func TestNewMultiAddressFromAccountID(t *testing.T) {
 		AsAddress20: [20]byte{},
 	})
 }
+
+func TestNewMultiAddressFromSS58(t *testing.T) {
+	addr, err := types.NewMultiAddressFromSS58(signature.TestKeyringPairAlice.Address)
+	assert.NoError(t, err)
+	assert.Equal(t, types.NewMultiAddressFromAccountID(signature.TestKeyringPairAlice.PublicKey), addr)
+
+	// the address of Alice on Polkadot
+	addr, err = types.NewMultiAddressFromSS58("15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6Sp5")
+	assert.NoError(t, err)
+	assert.Equal(t, types.NewMultiAddressFromAccountID(signature.TestKeyringPairAlice.PublicKey), addr)
+
+	_, err = types.NewMultiAddressFromSS58("5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQZ")
+	assert.EqualError(t, err, "invalid checksum of SS58 address 5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQZ")
+}
@@ -16,7 +16,14 @@
 
 package types
 
-import "github.com/Phala-Network/go-substrate-rpc-client/v3/scale"
+import (
+	"fmt"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/scale"
+)
+
+// EcdsaSignatureLength is the length of ecdsa signatures, which include the recovery id
+const EcdsaSignatureLength = 65
 
 // MultiSignature
 type MultiSignature struct {
@@ -25,7 +32,7 @@ type MultiSignature struct {
 	IsSr25519 bool      // 1:: Sr25519(Sr25519Signature)
 	AsSr25519 Signature // Sr25519Signature
 	IsEcdsa   bool      // 2:: Ecdsa(EcdsaSignature)
-	AsEcdsa   Bytes     // EcdsaSignature
+	AsEcdsa   Bytes     // EcdsaSignature, encoded without length prefix as it has EcdsaSignatureLength bytes
 }
 
 func (m *MultiSignature) Decode(decoder scale.Decoder) error {
@@ -43,7 +50,11 @@ func (m *MultiSignature) Decode(decoder scale.Decoder) error {
 		err = decoder.Decode(&m.AsSr25519)
 	case 2:
 		m.IsEcdsa = true
-		err = decoder.Decode(&m.AsEcdsa)
+		m.AsEcdsa = make(Bytes, EcdsaSignatureLength)
+		err = decoder.Read(m.AsEcdsa)
+		if err == nil {
+			err = checkEcdsaRecoveryID(m.AsEcdsa)
+		}
 	}
 
 	if err != nil {
@@ -63,8 +74,11 @@ func (m MultiSignature) Encode(encoder scale.Encoder) error {
 		err1 = encoder.PushByte(1)
 		err2 = encoder.Encode(m.AsSr25519)
 	case m.IsEcdsa:
+		if len(m.AsEcdsa) != EcdsaSignatureLength {
+			return fmt.Errorf("ecdsa signature must have %v bytes, got %v", EcdsaSignatureLength, len(m.AsEcdsa))
+		}
 		err1 = encoder.PushByte(2)
-		err2 = encoder.Encode(m.AsEcdsa)
+		err2 = encoder.Write(m.AsEcdsa)
 	}
 
 	if err1 != nil {
@@ -76,3 +90,14 @@ func (m MultiSignature) Encode(encoder scale.Encoder) error {
 
 	return nil
 }
+
+// checkEcdsaRecoveryID checks the recovery id of an ecdsa signature, which the runtime accepts as 0 to 3 or 27 to 30.
+// Signatures that have been encoded with a length prefix, as this package did before, are read shifted by the two
+// bytes of the prefix and are most likely rejected here.
+func checkEcdsaRecoveryID(sig []byte) error {
+	v := sig[EcdsaSignatureLength-1]
+	if v > 3 && (v < 27 || v > 30) {
+		return fmt.Errorf("invalid ecdsa recovery id %v, the signature may have been encoded with a length prefix", v)
+	}
+	return nil
+}
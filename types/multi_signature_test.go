@@ -20,10 +20,12 @@ import (
 	"testing"
 
 	. "github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
 )
 
 var testMultiSig1 = MultiSignature{IsEd25519: true, AsEd25519: NewSignature(hash64)}
 var testMultiSig2 = MultiSignature{IsSr25519: true, AsSr25519: NewSignature(hash64)}
+var testMultiSig3 = MultiSignature{IsEcdsa: true, AsEcdsa: NewBytes(append(hash64, 0x1b))}
 
 func TestMultiSignature_EncodeDecode(t *testing.T) {
 	assertRoundtrip(t, testMultiSig1)
@@ -43,3 +45,31 @@ func TestMultiSignature_Decode(t *testing.T) {
 		{MustHexDecodeString("0x0101020304050607080900010203040506070809000102030405060708090001020304050607080900010203040506070809000102030405060708090001020304"), testMultiSig2}, //nolint:lll
 	})
 }
+
+func TestMultiSignature_Ecdsa(t *testing.T) {
+	// ecdsa signatures have a fixed length and are encoded without length prefix
+	enc := MustHexDecodeString("0x02010203040506070809000102030405060708090001020304050607080900010203040506070809000102030405060708090001020304050607080900010203041b") //nolint:lll
+	assertRoundtrip(t, testMultiSig3)
+	assertEncode(t, []encodingAssert{{testMultiSig3, enc}})
+	assertDecode(t, []decodingAssert{{enc, testMultiSig3}})
+
+	_, err := EncodeToBytes(MultiSignature{IsEcdsa: true, AsEcdsa: NewBytes(hash64)})
+	assert.EqualError(t, err, "ecdsa signature must have 65 bytes, got 64")
+}
+
+func TestMultiSignature_EcdsaLengthPrefixed(t *testing.T) {
+	sig := make([]byte, EcdsaSignatureLength)
+	for i := range sig {
+		sig[i] = 0xaa
+	}
+	sig[EcdsaSignatureLength-1] = 1
+	assertRoundtrip(t, MultiSignature{IsEcdsa: true, AsEcdsa: sig})
+
+	// before the fixed length encoding, ecdsa signatures were encoded as Bytes with a compact length prefix, which
+	// shifts the recovery id out of the 65 bytes that are read
+	old, err := EncodeToBytes(NewBytes(sig))
+	assert.NoError(t, err)
+	var decoded MultiSignature
+	err = DecodeFromBytes(append([]byte{2}, old...), &decoded)
+	assert.EqualError(t, err, "invalid ecdsa recovery id 170, the signature may have been encoded with a length prefix")
+}
@@ -0,0 +1,253 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/scale"
+)
+
+// SignedExtensionEncoder returns the values a signed extension adds to an extrinsic (extra) and the values it only
+// adds to the signing payload (additional signed). Both are SCALE encoded in the given order.
+type SignedExtensionEncoder func(o SignatureOptions) (extra, additionalSigned []interface{}, err error)
+
+var (
+	signedExtensionEncodersMu sync.RWMutex
+	signedExtensionEncoders   = map[string]SignedExtensionEncoder{
+		"CheckSpecVersion": func(o SignatureOptions) ([]interface{}, []interface{}, error) {
+			return nil, []interface{}{o.SpecVersion}, nil
+		},
+		"CheckTxVersion": func(o SignatureOptions) ([]interface{}, []interface{}, error) {
+			return nil, []interface{}{o.TransactionVersion}, nil
+		},
+		"CheckGenesis": func(o SignatureOptions) ([]interface{}, []interface{}, error) {
+			return nil, []interface{}{o.GenesisHash}, nil
+		},
+		"CheckMortality": encodeCheckMortality,
+		"CheckEra":       encodeCheckMortality,
+		"CheckNonce": func(o SignatureOptions) ([]interface{}, []interface{}, error) {
+			return []interface{}{o.Nonce}, nil, nil
+		},
+		"ChargeTransactionPayment": func(o SignatureOptions) ([]interface{}, []interface{}, error) {
+			return []interface{}{o.Tip}, nil, nil
+		},
+		"ChargeAssetTxPayment": func(o SignatureOptions) ([]interface{}, []interface{}, error) {
+			if o.AssetID == nil {
+				return []interface{}{o.Tip, false}, nil, nil
+			}
+			return []interface{}{o.Tip, true, o.AssetID}, nil, nil
+		},
+		"CheckMetadataHash": func(o SignatureOptions) ([]interface{}, []interface{}, error) {
+			// the mode is enabled (1) if the metadata hash is included in the signing payload
+			if ok, _ := o.MetadataHash.Unwrap(); ok {
+				return []interface{}{U8(1)}, []interface{}{o.MetadataHash}, nil
+			}
+			return []interface{}{U8(0)}, []interface{}{o.MetadataHash}, nil
+		},
+	}
+)
+
+func encodeCheckMortality(o SignatureOptions) ([]interface{}, []interface{}, error) {
+	era := o.Era
+	if !o.Era.IsMortalEra {
+		era = ExtrinsicEra{IsImmortalEra: true}
+	}
+	return []interface{}{era}, []interface{}{o.BlockHash}, nil
+}
+
+// RegisterSignedExtension registers the encoder for the signed extension with the given identifier, as found in
+// ExtrinsicV14.SignedExtensions, replacing any encoder registered before. Signed extensions without an encoder can
+// only be used if they add no data, such as CheckWeight.
+func RegisterSignedExtension(identifier string, encoder SignedExtensionEncoder) {
+	signedExtensionEncodersMu.Lock()
+	defer signedExtensionEncodersMu.Unlock()
+	signedExtensionEncoders[identifier] = encoder
+}
+
+// UnregisterSignedExtension removes the encoder registered for the signed extension with the given identifier
+func UnregisterSignedExtension(identifier string) {
+	signedExtensionEncodersMu.Lock()
+	defer signedExtensionEncodersMu.Unlock()
+	delete(signedExtensionEncoders, identifier)
+}
+
+func findSignedExtensionEncoder(identifier string) (SignedExtensionEncoder, bool) {
+	signedExtensionEncodersMu.RLock()
+	defer signedExtensionEncodersMu.RUnlock()
+	encoder, ok := signedExtensionEncoders[identifier]
+	return encoder, ok
+}
+
+// SignedExtensionsData holds the encoded data of all signed extensions of a runtime, in the order of the metadata
+type SignedExtensionsData struct {
+	// Extra is part of the extrinsic signature and of the signing payload
+	Extra []byte
+	// AdditionalSigned is only part of the signing payload
+	AdditionalSigned []byte
+}
+
+// EncodeSignedExtensions encodes the signed extensions listed in the extrinsic metadata with the given options
+func (m *MetadataV14) EncodeSignedExtensions(o SignatureOptions) (SignedExtensionsData, error) {
+	return encodeSignedExtensions(m.lookup(), m.Extrinsic.SignedExtensions, o)
+}
+
+// EncodeSignedExtensions encodes the signed extensions listed in the extrinsic metadata with the given options
+func (m *MetadataV15) EncodeSignedExtensions(o SignatureOptions) (SignedExtensionsData, error) {
+	return encodeSignedExtensions(m.lookup(), m.Extrinsic.SignedExtensions, o)
+}
+
+// DecodeExtrinsic decodes an extrinsic whose signature carries the extra data of the signed extensions listed in the
+// extrinsic metadata. The extra data is kept in ExtrinsicSignatureV4.Extra, the era, nonce and tip are read from the
+// CheckMortality, CheckNonce and ChargeTransactionPayment or ChargeAssetTxPayment extensions.
+func (m *MetadataV14) DecodeExtrinsic(bz []byte) (Extrinsic, error) {
+	return decodeExtrinsic(m.lookup(), m.Extrinsic.SignedExtensions, bz)
+}
+
+// DecodeExtrinsic decodes an extrinsic with the signed extensions of the metadata, see MetadataV14.DecodeExtrinsic
+func (m *MetadataV15) DecodeExtrinsic(bz []byte) (Extrinsic, error) {
+	return decodeExtrinsic(m.lookup(), m.Extrinsic.SignedExtensions, bz)
+}
+
+func decodeExtrinsic(lookup map[int64]*Si1Type, extensions []SignedExtensionMetadataV14, bz []byte) (Extrinsic,
+	error) {
+	r := bytes.NewReader(bz)
+	decoder := scale.NewDecoder(r)
+	offset := func() int { return len(bz) - r.Len() }
+
+	var e Extrinsic
+	_, err := decoder.DecodeUintCompact()
+	if err != nil {
+		return Extrinsic{}, err
+	}
+
+	err = decoder.Decode(&e.Version)
+	if err != nil {
+		return Extrinsic{}, err
+	}
+
+	if e.IsSigned() {
+		if e.Type() != ExtrinsicVersion4 {
+			return Extrinsic{}, fmt.Errorf("unsupported extrinsic version: %v (isSigned: %v, type: %v)", e.Version,
+				e.IsSigned(), e.Type())
+		}
+
+		err = decoder.Decode(&e.Signature.Signer)
+		if err != nil {
+			return Extrinsic{}, err
+		}
+
+		err = decoder.Decode(&e.Signature.Signature)
+		if err != nil {
+			return Extrinsic{}, err
+		}
+
+		start := offset()
+		for _, ext := range extensions {
+			extStart := offset()
+			_, err = DecodeDynamicValue(*decoder, lookup, ext.Type)
+			if err != nil {
+				return Extrinsic{}, fmt.Errorf("failed to decode signed extension %v: %v", ext.Identifier, err)
+			}
+
+			err = decodeSignedExtensionExtra(string(ext.Identifier), bz[extStart:offset()], &e.Signature)
+			if err != nil {
+				return Extrinsic{}, fmt.Errorf("failed to decode signed extension %v: %v", ext.Identifier, err)
+			}
+		}
+		// Extra is never nil for signatures with the extra data of the metadata, see ExtrinsicSignatureV4
+		e.Signature.Extra = append([]byte{}, bz[start:offset()]...)
+	}
+
+	err = decoder.Decode(&e.Method)
+	if err != nil {
+		return Extrinsic{}, err
+	}
+
+	return e, nil
+}
+
+// decodeSignedExtensionExtra sets the era, nonce or tip of the signature from the extra data of the extensions that
+// add them
+func decodeSignedExtensionExtra(identifier string, extra []byte, s *ExtrinsicSignatureV4) error {
+	decoder := scale.NewDecoder(bytes.NewReader(extra))
+	switch identifier {
+	case "CheckMortality", "CheckEra":
+		return decoder.Decode(&s.Era)
+	case "CheckNonce":
+		return decoder.Decode(&s.Nonce)
+	case "ChargeTransactionPayment", "ChargeAssetTxPayment":
+		return decoder.Decode(&s.Tip)
+	default:
+		return nil
+	}
+}
+
+func encodeSignedExtensions(lookup map[int64]*Si1Type, extensions []SignedExtensionMetadataV14,
+	o SignatureOptions) (SignedExtensionsData, error) {
+	var extra, additional bytes.Buffer
+	extraEncoder, additionalEncoder := scale.NewEncoder(&extra), scale.NewEncoder(&additional)
+
+	for _, ext := range extensions {
+		encoder, ok := findSignedExtensionEncoder(string(ext.Identifier))
+		if !ok {
+			if isZeroSizedType(lookup, ext.Type) && isZeroSizedType(lookup, ext.AdditionalSigned) {
+				continue
+			}
+			return SignedExtensionsData{}, fmt.Errorf("no encoder registered for signed extension %v",
+				ext.Identifier)
+		}
+
+		extraValues, additionalValues, err := encoder(o)
+		if err != nil {
+			return SignedExtensionsData{}, fmt.Errorf("failed to encode signed extension %v: %v", ext.Identifier,
+				err)
+		}
+		for _, v := range extraValues {
+			err = extraEncoder.Encode(v)
+			if err != nil {
+				return SignedExtensionsData{}, fmt.Errorf("failed to encode signed extension %v: %v",
+					ext.Identifier, err)
+			}
+		}
+		for _, v := range additionalValues {
+			err = additionalEncoder.Encode(v)
+			if err != nil {
+				return SignedExtensionsData{}, fmt.Errorf("failed to encode signed extension %v: %v",
+					ext.Identifier, err)
+			}
+		}
+	}
+
+	return SignedExtensionsData{Extra: extra.Bytes(), AdditionalSigned: additional.Bytes()}, nil
+}
+
+// isZeroSizedType returns true if values of the registry type typeID are always encoded as zero bytes, e.g. ()
+func isZeroSizedType(lookup map[int64]*Si1Type, typeID Si1LookupTypeID) bool {
+	typ, ok := lookup[typeID.Int64()]
+	if !ok {
+		return false
+	}
+	def := typ.Def
+
+	switch {
+	case def.IsTuple:
+		for _, id := range def.Tuple {
+			if !isZeroSizedType(lookup, id) {
+				return false
+			}
+		}
+		return true
+	case def.IsComposite:
+		for _, f := range def.Composite.Fields {
+			if !isZeroSizedType(lookup, f.Type) {
+				return false
+			}
+		}
+		return true
+	case def.IsArray:
+		return def.Array.Len == 0 || isZeroSizedType(lookup, def.Array.Type)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,215 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/signature"
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var testSignatureOptions = SignatureOptions{
+	Era:                ExtrinsicEra{IsMortalEra: true, AsMortalEra: MortalEra{First: 0x95, Second: 0x00}},
+	Nonce:              NewUCompactFromUInt(3),
+	Tip:                NewUCompactFromUInt(10),
+	SpecVersion:        9100,
+	GenesisHash:        NewHash([]byte{1, 2, 3}),
+	BlockHash:          NewHash([]byte{4, 5, 6}),
+	TransactionVersion: 7,
+}
+
+func TestMetadata_EncodeSignedExtensions(t *testing.T) {
+	meta := DecodedMetadataV14Example()
+	o := testSignatureOptions
+
+	data, err := meta.EncodeSignedExtensions(o)
+	assert.NoError(t, err)
+
+	// the extensions of the example runtime are the ones ExtrinsicPayloadV4 is built for
+	legacy, err := EncodeToBytes(ExtrinsicPayloadV4{
+		ExtrinsicPayloadV3: ExtrinsicPayloadV3{
+			Method:      []byte{0, 1},
+			Era:         o.Era,
+			Nonce:       o.Nonce,
+			Tip:         o.Tip,
+			SpecVersion: o.SpecVersion,
+			GenesisHash: o.GenesisHash,
+			BlockHash:   o.BlockHash,
+		},
+		TransactionVersion: o.TransactionVersion,
+	})
+	assert.NoError(t, err)
+	payload, err := EncodeToBytes(ExtrinsicPayloadDynamic{Method: []byte{0, 1}, Extensions: data})
+	assert.NoError(t, err)
+	assert.Equal(t, legacy, payload)
+
+	extra, err := EncodeToBytes(struct {
+		Era   ExtrinsicEra
+		Nonce UCompact
+		Tip   UCompact
+	}{o.Era, o.Nonce, o.Tip})
+	assert.NoError(t, err)
+	assert.Equal(t, extra, data.Extra)
+
+	// immortal extrinsics
+	o.Era = ExtrinsicEra{}
+	data, err = meta.EncodeSignedExtensions(o)
+	assert.NoError(t, err)
+	assert.Equal(t, byte(0), data.Extra[0])
+
+	v15, err := metadataV15FromV14(t).EncodeSignedExtensions(o)
+	assert.NoError(t, err)
+	assert.Equal(t, data, v15)
+
+	_, err = ExamplaryMetadataV13.EncodeSignedExtensions(o)
+	assert.EqualError(t, err, "signed extensions are not supported for metadata version 13")
+}
+
+func TestMetadata_EncodeSignedExtensions_Custom(t *testing.T) {
+	meta := DecodedMetadataV14Example()
+	// 32 is (), 4 is u32
+	unit, u32 := NewSi1LookupTypeIDFromUInt(32), NewSi1LookupTypeIDFromUInt(4)
+	meta.AsMetadataV14.Extrinsic.SignedExtensions = []SignedExtensionMetadataV14{
+		{Identifier: "CheckNonZeroSender", Type: unit, AdditionalSigned: unit},
+		{Identifier: "ChargeAssetTxPayment", Type: u32, AdditionalSigned: unit},
+		{Identifier: "CheckMetadataHash", Type: u32, AdditionalSigned: u32},
+		{Identifier: "TestCheckCustom", Type: u32, AdditionalSigned: u32},
+	}
+
+	o := testSignatureOptions
+	_, err := meta.EncodeSignedExtensions(o)
+	assert.EqualError(t, err, "no encoder registered for signed extension TestCheckCustom")
+
+	RegisterSignedExtension("TestCheckCustom", func(o SignatureOptions) ([]interface{}, []interface{}, error) {
+		return []interface{}{U32(0x01020304)}, []interface{}{o.SpecVersion}, nil
+	})
+	defer UnregisterSignedExtension("TestCheckCustom")
+
+	data, err := meta.EncodeSignedExtensions(o)
+	assert.NoError(t, err)
+	assert.Equal(t, SignedExtensionsData{
+		// tip, no asset id, metadata hash disabled, custom extra
+		Extra: []byte{40, 0, 0, 4, 3, 2, 1},
+		// no metadata hash, spec version
+		AdditionalSigned: []byte{0, 0x8c, 0x23, 0, 0},
+	}, data)
+
+	o.AssetID = U32(1984)
+	o.MetadataHash = NewOptionH256(NewH256([]byte{0xff}))
+	data, err = meta.EncodeSignedExtensions(o)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{40, 1, 0xc0, 0x07, 0, 0, 1, 4, 3, 2, 1}, data.Extra)
+	assert.Equal(t, append(append([]byte{1, 0xff}, make([]byte, 31)...), 0x8c, 0x23, 0, 0), data.AdditionalSigned)
+}
+
+func TestExtrinsic_SignWithMetadata(t *testing.T) {
+	meta := DecodedMetadataV14Example()
+	c, err := NewCall(meta, "System.remark", NewBytes([]byte("hello")))
+	assert.NoError(t, err)
+
+	o := testSignatureOptions
+	ext := NewExtrinsic(c)
+	err = ext.SignWithMetadata(meta, signature.TestKeyringPairAlice, o)
+	assert.NoError(t, err)
+	assert.True(t, ext.IsSigned())
+
+	mb, err := EncodeToBytes(c)
+	assert.NoError(t, err)
+	data, err := meta.EncodeSignedExtensions(o)
+	assert.NoError(t, err)
+	payload, err := EncodeToBytes(ExtrinsicPayloadDynamic{Method: mb, Extensions: data})
+	assert.NoError(t, err)
+	ok, err := signature.Verify(payload, ext.Signature.Signature.AsSr25519[:], signature.TestKeyringPairAlice.URI)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// the extrinsic is encoded like one signed with Sign
+	enc, err := EncodeToHexString(ext)
+	assert.NoError(t, err)
+	legacy := NewExtrinsic(c)
+	err = legacy.Sign(signature.TestKeyringPairAlice, o)
+	assert.NoError(t, err)
+	legacy.Signature.Signature = ext.Signature.Signature
+	legacyEnc, err := EncodeToHexString(legacy)
+	assert.NoError(t, err)
+	assert.Equal(t, legacyEnc, enc)
+
+	var decoded Extrinsic
+	err = DecodeFromHexString(enc, &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, legacy, decoded)
+}
+
+func TestMetadata_DecodeExtrinsic(t *testing.T) {
+	meta := DecodedMetadataV14Example()
+	c, err := NewCall(meta, "System.remark", NewBytes([]byte("hello")))
+	assert.NoError(t, err)
+
+	// 4 is u32
+	extensions := &meta.AsMetadataV14.Extrinsic.SignedExtensions
+	*extensions = append(*extensions, SignedExtensionMetadataV14{
+		Identifier: "TestCheckCustom", Type: NewSi1LookupTypeIDFromUInt(4), AdditionalSigned: NewSi1LookupTypeIDFromUInt(4),
+	})
+	RegisterSignedExtension("TestCheckCustom", func(o SignatureOptions) ([]interface{}, []interface{}, error) {
+		return []interface{}{U32(0x01020304)}, []interface{}{o.SpecVersion}, nil
+	})
+	defer UnregisterSignedExtension("TestCheckCustom")
+
+	ext := NewExtrinsic(c)
+	err = ext.SignWithMetadata(meta, signature.TestKeyringPairAlice, testSignatureOptions)
+	assert.NoError(t, err)
+	enc, err := EncodeToBytes(ext)
+	assert.NoError(t, err)
+
+	decoded, err := meta.DecodeExtrinsic(enc)
+	assert.NoError(t, err)
+	assert.Equal(t, ext, decoded)
+
+	// Extrinsic.Decode only knows the era, nonce and tip and reads the custom extra data as part of the call
+	var legacy Extrinsic
+	err = DecodeFromBytes(enc, &legacy)
+	assert.NoError(t, err)
+	assert.Nil(t, legacy.Signature.Extra)
+	assert.NotEqual(t, ext.Method, legacy.Method)
+
+	_, err = ExamplaryMetadataV13.DecodeExtrinsic(enc)
+	assert.EqualError(t, err, "signed extensions are not supported for metadata version 13")
+}
+
+func TestMetadata_DecodeExtrinsic_EmptyExtra(t *testing.T) {
+	meta := DecodedMetadataV14Example()
+	c, err := NewCall(meta, "System.remark", NewBytes([]byte("hello")))
+	assert.NoError(t, err)
+
+	// 32 is (), 4 is u32, the extensions add no extra data
+	meta.AsMetadataV14.Extrinsic.SignedExtensions = []SignedExtensionMetadataV14{
+		{Identifier: "CheckNonZeroSender", Type: NewSi1LookupTypeIDFromUInt(32),
+			AdditionalSigned: NewSi1LookupTypeIDFromUInt(32)},
+		{Identifier: "CheckSpecVersion", Type: NewSi1LookupTypeIDFromUInt(32),
+			AdditionalSigned: NewSi1LookupTypeIDFromUInt(4)},
+	}
+
+	ext := NewExtrinsic(c)
+	err = ext.SignWithMetadata(meta, signature.TestKeyringPairAlice, testSignatureOptions)
+	assert.NoError(t, err)
+	// empty extra data is encoded instead of the era, nonce and tip
+	assert.Equal(t, []byte{}, ext.Signature.Extra)
+
+	enc, err := EncodeToBytes(ext)
+	assert.NoError(t, err)
+	withoutExtra := ext
+	withoutExtra.Signature.Extra = nil
+	encWithoutExtra, err := EncodeToBytes(withoutExtra)
+	assert.NoError(t, err)
+	assert.NotEqual(t, encWithoutExtra, enc)
+
+	decoded, err := meta.DecodeExtrinsic(enc)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{}, decoded.Signature.Extra)
+	assert.Equal(t, ext.Method, decoded.Method)
+	assert.Equal(t, ext.Signature.Signature, decoded.Signature.Signature)
+
+	reencoded, err := EncodeToBytes(decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, enc, reencoded)
+}
@@ -0,0 +1,88 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/signature"
+)
+
+// Signer signs the payloads of extrinsics. Implement it to sign with keys that are not held in memory, such as
+// hardware, remote or KMS signers.
+type Signer interface {
+	// PublicKey returns the public key of the signer
+	PublicKey() []byte
+	// MultiAddress returns the address of the signer that is included in signed extrinsics
+	MultiAddress() MultiAddress
+	// Sign signs the encoded payload. The payload is passed as is, implementations must hash payloads longer than 256
+	// bytes with blake2b-256 and sign the hash instead, as the runtime verifies the signature against that hash.
+	Sign(payload []byte) (MultiSignature, error)
+}
+
+type keyPairSigner struct {
+	keyPair signature.KeyPair
+}
+
+// NewSigner creates a Signer of any signature scheme with the key pair kp
+func NewSigner(kp signature.KeyPair) Signer {
+	return keyPairSigner{keyPair: kp}
+}
+
+// NewSignerFromKeystore creates a Signer with the key pair of the polkadot-js keystore ks, decrypted with password
+func NewSignerFromKeystore(ks signature.Keystore, password string) (Signer, error) {
+	kp, err := ks.Decrypt(password)
+	if err != nil {
+		return nil, err
+	}
+	return NewSigner(kp), nil
+}
+
+func (s keyPairSigner) PublicKey() []byte {
+	return s.keyPair.PublicKey()
+}
+
+func (s keyPairSigner) MultiAddress() MultiAddress {
+	return NewMultiAddressFromAccountID(s.keyPair.AccountID())
+}
+
+func (s keyPairSigner) Sign(payload []byte) (MultiSignature, error) {
+	sig, err := s.keyPair.Sign(payload)
+	if err != nil {
+		return MultiSignature{}, err
+	}
+
+	switch s.keyPair.Scheme {
+	case signature.Ed25519:
+		return MultiSignature{IsEd25519: true, AsEd25519: NewSignature(sig)}, nil
+	case signature.Sr25519:
+		return MultiSignature{IsSr25519: true, AsSr25519: NewSignature(sig)}, nil
+	case signature.Ecdsa:
+		return MultiSignature{IsEcdsa: true, AsEcdsa: NewBytes(sig)}, nil
+	default:
+		return MultiSignature{}, fmt.Errorf("unsupported signature scheme %v", s.keyPair.Scheme)
+	}
+}
+
+type keyringPairSigner struct {
+	keyringPair signature.KeyringPair
+}
+
+// NewKeyringPairSigner creates an sr25519 Signer that signs with the private key under the URI of the keyring pair kp
+func NewKeyringPairSigner(kp signature.KeyringPair) Signer {
+	return keyringPairSigner{keyringPair: kp}
+}
+
+func (s keyringPairSigner) PublicKey() []byte {
+	return s.keyringPair.PublicKey
+}
+
+func (s keyringPairSigner) MultiAddress() MultiAddress {
+	return NewMultiAddressFromAccountID(s.keyringPair.PublicKey)
+}
+
+func (s keyringPairSigner) Sign(payload []byte) (MultiSignature, error) {
+	sig, err := signature.Sign(payload, s.keyringPair.URI)
+	if err != nil {
+		return MultiSignature{}, err
+	}
+	return MultiSignature{IsSr25519: true, AsSr25519: NewSignature(sig)}, nil
+}
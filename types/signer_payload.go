@@ -0,0 +1,208 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// DefaultSignedExtensions are the signed extensions of a Substrate node template runtime, listed in the
+// signedExtensions field of SignerPayloadJSON
+var DefaultSignedExtensions = []string{
+	"CheckSpecVersion",
+	"CheckTxVersion",
+	"CheckGenesis",
+	"CheckMortality",
+	"CheckNonce",
+	"CheckWeight",
+	"ChargeTransactionPayment",
+}
+
+// SignerPayloadJSON is the signing payload of an extrinsic in the JSON format of polkadot-js, as handed to signers
+// such as browser extensions. Numbers are hex encoded big endian with the width of their type, the era and the method
+// are hex encoded SCALE.
+type SignerPayloadJSON struct {
+	Address            string   `json:"address"`
+	BlockHash          string   `json:"blockHash"`
+	BlockNumber        string   `json:"blockNumber"`
+	Era                string   `json:"era"`
+	GenesisHash        string   `json:"genesisHash"`
+	Method             string   `json:"method"`
+	Nonce              string   `json:"nonce"`
+	SignedExtensions   []string `json:"signedExtensions"`
+	SpecVersion        string   `json:"specVersion"`
+	Tip                string   `json:"tip"`
+	TransactionVersion string   `json:"transactionVersion"`
+	Version            int      `json:"version"`
+}
+
+// NewSignerPayloadJSON returns the payload p, signed by the account with the SS58 address and with the era starting at
+// blockNumber, in the JSON format of polkadot-js. The signed extensions are set to DefaultSignedExtensions.
+func NewSignerPayloadJSON(address string, blockNumber BlockNumber, p ExtrinsicPayloadV4) (SignerPayloadJSON, error) {
+	extensions := make([]string, len(DefaultSignedExtensions))
+	copy(extensions, DefaultSignedExtensions)
+	return newSignerPayloadJSON(address, blockNumber, p, extensions)
+}
+
+// NewSignerPayloadJSONWithMetadata returns the payload p like NewSignerPayloadJSON, with the signed extensions listed
+// in the extrinsic metadata of V14 and above
+func NewSignerPayloadJSONWithMetadata(meta *Metadata, address string, blockNumber BlockNumber,
+	p ExtrinsicPayloadV4) (SignerPayloadJSON, error) {
+	var signedExtensions []SignedExtensionMetadataV14
+	switch meta.Version {
+	case 14:
+		signedExtensions = meta.AsMetadataV14.Extrinsic.SignedExtensions
+	case 15:
+		signedExtensions = meta.AsMetadataV15.Extrinsic.SignedExtensions
+	default:
+		return SignerPayloadJSON{}, fmt.Errorf("signed extensions are not supported for metadata version %v",
+			meta.Version)
+	}
+
+	extensions := make([]string, len(signedExtensions))
+	for i, e := range signedExtensions {
+		extensions[i] = string(e.Identifier)
+	}
+	return newSignerPayloadJSON(address, blockNumber, p, extensions)
+}
+
+func newSignerPayloadJSON(address string, blockNumber BlockNumber, p ExtrinsicPayloadV4,
+	extensions []string) (SignerPayloadJSON, error) {
+	era, err := EncodeToHexString(p.Era)
+	if err != nil {
+		return SignerPayloadJSON{}, err
+	}
+
+	nonce, err := hexEncodeUint((*big.Int)(&p.Nonce), 4)
+	if err != nil {
+		return SignerPayloadJSON{}, fmt.Errorf("invalid nonce: %v", err)
+	}
+
+	tip, err := hexEncodeUint((*big.Int)(&p.Tip), 16)
+	if err != nil {
+		return SignerPayloadJSON{}, fmt.Errorf("invalid tip: %v", err)
+	}
+
+	return SignerPayloadJSON{
+		Address:            address,
+		BlockHash:          p.BlockHash.Hex(),
+		BlockNumber:        fmt.Sprintf("%#08x", uint32(blockNumber)),
+		Era:                era,
+		GenesisHash:        p.GenesisHash.Hex(),
+		Method:             HexEncodeToString(p.Method),
+		Nonce:              nonce,
+		SignedExtensions:   extensions,
+		SpecVersion:        fmt.Sprintf("%#08x", uint32(p.SpecVersion)),
+		Tip:                tip,
+		TransactionVersion: fmt.Sprintf("%#08x", uint32(p.TransactionVersion)),
+		Version:            int(ExtrinsicVersion4),
+	}, nil
+}
+
+// SignatureOptions returns the signature options of the payload
+func (p SignerPayloadJSON) SignatureOptions() (SignatureOptions, error) {
+	if p.Version != int(ExtrinsicVersion4) {
+		return SignatureOptions{}, fmt.Errorf("unsupported extrinsic version: %v", p.Version)
+	}
+
+	var o SignatureOptions
+	err := DecodeFromHexString(p.Era, &o.Era)
+	if err != nil {
+		return SignatureOptions{}, fmt.Errorf("invalid era: %v", err)
+	}
+
+	nonce, err := hexDecodeUint(p.Nonce)
+	if err != nil {
+		return SignatureOptions{}, fmt.Errorf("invalid nonce: %v", err)
+	}
+	o.Nonce = NewUCompact(nonce)
+
+	tip, err := hexDecodeUint(p.Tip)
+	if err != nil {
+		return SignatureOptions{}, fmt.Errorf("invalid tip: %v", err)
+	}
+	o.Tip = NewUCompact(tip)
+
+	specVersion, err := hexDecodeU32(p.SpecVersion)
+	if err != nil {
+		return SignatureOptions{}, fmt.Errorf("invalid spec version: %v", err)
+	}
+	o.SpecVersion = specVersion
+
+	transactionVersion, err := hexDecodeU32(p.TransactionVersion)
+	if err != nil {
+		return SignatureOptions{}, fmt.Errorf("invalid transaction version: %v", err)
+	}
+	o.TransactionVersion = transactionVersion
+
+	o.GenesisHash, err = NewHashFromHexString(p.GenesisHash)
+	if err != nil {
+		return SignatureOptions{}, fmt.Errorf("invalid genesis hash: %v", err)
+	}
+
+	o.BlockHash, err = NewHashFromHexString(p.BlockHash)
+	if err != nil {
+		return SignatureOptions{}, fmt.Errorf("invalid block hash: %v", err)
+	}
+
+	return o, nil
+}
+
+// ExtrinsicPayloadV4 returns the payload to sign, the inverse of NewSignerPayloadJSON
+func (p SignerPayloadJSON) ExtrinsicPayloadV4() (ExtrinsicPayloadV4, error) {
+	o, err := p.SignatureOptions()
+	if err != nil {
+		return ExtrinsicPayloadV4{}, err
+	}
+
+	method, err := HexDecodeString(p.Method)
+	if err != nil {
+		return ExtrinsicPayloadV4{}, fmt.Errorf("invalid method: %v", err)
+	}
+
+	return ExtrinsicPayloadV4{
+		ExtrinsicPayloadV3: ExtrinsicPayloadV3{
+			Method:      method,
+			Era:         o.era(),
+			Nonce:       o.Nonce,
+			Tip:         o.Tip,
+			SpecVersion: o.SpecVersion,
+			GenesisHash: o.GenesisHash,
+			BlockHash:   o.BlockHash,
+		},
+		TransactionVersion: o.TransactionVersion,
+	}, nil
+}
+
+// hexEncodeUint encodes v as a big endian hex string of size bytes
+func hexEncodeUint(v *big.Int, size int) (string, error) {
+	if v.Sign() < 0 || v.BitLen() > size*8 {
+		return "", fmt.Errorf("value %v does not fit into %v bytes", v, size)
+	}
+	return HexEncodeToString(v.FillBytes(make([]byte, size))), nil
+}
+
+// hexDecodeUint decodes a big endian hex string of any width
+func hexDecodeUint(s string) (*big.Int, error) {
+	s = strings.TrimPrefix(s, "0x")
+	if s == "" {
+		return big.NewInt(0), nil
+	}
+
+	v, ok := new(big.Int).SetString(s, 16)
+	if !ok || v.Sign() < 0 {
+		return nil, fmt.Errorf("invalid hex number %v", s)
+	}
+	return v, nil
+}
+
+func hexDecodeU32(s string) (U32, error) {
+	v, err := hexDecodeUint(s)
+	if err != nil {
+		return 0, err
+	}
+	if !v.IsUint64() || v.Uint64() > 0xffffffff {
+		return 0, fmt.Errorf("value %v does not fit into 4 bytes", v)
+	}
+	return U32(v.Uint64()), nil
+}
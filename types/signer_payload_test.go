@@ -0,0 +1,139 @@
+package types_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/signature"
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignerPayloadJSON(t *testing.T) {
+	meta := DecodedMetadataV14Example()
+	c, err := NewCall(meta, "System.remark", NewBytes([]byte("hello")))
+	assert.NoError(t, err)
+
+	o := testSignatureOptions
+	payload, err := NewExtrinsic(c).Payload(o)
+	assert.NoError(t, err)
+
+	p, err := NewSignerPayloadJSON(signature.TestKeyringPairAlice.Address, 1000, payload)
+	assert.NoError(t, err)
+	assert.Equal(t, SignerPayloadJSON{
+		Address:            "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY",
+		BlockHash:          "0x0405060000000000000000000000000000000000000000000000000000000000",
+		BlockNumber:        "0x000003e8",
+		Era:                "0x9500",
+		GenesisHash:        "0x0102030000000000000000000000000000000000000000000000000000000000",
+		Method:             "0x00011468656c6c6f",
+		Nonce:              "0x00000003",
+		SignedExtensions:   DefaultSignedExtensions,
+		SpecVersion:        "0x0000238c",
+		Tip:                "0x0000000000000000000000000000000a",
+		TransactionVersion: "0x00000007",
+		Version:            4,
+	}, p)
+
+	bz, err := json.Marshal(p)
+	assert.NoError(t, err)
+	var decoded SignerPayloadJSON
+	err = json.Unmarshal(bz, &decoded)
+	assert.NoError(t, err)
+
+	decodedOptions, err := decoded.SignatureOptions()
+	assert.NoError(t, err)
+	assert.Equal(t, o, decodedOptions)
+	decodedPayload, err := decoded.ExtrinsicPayloadV4()
+	assert.NoError(t, err)
+	assert.Equal(t, payload, decodedPayload)
+
+	// numbers of any width are accepted
+	decoded.Nonce = "0x3"
+	decoded.SpecVersion = "0x238c"
+	decodedOptions, err = decoded.SignatureOptions()
+	assert.NoError(t, err)
+	assert.Equal(t, o, decodedOptions)
+
+	decoded.SpecVersion = "0x0100000000"
+	_, err = decoded.SignatureOptions()
+	assert.EqualError(t, err, "invalid spec version: value 4294967296 does not fit into 4 bytes")
+
+	decoded.Version = 3
+	_, err = decoded.ExtrinsicPayloadV4()
+	assert.EqualError(t, err, "unsupported extrinsic version: 3")
+
+	payload.Nonce = NewUCompactFromUInt(1 << 32)
+	_, err = NewSignerPayloadJSON(signature.TestKeyringPairAlice.Address, 1000, payload)
+	assert.EqualError(t, err, "invalid nonce: value 4294967296 does not fit into 4 bytes")
+}
+
+func TestSignerPayloadJSONWithMetadata(t *testing.T) {
+	meta := DecodedMetadataV14Example()
+	c, err := NewCall(meta, "System.remark", NewBytes([]byte("hello")))
+	assert.NoError(t, err)
+	payload, err := NewExtrinsic(c).Payload(testSignatureOptions)
+	assert.NoError(t, err)
+
+	expected := []string{"CheckSpecVersion", "CheckTxVersion", "CheckGenesis", "CheckMortality", "CheckNonce",
+		"CheckWeight", "ChargeTransactionPayment", "PrevalidateAttests"}
+	for _, m := range []*Metadata{meta, metadataV15FromV14(t)} {
+		p, err := NewSignerPayloadJSONWithMetadata(m, signature.TestKeyringPairAlice.Address, 1000, payload)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, p.SignedExtensions)
+
+		withoutMetadata, err := NewSignerPayloadJSON(signature.TestKeyringPairAlice.Address, 1000, payload)
+		assert.NoError(t, err)
+		withoutMetadata.SignedExtensions = expected
+		assert.Equal(t, withoutMetadata, p)
+	}
+
+	_, err = NewSignerPayloadJSONWithMetadata(ExamplaryMetadataV13, signature.TestKeyringPairAlice.Address, 1000,
+		payload)
+	assert.EqualError(t, err, "signed extensions are not supported for metadata version 13")
+}
+
+func TestExtrinsic_AddSignature(t *testing.T) {
+	meta := DecodedMetadataV14Example()
+	c, err := NewCall(meta, "System.remark", NewBytes([]byte("hello")))
+	assert.NoError(t, err)
+
+	kp, err := signature.KeyPairFromSecret(signature.Ed25519, "//Alice")
+	assert.NoError(t, err)
+	signer := NewSigner(kp)
+	o := testSignatureOptions
+
+	// the payload is exported, signed offline and the signature added to the unsigned extrinsic
+	ext := NewExtrinsic(c)
+	payload, err := ext.Payload(o)
+	assert.NoError(t, err)
+	p, err := NewSignerPayloadJSON(signature.TestKeyringPairAlice.Address, 1000, payload)
+	assert.NoError(t, err)
+
+	offline, err := p.ExtrinsicPayloadV4()
+	assert.NoError(t, err)
+	sig, err := offline.SignWithSigner(signer)
+	assert.NoError(t, err)
+
+	err = ext.AddSignature(signer.MultiAddress(), sig, o)
+	assert.NoError(t, err)
+	assert.True(t, ext.IsSigned())
+
+	signed := NewExtrinsic(c)
+	err = signed.SignWithSigner(signer, o)
+	assert.NoError(t, err)
+	assert.Equal(t, signed, ext)
+
+	dynamicPayload, err := NewExtrinsic(c).PayloadWithMetadata(meta, o)
+	assert.NoError(t, err)
+	sig, err = dynamicPayload.SignWithSigner(signer)
+	assert.NoError(t, err)
+	ext = NewExtrinsic(c)
+	err = ext.AddSignatureWithMetadata(meta, signer.MultiAddress(), sig, o)
+	assert.NoError(t, err)
+
+	signed = NewExtrinsic(c)
+	err = signed.SignWithMetadataAndSigner(meta, signer, o)
+	assert.NoError(t, err)
+	assert.Equal(t, signed, ext)
+}
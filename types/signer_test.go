@@ -0,0 +1,213 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/Phala-Network/go-substrate-rpc-client/v3/signature"
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/vedhavyas/go-subkey"
+	"github.com/vedhavyas/go-subkey/ecdsa"
+	"github.com/vedhavyas/go-subkey/ed25519"
+	"github.com/vedhavyas/go-subkey/sr25519"
+)
+
+func TestExtrinsic_SignWithSigner(t *testing.T) {
+	c, err := NewCall(ExamplaryMetadataV4, "balances.transfer",
+		NewAddressFromAccountID(MustHexDecodeString("0x8eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f26a48")),
+		NewUCompactFromUInt(6969))
+	assert.NoError(t, err)
+
+	o := SignatureOptions{
+		BlockHash:          NewHash(MustHexDecodeString("0xec7afaf1cca720ce88c1d1b689d81f0583cc15a97d621cf046dd9abf605ef22f")),
+		GenesisHash:        NewHash(MustHexDecodeString("0xdcd1346701ca8396496e52aa2785b1748deb6db09551b72159dcb3e08991025b")),
+		Nonce:              NewUCompactFromUInt(1),
+		SpecVersion:        123,
+		Tip:                NewUCompactFromUInt(2),
+		TransactionVersion: 1,
+	}
+
+	for scheme, subkeyScheme := range map[signature.Scheme]subkey.Scheme{
+		signature.Ed25519: ed25519.Scheme{},
+		signature.Sr25519: sr25519.Scheme{},
+		signature.Ecdsa:   ecdsa.Scheme{},
+	} {
+		kp, err := signature.KeyPairFromSecret(scheme, "//Alice")
+		assert.NoError(t, err)
+		signer := NewSigner(kp)
+		assert.Equal(t, kp.PublicKey(), signer.PublicKey())
+
+		ext := NewExtrinsic(c)
+		err = ext.SignWithSigner(signer, o)
+		assert.NoError(t, err)
+		assert.True(t, ext.IsSigned())
+		assert.Equal(t, NewMultiAddressFromAccountID(kp.AccountID()), ext.Signature.Signer)
+
+		enc, err := EncodeToHexString(ext)
+		assert.NoError(t, err)
+		var dec Extrinsic
+		err = DecodeFromHexString(enc, &dec)
+		assert.NoError(t, err)
+		assert.Equal(t, ext, dec)
+
+		mb, err := EncodeToBytes(ext.Method)
+		assert.NoError(t, err)
+		payload, err := EncodeToBytes(ExtrinsicPayloadV4{
+			ExtrinsicPayloadV3: ExtrinsicPayloadV3{Method: mb, Era: ExtrinsicEra{IsImmortalEra: true}, Nonce: o.Nonce,
+				Tip: o.Tip, SpecVersion: o.SpecVersion, GenesisHash: o.GenesisHash, BlockHash: o.BlockHash},
+			TransactionVersion: o.TransactionVersion,
+		})
+		assert.NoError(t, err)
+
+		verifier, err := subkey.DeriveKeyPair(subkeyScheme, "//Alice")
+		assert.NoError(t, err)
+		sig := dec.Signature.Signature
+		switch scheme {
+		case signature.Ed25519:
+			assert.True(t, sig.IsEd25519)
+			assert.True(t, verifier.Verify(payload, sig.AsEd25519[:]))
+		case signature.Sr25519:
+			assert.True(t, sig.IsSr25519)
+			assert.True(t, verifier.Verify(payload, sig.AsSr25519[:]))
+		case signature.Ecdsa:
+			assert.True(t, sig.IsEcdsa)
+			assert.True(t, verifier.Verify(payload, sig.AsEcdsa))
+		}
+	}
+}
+
+func TestNewKeyringPairSigner(t *testing.T) {
+	signer := NewKeyringPairSigner(signature.TestKeyringPairAlice)
+	assert.Equal(t, signature.TestKeyringPairAlice.PublicKey, signer.PublicKey())
+	assert.Equal(t, NewMultiAddressFromAccountID(signature.TestKeyringPairAlice.PublicKey), signer.MultiAddress())
+
+	sig, err := signer.Sign([]byte("hello"))
+	assert.NoError(t, err)
+	assert.True(t, sig.IsSr25519)
+	ok, err := signature.Verify([]byte("hello"), sig.AsSr25519[:], signature.TestKeyringPairAlice.URI)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = NewSigner(signature.KeyPair{}).Sign([]byte("hello"))
+	assert.Error(t, err)
+}
+
+func TestExtrinsic_VerifySignature(t *testing.T) {
+	meta := DecodedMetadataV14Example()
+	c, err := NewCall(meta, "System.remark", NewBytes([]byte("hello")))
+	assert.NoError(t, err)
+
+	o := testSignatureOptions
+	for _, scheme := range []signature.Scheme{signature.Ed25519, signature.Sr25519, signature.Ecdsa} {
+		kp, err := signature.KeyPairFromSecret(scheme, "//Alice")
+		assert.NoError(t, err)
+
+		ext := NewExtrinsic(c)
+		_, err = ext.VerifySignature(o)
+		assert.EqualError(t, err, "extrinsic is not signed")
+
+		err = ext.SignWithSigner(NewSigner(kp), o)
+		assert.NoError(t, err)
+
+		enc, err := EncodeToHexString(ext)
+		assert.NoError(t, err)
+		var dec Extrinsic
+		err = DecodeFromHexString(enc, &dec)
+		assert.NoError(t, err)
+
+		// the era, nonce and tip are taken from the signature
+		ok, err := dec.VerifySignature(SignatureOptions{
+			SpecVersion:        o.SpecVersion,
+			GenesisHash:        o.GenesisHash,
+			BlockHash:          o.BlockHash,
+			TransactionVersion: o.TransactionVersion,
+		})
+		assert.NoError(t, err)
+		assert.True(t, ok, scheme.String())
+
+		other := o
+		other.SpecVersion++
+		ok, err = dec.VerifySignature(other)
+		assert.NoError(t, err)
+		assert.False(t, ok, scheme.String())
+	}
+
+	ext := NewExtrinsic(c)
+	err = ext.Sign(signature.TestKeyringPairAlice, o)
+	assert.NoError(t, err)
+	ok, err := ext.VerifySignature(o)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ext.Signature.Signer = MultiAddress{IsIndex: true, AsIndex: 1}
+	_, err = ext.VerifySignature(o)
+	assert.EqualError(t, err, "unable to verify the signature of a signer without an account id")
+}
+
+func TestExtrinsic_VerifySignatureWithMetadata(t *testing.T) {
+	meta := DecodedMetadataV14Example()
+	c, err := NewCall(meta, "System.remark", NewBytes([]byte("hello")))
+	assert.NoError(t, err)
+
+	// asset ids are only part of the extra data of ChargeAssetTxPayment, 32 is (), 4 is u32
+	unit, u32 := NewSi1LookupTypeIDFromUInt(32), NewSi1LookupTypeIDFromUInt(4)
+	meta.AsMetadataV14.Extrinsic.SignedExtensions = []SignedExtensionMetadataV14{
+		{Identifier: "CheckSpecVersion", Type: unit, AdditionalSigned: u32},
+		{Identifier: "ChargeAssetTxPayment", Type: u32, AdditionalSigned: unit},
+	}
+	o := testSignatureOptions
+	o.AssetID = U32(1984)
+
+	for _, scheme := range []signature.Scheme{signature.Ed25519, signature.Sr25519, signature.Ecdsa} {
+		kp, err := signature.KeyPairFromSecret(scheme, "//Alice")
+		assert.NoError(t, err)
+
+		ext := NewExtrinsic(c)
+		err = ext.SignWithMetadataAndSigner(meta, NewSigner(kp), o)
+		assert.NoError(t, err)
+
+		ok, err := ext.VerifySignatureWithMetadata(meta, o)
+		assert.NoError(t, err)
+		assert.True(t, ok, scheme.String())
+
+		// the legacy payload can't verify the signature
+		_, err = ext.VerifySignature(o)
+		assert.EqualError(t, err, "the signature has the extra data of signed extensions, verify it with "+
+			"VerifySignatureWithMetadata")
+
+		// the asset id is read from the extra data of the signature
+		other := o
+		other.AssetID = nil
+		ok, err = ext.VerifySignatureWithMetadata(meta, other)
+		assert.NoError(t, err)
+		assert.True(t, ok, scheme.String())
+
+		other.SpecVersion++
+		ok, err = ext.VerifySignatureWithMetadata(meta, other)
+		assert.NoError(t, err)
+		assert.False(t, ok, scheme.String())
+	}
+
+	ext := NewExtrinsic(c)
+	err = ext.Sign(signature.TestKeyringPairAlice, o)
+	assert.NoError(t, err)
+	_, err = ext.VerifySignatureWithMetadata(meta, o)
+	assert.EqualError(t, err, "the signature has no extra data of signed extensions, verify it with VerifySignature")
+}
+
+func TestNewSignerFromKeystore(t *testing.T) {
+	kp, err := signature.KeyPairFromSecret(signature.Ed25519, "//Alice")
+	assert.NoError(t, err)
+	ks, err := signature.EncryptKeyPair(kp, "secret", 42)
+	assert.NoError(t, err)
+
+	signer, err := NewSignerFromKeystore(ks, "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, NewMultiAddressFromAccountID(kp.AccountID()), signer.MultiAddress())
+	sig, err := signer.Sign([]byte("hello"))
+	assert.NoError(t, err)
+	assert.True(t, sig.IsEd25519)
+
+	_, err = NewSignerFromKeystore(ks, "wrong")
+	assert.EqualError(t, err, "unable to decrypt keystore, the password is wrong")
+}
@@ -17,6 +17,7 @@
 package types
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 
@@ -168,3 +169,149 @@ func createKey(meta *Metadata, method, prefix string, stringKey, arg []byte, ent
 func createPrefixedKey(method, prefix string) []byte {
 	return append(xxhash.New128([]byte(prefix)).Sum(nil), xxhash.New128([]byte(method)).Sum(nil)...)
 }
+
+// DecodedStorageKey is a StorageKey that has been resolved to the storage item it belongs to
+type DecodedStorageKey struct {
+	Prefix Text
+	Method Text
+	// Keys are the keys of a map, one per hasher of the map. Keys of partial map keys, e.g. of a key prefix used to
+	// iterate a double map, are limited to the keys that are part of the StorageKey.
+	Keys []DecodedStorageMapKey
+}
+
+// DecodedStorageMapKey is the part of a StorageKey that belongs to a single key of a map
+type DecodedStorageMapKey struct {
+	Hasher StorageHasherV10
+	// Hash is the hash of the key, not including the key appended by the Blake2_128Concat and Twox64Concat hashers
+	Hash []byte
+	// HasValue is true if the key can be restored from the StorageKey, that is for the Blake2_128Concat, Twox64Concat
+	// and Identity hashers
+	HasValue bool
+	Value    DynamicValue
+}
+
+// DecodeStorageKey is the inverse of CreateStorageKey. It resolves key to its prefix and method by the twox128 hashes
+// at the start of the key and decodes the keys of maps that are stored in the clear. The metadata must be of version
+// 14 or above.
+func DecodeStorageKey(meta *Metadata, key StorageKey) (DecodedStorageKey, error) {
+	switch meta.Version {
+	case 14:
+		return decodeStorageKey(meta.AsMetadataV14.lookup(), meta.AsMetadataV14.Pallets, key)
+	case 15:
+		return decodeStorageKey(meta.AsMetadataV15.lookup(), meta.AsMetadataV15.palletsV14(), key)
+	default:
+		return DecodedStorageKey{}, fmt.Errorf("decoding of storage keys is not supported for metadata version %v",
+			meta.Version)
+	}
+}
+
+func decodeStorageKey(lookup map[int64]*Si1Type, pallets []PalletMetadataV14, key StorageKey) (DecodedStorageKey,
+	error) {
+	if len(key) < 32 {
+		return DecodedStorageKey{}, fmt.Errorf("storage key %#x is too short to contain a prefix and method", key)
+	}
+
+	for _, mod := range pallets {
+		if !mod.HasStorage {
+			continue
+		}
+		prefix := xxhash.New128([]byte(mod.Storage.Prefix)).Sum(nil)
+		if !bytes.Equal(prefix, key[:16]) {
+			continue
+		}
+
+		for _, item := range mod.Storage.Items {
+			method := xxhash.New128([]byte(item.Name)).Sum(nil)
+			if !bytes.Equal(method, key[16:32]) {
+				continue
+			}
+
+			decoded := DecodedStorageKey{Prefix: mod.Storage.Prefix, Method: item.Name}
+			if !item.Type.IsMap {
+				if len(key) > 32 {
+					return DecodedStorageKey{}, fmt.Errorf("storage key of plain value %v.%v has %v extra bytes",
+						mod.Storage.Prefix, item.Name, len(key)-32)
+				}
+				return decoded, nil
+			}
+
+			keys, err := decodeStorageMapKeys(lookup, key[32:], item.Type.AsMap)
+			if err != nil {
+				return DecodedStorageKey{}, fmt.Errorf("unable to decode keys of %v.%v: %v", mod.Storage.Prefix,
+					item.Name, err)
+			}
+			decoded.Keys = keys
+			return decoded, nil
+		}
+		return DecodedStorageKey{}, fmt.Errorf("method of storage key %#x not found within module %v", key,
+			mod.Storage.Prefix)
+	}
+	return DecodedStorageKey{}, fmt.Errorf("module of storage key %#x not found in metadata", key)
+}
+
+// decodeStorageMapKeys decodes the hashed keys bz of a map. Maps with several hashers have a tuple key type, with one
+// element per hasher.
+func decodeStorageMapKeys(lookup map[int64]*Si1Type, bz []byte, mapType MapTypeV14) ([]DecodedStorageMapKey, error) {
+	keyTypes := []Si1LookupTypeID{mapType.Key}
+	if len(mapType.Hashers) > 1 {
+		typ, ok := lookup[mapType.Key.Int64()]
+		if !ok {
+			return nil, fmt.Errorf("type %v not found in the portable registry", mapType.Key.Int64())
+		}
+		if !typ.Def.IsTuple || len(typ.Def.Tuple) != len(mapType.Hashers) {
+			return nil, fmt.Errorf("expected key type %v to be a tuple of %v elements", mapType.Key.Int64(),
+				len(mapType.Hashers))
+		}
+		keyTypes = typ.Def.Tuple
+	}
+	return decodeStorageHashedKeys(lookup, bz, mapType.Hashers, keyTypes)
+}
+
+// decodeStorageHashedKeys decodes the hashed keys bz of a map with the given hashers and key types, one per hasher
+func decodeStorageHashedKeys(lookup map[int64]*Si1Type, bz []byte, hashers []StorageHasherV10,
+	keyTypes []Si1LookupTypeID) ([]DecodedStorageMapKey, error) {
+	reader := bytes.NewReader(bz)
+	var keys []DecodedStorageMapKey
+	for i, hasher := range hashers {
+		if reader.Len() == 0 {
+			break
+		}
+
+		hashLen, hasValue := storageHasherLen(hasher)
+		k := DecodedStorageMapKey{Hasher: hasher, Hash: make([]byte, hashLen), HasValue: hasValue}
+		_, err := io.ReadFull(reader, k.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read hash of key %v: %v", i, err)
+		}
+
+		if hasValue {
+			k.Value, err = DecodeDynamicValue(*scale.NewDecoder(reader), lookup, keyTypes[i])
+			if err != nil {
+				return nil, fmt.Errorf("unable to decode key %v: %v", i, err)
+			}
+		}
+		keys = append(keys, k)
+	}
+
+	if reader.Len() > 0 {
+		return nil, fmt.Errorf("decoded all keys but %v bytes remain", reader.Len())
+	}
+	return keys, nil
+}
+
+// storageHasherLen returns the length of the hash produced by the hasher and whether the hashed key is appended to
+// the hash
+func storageHasherLen(hasher StorageHasherV10) (int, bool) {
+	switch {
+	case hasher.IsBlake2_128, hasher.IsTwox128:
+		return 16, false
+	case hasher.IsBlake2_256, hasher.IsTwox256:
+		return 32, false
+	case hasher.IsBlake2_128Concat:
+		return 16, true
+	case hasher.IsTwox64Concat:
+		return 8, true
+	default:
+		return 0, true
+	}
+}
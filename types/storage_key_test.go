@@ -428,6 +428,138 @@ func TestStorageKey_Eq(t *testing.T) {
 	})
 }
 
+func TestDecodeStorageKeyPlainV14(t *testing.T) {
+	m := DecodedMetadataV14Example()
+
+	key, err := CreateStorageKey(m, "Timestamp", "Now")
+	assert.NoError(t, err)
+	decoded, err := DecodeStorageKey(m, key)
+	assert.NoError(t, err)
+	assert.Equal(t, DecodedStorageKey{Prefix: "Timestamp", Method: "Now"}, decoded)
+
+	_, err = DecodeStorageKey(m, append(key, 0))
+	assert.EqualError(t, err, "storage key of plain value Timestamp.Now has 1 extra bytes")
+}
+
+func TestDecodeStorageKeyMapV14(t *testing.T) {
+	m := DecodedMetadataV14Example()
+
+	alice := MustHexDecodeString(AlicePubKey)
+	key, err := CreateStorageKey(m, "System", "Account", alice)
+	assert.NoError(t, err)
+
+	decoded, err := DecodeStorageKey(m, key)
+	assert.NoError(t, err)
+	assert.Equal(t, NewText("System"), decoded.Prefix)
+	assert.Equal(t, NewText("Account"), decoded.Method)
+	assert.Len(t, decoded.Keys, 1)
+	assert.True(t, decoded.Keys[0].Hasher.IsBlake2_128Concat)
+	h, err := hash.NewBlake2b128(nil)
+	assert.NoError(t, err)
+	_, err = h.Write(alice)
+	assert.NoError(t, err)
+	assert.Equal(t, h.Sum(nil), decoded.Keys[0].Hash)
+	assert.True(t, decoded.Keys[0].HasValue)
+	account, ok := decoded.Keys[0].Value.Bytes()
+	assert.True(t, ok)
+	assert.Equal(t, alice, account)
+
+	// prefix of a map, e.g. as passed to GetKeys
+	decoded, err = DecodeStorageKey(m, key[:32])
+	assert.NoError(t, err)
+	assert.Equal(t, NewText("Account"), decoded.Method)
+	assert.Nil(t, decoded.Keys)
+
+	// Democracy.Preimages uses the Identity hasher
+	preimage := MustHexDecodeString("0x0102030405060708091011121314151617181920212223242526272829303132")
+	key, err = CreateStorageKey(m, "Democracy", "Preimages", preimage)
+	assert.NoError(t, err)
+	decoded, err = DecodeStorageKey(m, key)
+	assert.NoError(t, err)
+	assert.True(t, decoded.Keys[0].Hasher.IsIdentity)
+	assert.Empty(t, decoded.Keys[0].Hash)
+	b, ok := decoded.Keys[0].Value.Bytes()
+	assert.True(t, ok)
+	assert.Equal(t, preimage, b)
+}
+
+func TestDecodeStorageKeyDoubleMapV14(t *testing.T) {
+	m := DecodedMetadataV14Example()
+
+	era, err := EncodeToBytes(U32(42))
+	assert.NoError(t, err)
+	alice := MustHexDecodeString(AlicePubKey)
+	key, err := CreateStorageKey(m, "Staking", "ErasStakers", era, alice)
+	assert.NoError(t, err)
+
+	decoded, err := DecodeStorageKey(m, key)
+	assert.NoError(t, err)
+	assert.Equal(t, NewText("Staking"), decoded.Prefix)
+	assert.Equal(t, NewText("ErasStakers"), decoded.Method)
+	assert.Len(t, decoded.Keys, 2)
+	assert.True(t, decoded.Keys[0].Hasher.IsTwox64Concat)
+	assert.Equal(t, xxhash.New64(era).Sum(nil), decoded.Keys[0].Hash)
+	assert.Equal(t, U32(42), decoded.Keys[0].Value.AsPrimitive)
+	account, ok := decoded.Keys[1].Value.Bytes()
+	assert.True(t, ok)
+	assert.Equal(t, alice, account)
+
+	// first key only, as used to iterate the accounts of an era
+	decoded, err = DecodeStorageKey(m, key[:32+8+4])
+	assert.NoError(t, err)
+	assert.Len(t, decoded.Keys, 1)
+	assert.Equal(t, U32(42), decoded.Keys[0].Value.AsPrimitive)
+
+	_, err = DecodeStorageKey(m, key[:len(key)-1])
+	assert.Error(t, err)
+
+	// the same runtime in V15
+	v15Decoded, err := DecodeStorageKey(metadataV15FromV14(t), key)
+	assert.NoError(t, err)
+	decoded, err = DecodeStorageKey(m, key)
+	assert.NoError(t, err)
+	assert.Equal(t, decoded, v15Decoded)
+}
+
+func TestDecodeStorageKeyOpaqueHasherV14(t *testing.T) {
+	m := DecodedMetadataV14Example()
+	entry, err := m.AsMetadataV14.FindStorageEntryMetadata("System", "Account")
+	assert.NoError(t, err)
+	// the hashers are shared with the metadata
+	entry.(StorageEntryMetadataV14).Type.AsMap.Hashers[0] = StorageHasherV10{IsBlake2_256: true}
+
+	alice := MustHexDecodeString(AlicePubKey)
+	key, err := CreateStorageKey(m, "System", "Account", alice)
+	assert.NoError(t, err)
+
+	decoded, err := DecodeStorageKey(m, key)
+	assert.NoError(t, err)
+	assert.False(t, decoded.Keys[0].HasValue)
+	h, err := hash.NewBlake2b256(nil)
+	assert.NoError(t, err)
+	_, err = h.Write(alice)
+	assert.NoError(t, err)
+	assert.Equal(t, h.Sum(nil), decoded.Keys[0].Hash)
+}
+
+func TestDecodeStorageKeyFails(t *testing.T) {
+	m := DecodedMetadataV14Example()
+
+	_, err := DecodeStorageKey(m, MustHexDecodeString("0x0102"))
+	assert.EqualError(t, err, "storage key 0x0102 is too short to contain a prefix and method")
+
+	unknown := append(xxhash.New128([]byte("Unknown")).Sum(nil), xxhash.New128([]byte("Now")).Sum(nil)...)
+	_, err = DecodeStorageKey(m, unknown)
+	assert.EqualError(t, err, fmt.Sprintf("module of storage key %#x not found in metadata", unknown))
+
+	unknown = append(xxhash.New128([]byte("Timestamp")).Sum(nil), xxhash.New128([]byte("Unknown")).Sum(nil)...)
+	_, err = DecodeStorageKey(m, unknown)
+	assert.EqualError(t, err, fmt.Sprintf("method of storage key %#x not found within module Timestamp", unknown))
+
+	_, err = DecodeStorageKey(ExamplaryMetadataV13, unknown)
+	assert.EqualError(t, err, "decoding of storage keys is not supported for metadata version 13")
+}
+
 func DecodedMetadataV14Example() *Metadata {
 	var metadata Metadata
 	err := DecodeFromHexString(MetadataV14Data, &metadata)
@@ -0,0 +1,63 @@
+package types
+
+import "fmt"
+
+// StorageValue is a value read from storage with the default of its storage item applied. Keys that are absent from
+// storage have no value for storage items with the Optional modifier, and the default value of the storage item for
+// storage items with the Default modifier.
+type StorageValue struct {
+	// Data is the encoded value, that is the stored data or the default of the storage item if the key is absent
+	Data StorageDataRaw
+	// Modifier is the modifier of the storage item, Optional or Default
+	Modifier StorageFunctionModifierV0
+	// IsStored is true if the key is present in storage
+	IsStored bool
+}
+
+// NewStorageValue applies the modifier and default of the storage item entry to raw, the data read from storage for a
+// key of entry. Raw is empty if the key is absent.
+func NewStorageValue(entry StorageEntryMetadata, raw StorageDataRaw) (StorageValue, error) {
+	var modifier StorageFunctionModifierV0
+	var fallback Bytes
+	switch s := entry.(type) {
+	case StorageFunctionMetadataV4:
+		modifier, fallback = s.Modifier, s.Fallback
+	case StorageFunctionMetadataV5:
+		modifier, fallback = s.Modifier, s.Fallback
+	case StorageFunctionMetadataV10:
+		modifier, fallback = s.Modifier, s.Fallback
+	case StorageFunctionMetadataV13:
+		modifier, fallback = s.Modifier, s.Fallback
+	case StorageEntryMetadataV14:
+		modifier, fallback = s.Modifier, s.Fallback
+	default:
+		return StorageValue{}, fmt.Errorf("unsupported storage entry metadata %T", entry)
+	}
+
+	if len(raw) > 0 {
+		return StorageValue{Data: raw, Modifier: modifier, IsStored: true}, nil
+	}
+	if modifier.IsDefault {
+		return StorageValue{Data: NewStorageDataRaw(fallback), Modifier: modifier}, nil
+	}
+	return StorageValue{Modifier: modifier}, nil
+}
+
+// HasValue returns true if Data holds a value, that is if the key is present in storage or the storage item has the
+// Default modifier
+func (v StorageValue) HasValue() bool {
+	return v.IsStored || v.Modifier.IsDefault
+}
+
+// IsDefault returns true if Data is the default of the storage item because the key is absent from storage
+func (v StorageValue) IsDefault() bool {
+	return !v.IsStored && v.Modifier.IsDefault
+}
+
+// Decode decodes Data into target. Ok is false and target is left untouched if there is no value.
+func (v StorageValue) Decode(target interface{}) (ok bool, err error) {
+	if !v.HasValue() {
+		return false, nil
+	}
+	return true, DecodeFromBytes(v.Data, target)
+}
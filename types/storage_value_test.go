@@ -0,0 +1,64 @@
+package types_test
+
+import (
+	"math/big"
+	"testing"
+
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStorageValue(t *testing.T) {
+	meta := DecodedMetadataV14Example()
+
+	// System.Account has the Default modifier with AccountInfo zero values as fallback
+	entry, err := meta.FindStorageEntryMetadata("System", "Account")
+	assert.NoError(t, err)
+
+	v, err := NewStorageValue(entry, nil)
+	assert.NoError(t, err)
+	assert.False(t, v.IsStored)
+	assert.True(t, v.HasValue())
+	assert.True(t, v.IsDefault())
+	var info AccountInfo
+	ok, err := v.Decode(&info)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, U32(0), info.Providers)
+	assert.Equal(t, NewU128(*big.NewInt(0)), info.Data.Free)
+
+	stored := MustHexDecodeString(
+		"05000000" + // nonce
+			"00000000" + // consumers
+			"01000000" + // providers
+			"e8030000000000000000000000000000" + // free
+			"00000000000000000000000000000000" + // reserved
+			"00000000000000000000000000000000" + // miscFrozen
+			"00000000000000000000000000000000", // feeFrozen
+	)
+	v, err = NewStorageValue(entry, stored)
+	assert.NoError(t, err)
+	assert.True(t, v.IsStored)
+	assert.False(t, v.IsDefault())
+	ok, err = v.Decode(&info)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, U32(5), info.Nonce)
+	assert.Equal(t, NewU128(*big.NewInt(1000)), info.Data.Free)
+}
+
+func TestNewStorageValue_Optional(t *testing.T) {
+	entry, err := ExamplaryMetadataV13.FindStorageEntryMetadata("System", "ExtrinsicCount")
+	assert.NoError(t, err)
+	v, err := NewStorageValue(entry, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, StorageFunctionModifierV0{IsOptional: true}, v.Modifier)
+	assert.False(t, v.HasValue())
+	var count U32
+	ok, err := v.Decode(&count)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = NewStorageValue(nil, nil)
+	assert.EqualError(t, err, "unsupported storage entry metadata <nil>")
+}
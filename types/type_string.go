@@ -0,0 +1,141 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TypeString returns a Rust-like name of the registry type typeID, such as Vec<(AccountId32, Compact<u128>)> or
+// BoundedVec<u8, S>. Types with a path are named by the last segment of their path and their type parameters.
+func (m *MetadataV14) TypeString(typeID Si1LookupTypeID) string {
+	return newTypeStringer(m.lookup(), false).typeString(typeID)
+}
+
+// TypeString returns a Rust-like name of the registry type typeID, see MetadataV14.TypeString
+func (m *MetadataV15) TypeString(typeID Si1LookupTypeID) string {
+	return newTypeStringer(m.lookup(), false).typeString(typeID)
+}
+
+// TypeString returns a Rust-like name of the registry type typeID, see MetadataV14.TypeString
+func (m *Metadata) TypeString(typeID Si1LookupTypeID) (string, error) {
+	switch m.Version {
+	case 14:
+		return m.AsMetadataV14.TypeString(typeID), nil
+	case 15:
+		return m.AsMetadataV15.TypeString(typeID), nil
+	default:
+		return "", fmt.Errorf("the portable registry is not supported for metadata version %v", m.Version)
+	}
+}
+
+// typeStringer renders registry types. Types that are being rendered are tracked, so that recursive types such as
+// the call enum of a runtime are rendered by name where they contain themselves.
+type typeStringer struct {
+	lookup    map[int64]*Si1Type
+	fullPaths bool
+	rendering map[int64]bool
+}
+
+// newTypeStringer returns a typeStringer that names types by the last segment of their path, or by the full path,
+// e.g. sp_core::crypto::AccountId32, if fullPaths is set
+func newTypeStringer(lookup map[int64]*Si1Type, fullPaths bool) *typeStringer {
+	return &typeStringer{lookup: lookup, fullPaths: fullPaths, rendering: map[int64]bool{}}
+}
+
+func (s *typeStringer) typeString(typeID Si1LookupTypeID) string {
+	id := typeID.Int64()
+	typ, ok := s.lookup[id]
+	if !ok {
+		return fmt.Sprintf("type %v", id)
+	}
+	if s.rendering[id] {
+		if len(typ.Path) > 0 {
+			return s.pathName(typ.Path)
+		}
+		return fmt.Sprintf("type %v", id)
+	}
+	s.rendering[id] = true
+	defer delete(s.rendering, id)
+
+	def := typ.Def
+
+	switch {
+	case len(typ.Path) > 0:
+		name := s.pathName(typ.Path)
+		if len(typ.Params) == 0 {
+			return name
+		}
+		params := make([]string, len(typ.Params))
+		for i, p := range typ.Params {
+			if p.HasType {
+				params[i] = s.typeString(p.Type)
+			} else {
+				params[i] = string(p.Name)
+			}
+		}
+		return fmt.Sprintf("%v<%v>", name, strings.Join(params, ", "))
+	case def.IsPrimitive:
+		if name, ok := primitiveNames[def.Primitive.Si0TypeDefPrimitive]; ok {
+			return name
+		}
+		return fmt.Sprintf("type %v", id)
+	case def.IsSequence:
+		return fmt.Sprintf("Vec<%v>", s.typeString(def.Sequence.Type))
+	case def.IsArray:
+		return fmt.Sprintf("[%v; %v]", s.typeString(def.Array.Type), def.Array.Len)
+	case def.IsCompact:
+		return fmt.Sprintf("Compact<%v>", s.typeString(def.Compact.Type))
+	case def.IsTuple:
+		return s.tupleString(def.Tuple)
+	case def.IsBitSequence:
+		return fmt.Sprintf("BitVec<%v, %v>", s.typeString(def.BitSequence.BitStoreType),
+			s.typeString(def.BitSequence.BitOrderType))
+	case def.IsComposite:
+		return s.fieldsString(def.Composite.Fields)
+	case def.IsHistoricMetaCompat:
+		return string(def.HistoricMetaCompat)
+	default:
+		// variants without a path can't be named
+		return fmt.Sprintf("type %v", id)
+	}
+}
+
+func (s *typeStringer) pathName(path Si1Path) string {
+	if !s.fullPaths {
+		return string(path[len(path)-1])
+	}
+	segments := make([]string, len(path))
+	for i, p := range path {
+		segments[i] = string(p)
+	}
+	return strings.Join(segments, "::")
+}
+
+// tupleString renders a tuple like Rust, with a trailing comma if it has a single element
+func (s *typeStringer) tupleString(ids []Si1LookupTypeID) string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = s.typeString(id)
+	}
+	if len(names) == 1 {
+		return fmt.Sprintf("(%v,)", names[0])
+	}
+	return fmt.Sprintf("(%v)", strings.Join(names, ", "))
+}
+
+// fieldsString renders the fields of an anonymous composite as a struct if they are named, or as a tuple otherwise
+func (s *typeStringer) fieldsString(fields []Si1Field) string {
+	if len(fields) == 0 || !fields[0].HasName {
+		ids := make([]Si1LookupTypeID, len(fields))
+		for i, f := range fields {
+			ids[i] = f.Type
+		}
+		return s.tupleString(ids)
+	}
+
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = fmt.Sprintf("%v: %v", f.Name, s.typeString(f.Type))
+	}
+	return fmt.Sprintf("{ %v }", strings.Join(names, ", "))
+}
@@ -0,0 +1,88 @@
+package types_test
+
+import (
+	"testing"
+
+	. "github.com/Phala-Network/go-substrate-rpc-client/v3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataV14_TypeString(t *testing.T) {
+	meta := DecodedMetadataV14Example()
+
+	for id, expected := range map[uint64]string{
+		0:    "AccountId32",
+		1:    "[u8; 32]",
+		4:    "u32",
+		10:   "Vec<u8>",
+		30:   "Option<Vec<u8>>",
+		32:   "()",
+		48:   "Vec<(AccountId32, Exposure<AccountId32, u128>)>",
+		105:  "Vec<(u32, u32)>",
+		130:  "Call",
+		146:  "Call<T, I>",
+		204:  "Vec<Call>",
+		207:  "BoundedVec<(Data, Data), S>",
+		257:  "Vec<(Compact<u32>, (Compact<u16>, Compact<PerU16>), Compact<u16>)>",
+		262:  "Vec<(Compact<u32>, [(Compact<u16>, Compact<PerU16>); 2], Compact<u16>)>",
+		9999: "type 9999",
+	} {
+		assert.Equal(t, expected, meta.AsMetadataV14.TypeString(NewSi1LookupTypeIDFromUInt(id)))
+	}
+
+	s, err := meta.TypeString(NewSi1LookupTypeIDFromUInt(147))
+	assert.NoError(t, err)
+	assert.Equal(t, "MultiAddress<AccountId32, ()>", s)
+
+	s, err = metadataV15FromV14(t).TypeString(NewSi1LookupTypeIDFromUInt(147))
+	assert.NoError(t, err)
+	assert.Equal(t, "MultiAddress<AccountId32, ()>", s)
+
+	_, err = ExamplaryMetadataV13.TypeString(NewSi1LookupTypeIDFromUInt(0))
+	assert.EqualError(t, err, "the portable registry is not supported for metadata version 13")
+}
+
+func TestMetadataV14_TypeString_Anonymous(t *testing.T) {
+	u8, u32 := NewSi1LookupTypeIDFromUInt(0), NewSi1LookupTypeIDFromUInt(1)
+	meta := MetadataV14{Lookup: PortableRegistryV14{Types: []PortableTypeV14{
+		{ID: u8, Type: Si1Type{Def: Si1TypeDef{IsPrimitive: true, Primitive: Si1TypeDefPrimitive{IsU8}}}},
+		{ID: u32, Type: Si1Type{Def: Si1TypeDef{IsPrimitive: true, Primitive: Si1TypeDefPrimitive{IsU32}}}},
+		{ID: NewSi1LookupTypeIDFromUInt(2), Type: Si1Type{Def: Si1TypeDef{IsTuple: true, Tuple: Si1TypeDefTuple{u8}}}},
+		{ID: NewSi1LookupTypeIDFromUInt(3), Type: Si1Type{Def: Si1TypeDef{IsComposite: true, Composite: Si1TypeDefComposite{
+			Fields: []Si1Field{{HasName: true, Name: "a", Type: u8}, {HasName: true, Name: "b", Type: u32}},
+		}}}},
+		{ID: NewSi1LookupTypeIDFromUInt(4), Type: Si1Type{Def: Si1TypeDef{IsComposite: true, Composite: Si1TypeDefComposite{
+			Fields: []Si1Field{{Type: u8}, {Type: u32}},
+		}}}},
+		{ID: NewSi1LookupTypeIDFromUInt(5), Type: Si1Type{Def: Si1TypeDef{IsBitSequence: true,
+			BitSequence: Si1TypeDefBitSequence{BitStoreType: u8, BitOrderType: NewSi1LookupTypeIDFromUInt(6)}}}},
+		{ID: NewSi1LookupTypeIDFromUInt(6), Type: Si1Type{Path: Si1Path{"bitvec", "order", "Lsb0"},
+			Def: Si1TypeDef{IsComposite: true}}},
+	}}}
+
+	assert.Equal(t, "(u8,)", meta.TypeString(NewSi1LookupTypeIDFromUInt(2)))
+	assert.Equal(t, "{ a: u8, b: u32 }", meta.TypeString(NewSi1LookupTypeIDFromUInt(3)))
+	assert.Equal(t, "(u8, u32)", meta.TypeString(NewSi1LookupTypeIDFromUInt(4)))
+	assert.Equal(t, "BitVec<u8, Lsb0>", meta.TypeString(NewSi1LookupTypeIDFromUInt(5)))
+}
+
+func TestMetadataV14_TypeString_Recursive(t *testing.T) {
+	node, children := NewSi1LookupTypeIDFromUInt(0), NewSi1LookupTypeIDFromUInt(1)
+	meta := MetadataV14{Lookup: PortableRegistryV14{Types: []PortableTypeV14{
+		{ID: node, Type: Si1Type{
+			Path:   Si1Path{"tree", "Node"},
+			Params: []Si1TypeParameter{{Name: "C", HasType: true, Type: children}},
+			Def: Si1TypeDef{IsComposite: true, Composite: Si1TypeDefComposite{
+				Fields: []Si1Field{{HasName: true, Name: "children", Type: children}},
+			}},
+		}},
+		{ID: children, Type: Si1Type{Def: Si1TypeDef{IsSequence: true, Sequence: Si1TypeDefSequence{Type: node}}}},
+		{ID: NewSi1LookupTypeIDFromUInt(2), Type: Si1Type{Def: Si1TypeDef{IsSequence: true,
+			Sequence: Si1TypeDefSequence{Type: NewSi1LookupTypeIDFromUInt(2)}}}},
+	}}}
+
+	assert.Equal(t, "Node<Vec<Node>>", meta.TypeString(node))
+	// anonymous types have no name to refer to themselves
+	assert.Equal(t, "Vec<Node<type 1>>", meta.TypeString(children))
+	assert.Equal(t, "Vec<type 2>", meta.TypeString(NewSi1LookupTypeIDFromUInt(2)))
+}